@@ -0,0 +1,355 @@
+// Package bundle implements the portable file format used to export and
+// import recipes.
+//
+// A bundle is a YAML document with a format version, the ingredient catalog
+// and the recipes using it:
+//
+//	version: 1
+//	ingredients:
+//	  - name: Bread Flour
+//	    kind: flour
+//	  - name: Egg
+//	    kind: egg
+//	    piece_weight: 55
+//	recipes:
+//	  - name: Brioche
+//	    ingredients:
+//	      - name: Bread Flour
+//	        unit_category: weight
+//	        percentage: 1
+//	        dependency: total_flour
+//	      - name: Egg
+//	        unit_category: count
+//	        amount: 3 pc
+//	    steps:
+//	      - text: Mix
+//	        ingredients:
+//	          - Bread Flour
+//	          - Egg
+//	      - text: Bulk ferment
+//	        duration: 4–5h
+//	        temperature: 24C
+//
+// Ingredients are referenced by name, ignoring case, so an ingredient used by
+// several recipes is only described once. The ingredients and steps of a
+// recipe are listed in the order they are shown. A step refers to an
+// ingredient of the final dough by name, and to that of another stage by name
+// and stage:
+//
+//	ingredients:
+//	  - Bread Flour
+//	  - name: Water
+//	    stage: Levain
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/simonklee/sourdough/recipe"
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the format version written by this package. Bundles with a
+// newer version are rejected by Read.
+const Version = 1
+
+type Bundle struct {
+	Version     int          `yaml:"version"`
+	Ingredients []Ingredient `yaml:"ingredients"`
+	Recipes     []Recipe     `yaml:"recipes"`
+}
+
+// Ingredient is an ingredient of the catalog. Zero values are unknown, and
+// the defaults of the kind are used instead.
+type Ingredient struct {
+	Name string      `yaml:"name"`
+	Kind recipe.Kind `yaml:"kind,omitempty"`
+
+	// Hydration of a sourdough starter as a ratio of water to flour.
+	Hydration float64 `yaml:"hydration,omitempty"`
+
+	// Density in g/ml and the weight of a single piece in grams.
+	Density     float64 `yaml:"density,omitempty"`
+	PieceWeight float64 `yaml:"piece_weight,omitempty"`
+}
+
+type Recipe struct {
+	Name        string             `yaml:"name"`
+	Notes       string             `yaml:"notes,omitempty"`
+	Ingredients []RecipeIngredient `yaml:"ingredients"`
+	Steps       []Step             `yaml:"steps,omitempty"`
+}
+
+// RecipeIngredient is an ingredient of a recipe, either a baker's percentage
+// of its dependency or a fixed amount such as "10 g".
+type RecipeIngredient struct {
+	Name         string              `yaml:"name"`
+	UnitCategory recipe.UnitCategory `yaml:"unit_category"`
+	Percentage   float64             `yaml:"percentage,omitempty"`
+	Amount       string              `yaml:"amount,omitempty"`
+	Dependency   string              `yaml:"dependency,omitempty"`
+	Stage        string              `yaml:"stage,omitempty"`
+	Group        string              `yaml:"group,omitempty"`
+}
+
+// Step is a step of the method of a recipe. Duration is a duration or a
+// range such as "12–16h" and Ingredients the ingredients of the recipe added
+// in the step.
+type Step struct {
+	Text        string           `yaml:"text"`
+	Duration    string           `yaml:"duration,omitempty"`
+	Temperature string           `yaml:"temperature,omitempty"`
+	Ingredients []StepIngredient `yaml:"ingredients,omitempty"`
+}
+
+// StepIngredient refers to an ingredient of the recipe added in a step. It's
+// written as just the name if the ingredient belongs to the final dough.
+type StepIngredient struct {
+	Name  string `yaml:"name"`
+	Stage string `yaml:"stage,omitempty"`
+}
+
+func (s StepIngredient) MarshalYAML() (any, error) {
+	if s.Stage == recipe.FinalDough {
+		return s.Name, nil
+	}
+	type plain StepIngredient
+	return plain(s), nil
+}
+
+func (s *StepIngredient) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*s = StepIngredient{}
+		return node.Decode(&s.Name)
+	}
+	type plain StepIngredient
+	return node.Decode((*plain)(s))
+}
+
+// New returns a bundle of recipes. The catalog is built from the properties
+// of the recipe ingredients, the first occurrence of a name winning.
+func New(recipes []recipe.Recipe) Bundle {
+	b := Bundle{
+		Version:     Version,
+		Ingredients: []Ingredient{},
+		Recipes:     make([]Recipe, 0, len(recipes)),
+	}
+
+	seen := map[string]bool{}
+	for _, r := range recipes {
+		out := Recipe{
+			Name:        r.Name,
+			Notes:       r.Notes,
+			Ingredients: make([]RecipeIngredient, 0, len(r.Ingredients)),
+		}
+
+		for _, ri := range r.Ingredients {
+			if key := strings.ToLower(ri.Name); !seen[key] {
+				seen[key] = true
+				b.Ingredients = append(b.Ingredients, Ingredient{
+					Name:        ri.Name,
+					Kind:        ri.Kind,
+					Hydration:   ri.Hydration,
+					Density:     ri.Density,
+					PieceWeight: ri.PieceWeight,
+				})
+			}
+
+			ingredient := RecipeIngredient{
+				Name:         ri.Name,
+				UnitCategory: ri.PreferUnitCategory,
+				Dependency:   ri.Dependency,
+				Stage:        ri.Stage,
+				Group:        ri.Group,
+			}
+			if ri.Mode == recipe.ModeAmount {
+				ingredient.Amount = formatAmount(ri.Amount)
+				ingredient.Dependency = ""
+			} else {
+				ingredient.Percentage = ri.Percentage
+			}
+			out.Ingredients = append(out.Ingredients, ingredient)
+		}
+
+		for _, step := range r.Steps {
+			s := Step{Text: step.Text}
+			for _, ingredient := range step.Ingredients {
+				s.Ingredients = append(s.Ingredients, StepIngredient(ingredient))
+			}
+			if !step.Duration.IsZero() {
+				s.Duration = step.Duration.Format()
+			}
+			if step.Temperature != nil {
+				s.Temperature = strconv.FormatFloat(step.Temperature.Value, 'f', -1, 64) + string(step.Temperature.Scale)
+			}
+			out.Steps = append(out.Steps, s)
+		}
+
+		b.Recipes = append(b.Recipes, out)
+	}
+
+	return b
+}
+
+// formatAmount formats an amount without losing precision, e.g. "12.5 g",
+// so it is parsed back by recipe.ParseTuple to the same value.
+func formatAmount(amount recipe.Tuple) string {
+	return strconv.FormatFloat(amount.Value, 'f', -1, 64) + " " + amount.Unit.String()
+}
+
+// RecipeTemplates returns the recipes of the bundle with the properties of
+// their ingredients taken from the catalog. Ingredients missing from the
+// catalog are of unknown kind.
+func (b Bundle) RecipeTemplates() ([]recipe.Recipe, error) {
+	catalog := map[string]Ingredient{}
+	for _, ingredient := range b.Ingredients {
+		if ingredient.Name == "" {
+			return nil, errors.New("ingredient without a name")
+		}
+		catalog[strings.ToLower(ingredient.Name)] = ingredient
+	}
+
+	recipes := make([]recipe.Recipe, 0, len(b.Recipes))
+	for _, r := range b.Recipes {
+		if r.Name == "" {
+			return nil, errors.New("recipe without a name")
+		}
+
+		out := recipe.Recipe{
+			Name:        r.Name,
+			Notes:       r.Notes,
+			Ingredients: make([]recipe.RecipeIngredient, 0, len(r.Ingredients)),
+		}
+		for _, ri := range r.Ingredients {
+			ingredient, err := ri.template(catalog[strings.ToLower(ri.Name)])
+			if err != nil {
+				return nil, fmt.Errorf("recipe %s: %w", r.Name, err)
+			}
+			out.Ingredients = append(out.Ingredients, ingredient)
+		}
+		for _, s := range r.Steps {
+			step, err := s.template()
+			if err != nil {
+				return nil, fmt.Errorf("recipe %s: %w", r.Name, err)
+			}
+			out.Steps = append(out.Steps, step)
+		}
+
+		recipes = append(recipes, out)
+	}
+
+	return recipes, nil
+}
+
+func (ri RecipeIngredient) template(ingredient Ingredient) (recipe.RecipeIngredient, error) {
+	if ri.Name == "" {
+		return recipe.RecipeIngredient{}, errors.New("ingredient without a name")
+	}
+
+	out := recipe.RecipeIngredient{
+		Name:               ri.Name,
+		Kind:               ingredient.Kind,
+		PreferUnitCategory: ri.UnitCategory,
+		Dependency:         ri.Dependency,
+		Stage:              ri.Stage,
+		Group:              ri.Group,
+		Hydration:          ingredient.Hydration,
+		Density:            ingredient.Density,
+		PieceWeight:        ingredient.PieceWeight,
+	}
+	if out.PreferUnitCategory == "" {
+		out.PreferUnitCategory = recipe.UnitCategoryWeight
+	}
+	if _, err := recipe.FromUnitCategory(out.PreferUnitCategory); err != nil {
+		return out, fmt.Errorf("ingredient %s: %w", ri.Name, err)
+	}
+
+	switch {
+	case ri.Amount != "" && ri.Percentage != 0:
+		return out, fmt.Errorf("ingredient %s: percentage and amount are mutually exclusive", ri.Name)
+	case ri.Amount != "":
+		amount, err := recipe.ParseTuple(ri.Amount)
+		if err != nil {
+			return out, fmt.Errorf("ingredient %s: %w", ri.Name, err)
+		}
+		out.Mode = recipe.ModeAmount
+		out.Amount = amount
+		out.Dependency = ""
+	case ri.Percentage > 0:
+		if ri.Dependency == "" {
+			return out, fmt.Errorf("ingredient %s: a percentage requires a dependency", ri.Name)
+		}
+		out.Mode = recipe.ModePercentage
+		out.Percentage = ri.Percentage
+	default:
+		return out, fmt.Errorf("ingredient %s: requires a percentage or an amount", ri.Name)
+	}
+
+	return out, nil
+}
+
+func (s Step) template() (recipe.Step, error) {
+	if s.Text == "" {
+		return recipe.Step{}, errors.New("step without text")
+	}
+
+	out := recipe.Step{Text: s.Text}
+	for _, ingredient := range s.Ingredients {
+		if ingredient.Name == "" {
+			return out, fmt.Errorf("step %s: ingredient without a name", s.Text)
+		}
+		out.Ingredients = append(out.Ingredients, recipe.StepIngredient(ingredient))
+	}
+	if s.Duration != "" {
+		d, err := recipe.ParseDurationRange(s.Duration)
+		if err != nil {
+			return out, fmt.Errorf("step %s: %w", s.Text, err)
+		}
+		out.Duration = d
+	}
+	if s.Temperature != "" {
+		t, err := recipe.ParseTemperature(s.Temperature)
+		if err != nil {
+			return out, fmt.Errorf("step %s: %w", s.Text, err)
+		}
+		out.Temperature = &t
+	}
+	return out, nil
+}
+
+// Read decodes a bundle. Unknown fields and unsupported versions are errors.
+func Read(r io.Reader) (Bundle, error) {
+	var b Bundle
+
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&b); err != nil {
+		if errors.Is(err, io.EOF) {
+			return b, errors.New("empty bundle")
+		}
+		return b, err
+	}
+
+	switch {
+	case b.Version == 0:
+		return b, errors.New("missing bundle version")
+	case b.Version > Version:
+		return b, fmt.Errorf("bundle version %d is newer than this binary supports (%d)", b.Version, Version)
+	}
+
+	return b, nil
+}
+
+// Write encodes a bundle.
+func Write(w io.Writer, b Bundle) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(b); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,240 @@
+package bundle
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/simonklee/sourdough/recipe"
+	"gopkg.in/yaml.v3"
+)
+
+var brioche = recipe.Recipe{
+	Name:  "Brioche",
+	Notes: "Chill overnight.",
+	Ingredients: []recipe.RecipeIngredient{
+		{
+			Name:               "Bread Flour",
+			Kind:               recipe.KindFlour,
+			PreferUnitCategory: recipe.UnitCategoryWeight,
+			Mode:               recipe.ModePercentage,
+			Percentage:         1,
+			Dependency:         recipe.DependencyTotalFlour,
+		},
+		{
+			Name:               "Starter",
+			Kind:               recipe.KindSourdough,
+			PreferUnitCategory: recipe.UnitCategoryWeight,
+			Mode:               recipe.ModePercentage,
+			Percentage:         0.2,
+			Dependency:         recipe.DependencyTotalFlour,
+			Stage:              "Levain",
+			Hydration:          1,
+		},
+		{
+			Name:               "Egg",
+			Kind:               recipe.KindEgg,
+			PreferUnitCategory: recipe.UnitCategoryCount,
+			Mode:               recipe.ModeAmount,
+			Amount:             recipe.UnitPieces.Tuple(3),
+			PieceWeight:        55,
+		},
+		{
+			Name:               "Honey",
+			Kind:               recipe.KindSugar,
+			PreferUnitCategory: recipe.UnitCategoryVolume,
+			Mode:               recipe.ModeAmount,
+			Amount:             recipe.UnitFluidOunces.Tuple(1.5),
+			Group:              "Glaze",
+			Density:            1.42,
+		},
+	},
+	Steps: []recipe.Step{
+		{Text: "Mix", Ingredients: []recipe.StepIngredient{{Name: "Bread Flour"}, {Name: "Starter", Stage: "Levain"}, {Name: "Egg"}}},
+		{
+			Text:        "Bulk ferment",
+			Duration:    recipe.DurationRange{Min: 4 * time.Hour, Max: 5*time.Hour + 30*time.Minute},
+			Temperature: &recipe.Temperature{Value: 24.5, Scale: recipe.Celsius},
+		},
+		{Text: "Bake", Duration: recipe.FixedDuration(25 * time.Minute)},
+	},
+}
+
+var sandwich = recipe.Recipe{
+	Name: "Sandwich Loaf",
+	Ingredients: []recipe.RecipeIngredient{
+		{
+			Name:               "bread flour",
+			Kind:               recipe.KindFlour,
+			PreferUnitCategory: recipe.UnitCategoryWeight,
+			Mode:               recipe.ModePercentage,
+			Percentage:         1,
+			Dependency:         recipe.DependencyTotalFlour,
+		},
+	},
+}
+
+func TestRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, New([]recipe.Recipe{brioche})); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	b, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	got, err := b.RecipeTemplates()
+	if err != nil {
+		t.Fatalf("RecipeTemplates() error = %v", err)
+	}
+
+	if want := []recipe.Recipe{brioche}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RecipeTemplates() got = %+v, want %+v", got, want)
+	}
+}
+
+func TestNewDeduplicatesIngredients(t *testing.T) {
+	b := New([]recipe.Recipe{brioche, sandwich})
+
+	var names []string
+	for _, ingredient := range b.Ingredients {
+		names = append(names, ingredient.Name)
+	}
+
+	want := []string{"Bread Flour", "Starter", "Egg", "Honey"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("New() ingredients = %v, want %v", names, want)
+	}
+
+	recipes, err := b.RecipeTemplates()
+	if err != nil {
+		t.Fatalf("RecipeTemplates() error = %v", err)
+	}
+	if got := recipes[1].Ingredients[0].Kind; got != recipe.KindFlour {
+		t.Errorf("RecipeTemplates() kind of bread flour = %q, want %q", got, recipe.KindFlour)
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			input: "version: 1\nrecipes:\n  - name: Country\n    ingredients: []\n",
+		},
+		{
+			name:    "missing version",
+			input:   "recipes: []\n",
+			wantErr: "missing bundle version",
+		},
+		{
+			name:    "newer version",
+			input:   "version: 2\n",
+			wantErr: "bundle version 2 is newer",
+		},
+		{
+			name:    "unknown field",
+			input:   "version: 1\nrecipez: []\n",
+			wantErr: "field recipez not found",
+		},
+		{
+			name:    "empty",
+			input:   "",
+			wantErr: "empty bundle",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.input))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Read() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Read() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecipeTemplatesErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		ingredient RecipeIngredient
+		wantErr    string
+	}{
+		{
+			name:       "percentage and amount",
+			ingredient: RecipeIngredient{Name: "Salt", Percentage: 0.02, Amount: "10 g", Dependency: "total_flour"},
+			wantErr:    "mutually exclusive",
+		},
+		{
+			name:       "percentage without dependency",
+			ingredient: RecipeIngredient{Name: "Salt", Percentage: 0.02},
+			wantErr:    "requires a dependency",
+		},
+		{
+			name:       "neither",
+			ingredient: RecipeIngredient{Name: "Salt"},
+			wantErr:    "requires a percentage or an amount",
+		},
+		{
+			name:       "invalid amount",
+			ingredient: RecipeIngredient{Name: "Salt", Amount: "a pinch"},
+			wantErr:    "invalid amount",
+		},
+		{
+			name:       "invalid unit category",
+			ingredient: RecipeIngredient{Name: "Salt", UnitCategory: "mass", Amount: "10 g"},
+			wantErr:    "invalid unit category",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Bundle{
+				Version: Version,
+				Recipes: []Recipe{{Name: "Country", Ingredients: []RecipeIngredient{tt.ingredient}}},
+			}
+			_, err := b.RecipeTemplates()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("RecipeTemplates() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStepIngredientYAML(t *testing.T) {
+	step := Step{
+		Text: "Mix",
+		Ingredients: []StepIngredient{
+			{Name: "Water"},
+			{Name: "Water", Stage: "Levain"},
+		},
+	}
+	want := "text: Mix\ningredients:\n    - Water\n    - name: Water\n      stage: Levain\n"
+
+	var buf bytes.Buffer
+	if err := yaml.NewEncoder(&buf).Encode(step); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("Encode() got = %q, want %q", got, want)
+	}
+
+	var got Step
+	if err := yaml.Unmarshal([]byte(want), &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, step) {
+		t.Errorf("Unmarshal() got = %+v, want %+v", got, step)
+	}
+}
@@ -1,10 +1,15 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/peterbourgon/ff/v4"
@@ -13,7 +18,11 @@ import (
 )
 
 type AddCmdOptions struct {
-	Name string
+	Name        string
+	Notes       string
+	Ingredients []string
+	Stdin       bool
+	Strict      bool
 
 	Root *RootCmdOptions
 }
@@ -32,44 +41,230 @@ func NewAddCmd(parent *RootCmd) *AddCmd {
 	cmd.root = parent
 	cmd.Flags = ff.NewFlagSet("add").SetParent(parent.Flags)
 	cmd.Flags.StringVar(&cmd.Opts.Name, 'n', "name", "", "name of the recipe")
+	cmd.Flags.StringVar(&cmd.Opts.Notes, 0, "notes", "", "notes of the recipe")
+	cmd.Flags.StringListVar(&cmd.Opts.Ingredients, 'i', "ingredient", "ingredient of the recipe as name:kind:value[:dependency[:stage[:unit[:group]]]] (e.g. \"Rye Flour:flour:0.8\")")
+	cmd.Flags.BoolVar(&cmd.Opts.Stdin, 0, "stdin", "read ingredients from stdin, one per line in the format of --ingredient")
+	cmd.Flags.BoolVar(&cmd.Opts.Strict, 0, "strict", "don't add the recipe if it fails lint with errors")
 
 	cmd.Command = &ff.Command{
 		Name:      "add",
 		Usage:     CmdLabel + " add [flags]",
 		ShortHelp: "add a new recipe",
-		Flags:     cmd.Flags,
-		Exec:      AddCmdExec(&cmd.Opts),
+		LongHelp: `  Adds a recipe, optionally with its ingredients. The recipe
+  and all its ingredients are created in a single transaction,
+  so nothing is added if an ingredient is invalid.
+
+  An ingredient is given as name:kind:value with optional
+  dependency, stage, unit category and group fields,
+  separated by colons. The value is a baker's percentage, e.g. 0.8 or 80%,
+  or a fixed amount such as 10g or 2pc. The dependency of a
+  percentage defaults to total_flour, and the unit category
+  to the category of the amount or weight. An empty kind is
+  guessed from the name, and an empty stage is the final
+  dough.
+
+  With --stdin the ingredients are read from stdin, one per
+  line. Empty lines and lines starting with # are skipped.
+
+  A recipe added with ingredients is linted, writing its
+  issues to stderr. With --strict nothing is added if the
+  recipe has lint errors.
+
+  Example:
+
+     $ sourdough add --name "Rye Loaf" \
+         --ingredient "Rye Flour:flour:0.8" \
+         --ingredient "Bread Flour:flour:0.2" \
+         --ingredient "Water:water:85%" \
+         --ingredient "Salt:salt:0.02" \
+         --ingredient "Egg:egg:2pc:::count"
+
+     $ sourdough add --name "Country" --stdin <<EOF
+     Whole Wheat Flour:flour:1::levain
+     Water:water:1::levain
+     Sourdough Starter:sourdough:0.2::levain
+     Bread Flour:flour:1
+     Water:water:0.7
+     Levain:sourdough:0.22
+     Salt::2%
+     EOF
+
+`,
+		Flags: cmd.Flags,
+		Exec:  AddCmdExec(&cmd.Opts),
 	}
 	cmd.root.Command.Subcommands = append(cmd.root.Command.Subcommands, cmd.Command)
 	_ = newAddIngredientCmd(&cmd)
+	_ = newAddStepCmd(&cmd)
 
 	return &cmd
 }
 
 func AddCmdExec(opts *AddCmdOptions) CmdExec {
 	return func(ctx context.Context, args []string) error {
-		db, err := opts.Root.SetupStore(ctx)
+		if opts.Name == "" {
+			return errors.New("requires a recipe name")
+		}
+
+		specs := opts.Ingredients
+		if opts.Stdin {
+			lines, err := readIngredientSpecs(opts.Root.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read stdin: %w", err)
+			}
+			specs = append(specs, lines...)
+		}
+
+		// Parse everything up front so an invalid ingredient fails before
+		// the database is touched.
+		ingredients := make([]AddIngredientParams, 0, len(specs))
+		for _, spec := range specs {
+			ingredient, err := parseIngredientSpec(spec)
+			if err != nil {
+				return err
+			}
+			ingredients = append(ingredients, ingredient)
+		}
+
+		db, err := opts.Root.SetupDB(ctx)
 		if err != nil {
 			return err
 		}
+		defer db.Close()
+
+		return InTx(ctx, db, func(q *query.Queries) error {
+			r, err := q.CreateRecipe(ctx, query.CreateRecipeParams{
+				Name:  opts.Name,
+				Notes: opts.Notes,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, ingredient := range ingredients {
+				ingredient.RecipeID = r.ID
+				if _, err := addRecipeIngredient(ctx, q, ingredient); err != nil {
+					return fmt.Errorf("ingredient %s: %w", ingredient.Name, err)
+				}
+			}
+
+			if len(ingredients) > 0 {
+				issues, err := lintRecipe(ctx, q, opts.Root.Stderr, r)
+				if err != nil {
+					return err
+				}
+				if opts.Strict && recipe.HasErrors(issues) {
+					return fmt.Errorf("recipe %s fails lint, it was not added", r.Name)
+				}
+			}
+
+			if opts.Root.Verbose {
+				fmt.Fprintf(opts.Root.Stdout, "Added recipe %d %s with %d ingredients\n", r.ID, r.Name, len(ingredients))
+			}
+			return nil
+		})
+	}
+}
+
+// readIngredientSpecs reads ingredients in the format of --ingredient, one
+// per line. Empty lines and comments starting with # are skipped.
+func readIngredientSpecs(r io.Reader) ([]string, error) {
+	var specs []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		specs = append(specs, line)
+	}
+
+	return specs, scanner.Err()
+}
+
+// parseIngredientSpec parses an ingredient given as
+// name:kind:value[:dependency[:stage[:unit[:group]]]], where the value is a
+// baker's percentage such as 0.8 or 80%, or a fixed amount such as 10g.
+func parseIngredientSpec(spec string) (AddIngredientParams, error) {
+	fields := strings.Split(spec, ":")
+	if len(fields) < 3 || len(fields) > 7 {
+		return AddIngredientParams{}, fmt.Errorf("invalid ingredient %q, expected name:kind:value[:dependency[:stage[:unit[:group]]]]", spec)
+	}
+	for len(fields) < 7 {
+		fields = append(fields, "")
+	}
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
 
-		// Create new recipe
-		_, err = db.CreateRecipe(ctx, opts.Name)
+	out := AddIngredientParams{
+		Name:               fields[0],
+		Kind:               recipe.Kind(fields[1]),
+		Dependency:         fields[3],
+		Stage:              fields[4],
+		PreferUnitCategory: recipe.UnitCategory(fields[5]),
+		Group:              fields[6],
+	}
+	if out.Name == "" {
+		return out, fmt.Errorf("invalid ingredient %q: missing name", spec)
+	}
+	if out.Kind == "" {
+		out.Kind = recipe.GuessKind(out.Name)
+	}
+
+	if amount, err := recipe.ParseTuple(fields[2]); err == nil {
+		if out.Dependency != "" {
+			return out, fmt.Errorf("invalid ingredient %q: a fixed amount has no dependency", spec)
+		}
+		out.Mode = recipe.ModeAmount
+		out.Amount = amount
+		if out.PreferUnitCategory == recipe.UnitCategoryUnknown {
+			out.PreferUnitCategory = amount.Unit.Category()
+		}
+	} else {
+		percentage, err := parsePercentage(fields[2])
 		if err != nil {
-			return err
+			return out, fmt.Errorf("invalid ingredient %q: %s is neither a percentage nor an amount", spec, fields[2])
+		}
+		out.Mode = recipe.ModePercentage
+		out.Percentage = percentage
+		if out.Dependency == "" {
+			out.Dependency = recipe.DependencyTotalFlour
 		}
+	}
 
-		return err
+	if out.PreferUnitCategory == recipe.UnitCategoryUnknown {
+		out.PreferUnitCategory = recipe.UnitCategoryWeight
+	}
+	if _, err := recipe.FromUnitCategory(out.PreferUnitCategory); err != nil {
+		return out, fmt.Errorf("invalid ingredient %q: %w", spec, err)
 	}
+
+	return out, nil
+}
+
+// parsePercentage parses a ratio such as 0.8 or a percentage such as 80%.
+func parsePercentage(value string) (float64, error) {
+	if v, ok := strings.CutSuffix(value, "%"); ok {
+		percentage, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return percentage / 100, err
+	}
+	return strconv.ParseFloat(value, 64)
 }
 
 type AddIngredientCmdOptions struct {
 	Name               string  `validate:"required"`
 	PreferUnitCategory string  `validate:"required,oneof=weight volume count teaspoon"`
-	RecipeID           int     `validate:"gt=0,required"`
-	Percentage         float64 `validate:"gte=0,lte=1,required"`
-	Dependency         string
+	RecipeID           int     `validate:"gte=0"`
+	Percentage         float64 `validate:"required_without=Amount,excluded_with=Amount,gte=0"`
+	Amount             string  `validate:"required_without=Percentage"`
+	Dependency         string  `validate:"excluded_with=Amount"`
 	Kind               string
+	Stage              string
+	Group              string
+	Hydration          float64 `validate:"gte=0"`
+	Strict             bool
 
 	Parent *AddCmdOptions
 }
@@ -87,18 +282,30 @@ func newAddIngredientCmd(parent *AddCmd) *addIngredientCmd {
 	cmd.Opts.Parent = &parent.Opts
 	cmd.parent = parent
 	cmd.Flags = ff.NewFlagSet("ingredient")
-	cmd.Flags.IntVar(&cmd.Opts.RecipeID, 'r', "recipe", 0, "recipe ID")
+	cmd.Flags.IntVar(&cmd.Opts.RecipeID, 'r', "recipe", 0, "recipe ID, instead of the <recipe> argument")
 	cmd.Flags.StringVar(&cmd.Opts.Name, 'n', "name", "", "name of the ingredient")
 	cmd.Flags.StringEnumVar(&cmd.Opts.PreferUnitCategory, 'u', "unit", "preferred output unit category", "weight", "volume", "count", "teaspoon")
-	cmd.Flags.Float64Var(&cmd.Opts.Percentage, 'p', "percentage", 0.0, "percentage of the ingredient")
-	cmd.Flags.StringVar(&cmd.Opts.Dependency, 'd', "dependency", "", "dependency of the ingredient")
+	cmd.Flags.Float64Var(&cmd.Opts.Percentage, 'p', "percentage", 0.0, "baker's percentage of the ingredient (e.g. 1.05 for 105%)")
+	cmd.Flags.StringVar(&cmd.Opts.Amount, 'a', "amount", "", "fixed amount of the ingredient (e.g. 10g, 2pc)")
+	cmd.Flags.StringVar(&cmd.Opts.Dependency, 'd', "dependency", "", "dependency of the ingredient (total_flour, total_water, total_dough, per_piece or an ingredient name)")
 	cmd.Flags.StringVar(&cmd.Opts.Kind, 'k', "kind", "", "kind of the ingredient")
+	cmd.Flags.Float64Var(&cmd.Opts.Hydration, 0, "hydration", 0, "hydration of a sourdough starter (e.g. 1 for 100%)")
+	cmd.Flags.StringVar(&cmd.Opts.Stage, 's', "stage", "", "stage of the ingredient (e.g. levain); empty for the final dough")
+	cmd.Flags.StringVar(&cmd.Opts.Group, 'g', "group", "", "group of the ingredient (e.g. topping), shown with a subtotal")
+	cmd.Flags.BoolVar(&cmd.Opts.Strict, 0, "strict", "don't add the ingredient if the recipe fails lint with errors")
 	cmd.Command = &ff.Command{
 		Name:      "ingredient",
 		Usage:     CmdLabel + " add ingredient <recipe> [flags]",
 		ShortHelp: "add a new ingredient to a recipe",
-		Flags:     cmd.Flags,
-		Exec:      addIngredientCmdExec(&cmd.Opts),
+		LongHelp: `  Adds an ingredient to a recipe and lints the recipe, writing
+  its issues to stderr. With --strict nothing is added if the
+  recipe has lint errors afterwards. The flour-sum check is
+  skipped, as the flour only sums to 100% once every flour is
+  added; run lint to check it.
+
+`,
+		Flags: cmd.Flags,
+		Exec:  addIngredientCmdExec(&cmd.Opts),
 	}
 	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
 
@@ -113,32 +320,71 @@ func addIngredientCmdExec(opts *AddIngredientCmdOptions) CmdExec {
 			return err
 		}
 
-		db, err := opts.Parent.Root.SetupStore(ctx)
+		db, err := opts.Parent.Root.SetupDB(ctx)
 		if err != nil {
 			return err
 		}
+		defer db.Close()
+
+		mode := recipe.ModePercentage
+		var amount recipe.Tuple
+		if opts.Amount != "" {
+			mode = recipe.ModeAmount
+			amount, err = recipe.ParseTuple(opts.Amount)
+			if err != nil {
+				return err
+			}
+		}
+
+		var value string
+		switch {
+		case len(args) > 0:
+			value = args[0]
+		case opts.RecipeID > 0:
+			value = strconv.Itoa(opts.RecipeID)
+		default:
+			return errors.New("requires a recipe ID or name")
+		}
 
-		// Check if recipe exists
-		r, err := db.GetRecipe(ctx, int64(opts.RecipeID))
+		r, err := findRecipe(ctx, query.New(db), value)
 		if err != nil {
-			return fmt.Errorf("recipe %d does not exist: %w", opts.RecipeID, err)
+			return err
 		}
 
 		if opts.Parent.Root.Verbose {
 			fmt.Fprintf(opts.Parent.Root.Stdout, "Adding ingredient %s to recipe %s\n", opts.Name, r.Name)
-			fmt.Fprintf(opts.Parent.Root.Stdout, "Ingredient: %s, Unit: %s, Percentage: %f, Dependency: %s\n", opts.Name, opts.PreferUnitCategory, opts.Percentage, opts.Dependency)
+			fmt.Fprintf(opts.Parent.Root.Stdout, "Ingredient: %s, Unit: %s, Percentage: %f, Amount: %s, Dependency: %s\n", opts.Name, opts.PreferUnitCategory, opts.Percentage, opts.Amount, opts.Dependency)
 		}
 
-		_, err = addRecipeIngredient(ctx, db, AddIngredientParams{
-			Name:               opts.Name,
-			RecipeID:           r.ID,
-			PreferUnitCategory: recipe.UnitCategory(opts.PreferUnitCategory),
-			Percentage:         opts.Percentage,
-			Dependency:         opts.Dependency,
-			Kind:               recipe.Kind(opts.Kind), // TODO: parse ingredient type
-		})
+		return InTx(ctx, db, func(q *query.Queries) error {
+			_, err := addRecipeIngredient(ctx, q, AddIngredientParams{
+				Name:               opts.Name,
+				RecipeID:           r.ID,
+				PreferUnitCategory: recipe.UnitCategory(opts.PreferUnitCategory),
+				Mode:               mode,
+				Percentage:         opts.Percentage,
+				Amount:             amount,
+				Dependency:         opts.Dependency,
+				Kind:               recipe.Kind(opts.Kind), // TODO: parse ingredient type
+				Stage:              opts.Stage,
+				Group:              opts.Group,
+				Hydration:          opts.Hydration,
+			})
+			if err != nil {
+				return err
+			}
 
-		return err
+			// The flour of a recipe built one ingredient at a time
+			// only sums to 100% once the last flour is added.
+			issues, err := lintRecipe(ctx, q, opts.Parent.Root.Stderr, r, recipe.CheckFlourSum)
+			if err != nil {
+				return err
+			}
+			if opts.Strict && recipe.HasErrors(issues) {
+				return fmt.Errorf("recipe %s fails lint, %s was not added", r.Name, opts.Name)
+			}
+			return nil
+		})
 	}
 }
 
@@ -146,27 +392,61 @@ type AddIngredientParams struct {
 	Name               string
 	RecipeID           int64
 	PreferUnitCategory recipe.UnitCategory
+	Mode               recipe.Mode
 	Percentage         float64
+	Amount             recipe.Tuple
 	Dependency         string
 	Kind               recipe.Kind
+	Stage              string
+	Group              string
+	Hydration          float64
 }
 
-// addRecipeIngredient adds a new ingredient to a recipe. If the ingredient
-// does not exist, it will be created.
+// addRecipeIngredient adds a new ingredient to the end of a recipe. If the
+// ingredient does not exist, it will be created. A non-zero hydration is
+// stored on the ingredient.
 func addRecipeIngredient(ctx context.Context, db *query.Queries, args AddIngredientParams) (*query.RecipeIngredient, error) {
 	ingredient, err := db.GetIngredientByName(ctx, args.Name)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return nil, fmt.Errorf("failed to get ingredient %s: %w", args.Name, err)
 	}
 
+	var hydration *float64
+	if args.Hydration > 0 {
+		hydration = &args.Hydration
+	}
+
 	if ingredient.ID == 0 {
 		ingredient, err = db.CreateIngredient(ctx, query.CreateIngredientParams{
-			Name: args.Name,
-			Kind: args.Kind,
+			Name:      args.Name,
+			Kind:      args.Kind,
+			Hydration: hydration,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to create ingredient %s: %w", args.Name, err)
 		}
+	} else if hydration != nil {
+		ingredient, err = db.SetIngredientHydration(ctx, query.SetIngredientHydrationParams{
+			Hydration: hydration,
+			ID:        ingredient.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to set hydration of %s: %w", args.Name, err)
+		}
+	}
+
+	var stageID *int64
+	if args.Stage != recipe.FinalDough {
+		stage, err := getOrCreateStage(ctx, db, args.RecipeID, args.Stage)
+		if err != nil {
+			return nil, err
+		}
+		stageID = &stage.ID
+	}
+
+	position, err := db.NextRecipeIngredientPosition(ctx, args.RecipeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get position of %s: %w", args.Name, err)
 	}
 
 	// Create new recipe ingredient
@@ -176,6 +456,16 @@ func addRecipeIngredient(ctx context.Context, db *query.Queries, args AddIngredi
 		PreferUnitCategory: args.PreferUnitCategory,
 		Percentage:         args.Percentage,
 		Dependency:         args.Dependency,
+		Mode:               args.Mode,
+		StageID:            stageID,
+		Position:           position,
+	}
+	if args.Group != "" {
+		params.GroupName = &args.Group
+	}
+	if args.Mode == recipe.ModeAmount {
+		params.Amount = &args.Amount.Value
+		params.Unit = args.Amount.Unit
 	}
 
 	ri, err := db.CreateRecipeIngredient(ctx, params)
@@ -185,3 +475,221 @@ func addRecipeIngredient(ctx context.Context, db *query.Queries, args AddIngredi
 
 	return &ri, nil
 }
+
+// getOrCreateStage returns the stage of a recipe with the given name. If the
+// stage does not exist, it will be created after the existing stages.
+func getOrCreateStage(ctx context.Context, db *query.Queries, recipeID int64, name string) (*query.Stage, error) {
+	stage, err := db.GetStageByName(ctx, query.GetStageByNameParams{
+		RecipeID: recipeID,
+		Name:     name,
+	})
+	if err == nil {
+		return &stage, nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get stage %s: %w", name, err)
+	}
+
+	stages, err := db.ListStages(ctx, recipeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stages: %w", err)
+	}
+
+	stage, err = db.CreateStage(ctx, query.CreateStageParams{
+		RecipeID: recipeID,
+		Name:     name,
+		Position: int64(len(stages) + 1),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stage %s: %w", name, err)
+	}
+
+	return &stage, nil
+}
+
+type AddStepCmdOptions struct {
+	Text        string
+	Duration    string
+	Temperature string
+	Ingredients []string
+
+	Parent *AddCmdOptions
+}
+
+type addStepCmd struct {
+	Opts AddStepCmdOptions
+
+	parent  *AddCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newAddStepCmd(parent *AddCmd) *addStepCmd {
+	var cmd addStepCmd
+	cmd.Opts.Parent = &parent.Opts
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("step").SetParent(parent.root.Flags)
+	cmd.Flags.StringVar(&cmd.Opts.Text, 't', "text", "", "what to do in the step (e.g. \"bulk ferment\")")
+	cmd.Flags.StringVar(&cmd.Opts.Duration, 'd', "duration", "", "duration or range of the step (e.g. 30m, 1h30m, 12-16h)")
+	cmd.Flags.StringVar(&cmd.Opts.Temperature, 0, "temp", "", "temperature of the step (e.g. 24C)")
+	cmd.Flags.StringListVar(&cmd.Opts.Ingredients, 'i', "ingredient", "ID or name of an ingredient of the recipe added in the step")
+	cmd.Command = &ff.Command{
+		Name:      "step",
+		Usage:     CmdLabel + " add step <recipe> [flags]",
+		ShortHelp: "add a step to the method of a recipe",
+		LongHelp: `  Adds a step to the end of the method of a recipe. The
+  ingredients added in the step are given by name, or by the
+  ID shown in the # column of view if several ingredients of
+  the recipe have the same name.
+
+  Example:
+
+     $ sourdough add step --text "Autolyse" --duration 30m \
+         --ingredient "Bread Flour" --ingredient 5 1
+     $ sourdough add step --text "Cold retard" --duration 12-16h \
+         --temp 4C 1
+
+`,
+		Flags: cmd.Flags,
+		Exec:  addStepCmdExec(&cmd.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func addStepCmdExec(opts *AddStepCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return errors.New("requires a recipe ID or name")
+		}
+
+		step, err := parseStep(opts.Text, opts.Duration, opts.Temperature)
+		if err != nil {
+			return err
+		}
+
+		db, err := opts.Parent.Root.SetupDB(ctx)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		return InTx(ctx, db, func(q *query.Queries) error {
+			r, err := findRecipe(ctx, q, args[0])
+			if err != nil {
+				return err
+			}
+
+			ids, err := findStepIngredients(ctx, q, r.ID, opts.Ingredients)
+			if err != nil {
+				return err
+			}
+
+			row, err := addStep(ctx, q, r.ID, step, ids)
+			if err != nil {
+				return err
+			}
+
+			if opts.Parent.Root.Verbose {
+				fmt.Fprintf(opts.Parent.Root.Stdout, "Added step %d to recipe %s\n", row.ID, r.Name)
+			}
+			return nil
+		})
+	}
+}
+
+// parseStep parses the flags of a step. Duration and temperature are
+// optional.
+func parseStep(text, duration, temperature string) (recipe.Step, error) {
+	step := recipe.Step{Text: strings.TrimSpace(text)}
+	if step.Text == "" {
+		return step, errors.New("requires the --text of the step")
+	}
+
+	if duration != "" {
+		d, err := recipe.ParseDurationRange(duration)
+		if err != nil {
+			return step, err
+		}
+		step.Duration = d
+	}
+
+	if temperature != "" {
+		t, err := recipe.ParseTemperature(temperature)
+		if err != nil {
+			return step, err
+		}
+		step.Temperature = &t
+	}
+
+	return step, nil
+}
+
+// addStep adds a step to the end of the method of a recipe, with the recipe
+// ingredients added in it.
+func addStep(ctx context.Context, db *query.Queries, recipeID int64, step recipe.Step, ingredientIDs []int64) (*query.Step, error) {
+	position, err := db.NextStepPosition(ctx, recipeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get position of step %s: %w", step.Text, err)
+	}
+
+	params := query.CreateStepParams{
+		RecipeID: recipeID,
+		Position: position,
+		Text:     step.Text,
+	}
+	params.DurationMin, params.DurationMax = durationColumns(step.Duration)
+	params.Temperature, params.TemperatureScale = temperatureColumns(step.Temperature)
+
+	row, err := db.CreateStep(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create step %s: %w", step.Text, err)
+	}
+
+	if err := addStepIngredients(ctx, db, row.ID, ingredientIDs); err != nil {
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+func addStepIngredients(ctx context.Context, db *query.Queries, stepID int64, ingredientIDs []int64) error {
+	seen := map[int64]bool{}
+	for _, id := range ingredientIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		err := db.CreateStepIngredient(ctx, query.CreateStepIngredientParams{
+			StepID:             stepID,
+			RecipeIngredientID: id,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add recipe ingredient %d to step %d: %w", id, stepID, err)
+		}
+	}
+	return nil
+}
+
+// durationColumns returns the duration of a step in minutes, null for a step
+// without a duration.
+func durationColumns(d recipe.DurationRange) (*int64, *int64) {
+	if d.IsZero() {
+		return nil, nil
+	}
+
+	min, max := int64(d.Min/time.Minute), int64(d.Max/time.Minute)
+	return &min, &max
+}
+
+// temperatureColumns returns the temperature of a step and its scale. The
+// scale of a step without a temperature is Celsius.
+func temperatureColumns(t *recipe.Temperature) (*float64, recipe.TemperatureScale) {
+	if t == nil {
+		return nil, recipe.Celsius
+	}
+
+	value := t.Value
+	return &value, t.Scale
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/simonklee/sourdough/recipe"
+)
+
+func TestParseIngredientSpec(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    AddIngredientParams
+		wantErr bool
+	}{
+		// The examples of add and the README.
+		{
+			spec: "Rye Flour:flour:0.8",
+			want: AddIngredientParams{Name: "Rye Flour", Kind: recipe.KindFlour, Mode: recipe.ModePercentage, Percentage: 0.8, Dependency: recipe.DependencyTotalFlour, PreferUnitCategory: recipe.UnitCategoryWeight},
+		},
+		{
+			spec: "Water:water:85%",
+			want: AddIngredientParams{Name: "Water", Kind: recipe.KindWater, Mode: recipe.ModePercentage, Percentage: 0.85, Dependency: recipe.DependencyTotalFlour, PreferUnitCategory: recipe.UnitCategoryWeight},
+		},
+		{
+			spec: "Salt::0.02",
+			want: AddIngredientParams{Name: "Salt", Kind: recipe.KindSalt, Mode: recipe.ModePercentage, Percentage: 0.02, Dependency: recipe.DependencyTotalFlour, PreferUnitCategory: recipe.UnitCategoryWeight},
+		},
+		{
+			spec: "Egg:egg:2pc:::count",
+			want: AddIngredientParams{Name: "Egg", Kind: recipe.KindEgg, Mode: recipe.ModeAmount, Amount: recipe.UnitPieces.Tuple(2), PreferUnitCategory: recipe.UnitCategoryCount},
+		},
+		{
+			spec: "Whole Wheat Flour:flour:1::levain",
+			want: AddIngredientParams{Name: "Whole Wheat Flour", Kind: recipe.KindFlour, Mode: recipe.ModePercentage, Percentage: 1, Dependency: recipe.DependencyTotalFlour, Stage: "levain", PreferUnitCategory: recipe.UnitCategoryWeight},
+		},
+		{
+			spec: "Sourdough Starter:sourdough:0.2::levain",
+			want: AddIngredientParams{Name: "Sourdough Starter", Kind: recipe.KindSourdough, Mode: recipe.ModePercentage, Percentage: 0.2, Dependency: recipe.DependencyTotalFlour, Stage: "levain", PreferUnitCategory: recipe.UnitCategoryWeight},
+		},
+		{
+			spec: "Levain:sourdough:0.22",
+			want: AddIngredientParams{Name: "Levain", Kind: recipe.KindSourdough, Mode: recipe.ModePercentage, Percentage: 0.22, Dependency: recipe.DependencyTotalFlour, PreferUnitCategory: recipe.UnitCategoryWeight},
+		},
+		{
+			spec: "Salt:salt:2%",
+			want: AddIngredientParams{Name: "Salt", Kind: recipe.KindSalt, Mode: recipe.ModePercentage, Percentage: 0.02, Dependency: recipe.DependencyTotalFlour, PreferUnitCategory: recipe.UnitCategoryWeight},
+		},
+		// Every field.
+		{
+			spec: "Sesame:other:5%:Bread Flour:Final:weight:Topping",
+			want: AddIngredientParams{Name: "Sesame", Kind: "other", Mode: recipe.ModePercentage, Percentage: 0.05, Dependency: "Bread Flour", Stage: "Final", PreferUnitCategory: recipe.UnitCategoryWeight, Group: "Topping"},
+		},
+		{spec: "Egg:egg:2pc:total_flour", wantErr: true},
+		{spec: "Water:water", wantErr: true},
+		{spec: ":water:0.7", wantErr: true},
+		{spec: "Water:water:lots", wantErr: true},
+		{spec: "Water:water:0.7:::cups", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := parseIngredientSpec(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseIngredientSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseIngredientSpec() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,931 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/peterbourgon/ff/v4"
+	"github.com/simonklee/sourdough/bundle"
+	"github.com/simonklee/sourdough/query"
+	"github.com/simonklee/sourdough/recipe"
+)
+
+type BakeCmdOptions struct {
+	Root *RootCmdOptions
+}
+
+type BakeCmd struct {
+	Opts BakeCmdOptions
+
+	root    *RootCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func NewBakeCmd(parent *RootCmd) *BakeCmd {
+	var cmd BakeCmd
+	cmd.Opts.Root = &parent.Opts
+	cmd.root = parent
+	cmd.Flags = ff.NewFlagSet("bake").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "bake",
+		Usage:     CmdLabel + " bake <subcommand> ...",
+		ShortHelp: "keep a journal of bakes",
+		LongHelp: `  A bake is started from a recipe, logged as it goes and
+  finished with a rating. The journal keeps the version of
+  the recipe baked, so it's clear which bakes were made
+  before the recipe changed.
+
+  Example:
+
+     $ sourdough bake start --dependency "total_flour 1000g" Country
+     $ sourdough bake log --stage bulk --temp 24C --duration 5h30m
+     $ sourdough bake finish --rating 4 --notes "open crumb" --photo crumb.jpg
+     $ sourdough bake list
+
+`,
+		Flags: cmd.Flags,
+		Exec: func(ctx context.Context, args []string) error {
+			return ff.ErrHelp
+		},
+	}
+	cmd.root.Command.Subcommands = append(cmd.root.Command.Subcommands, cmd.Command)
+	_ = newBakeStartCmd(&cmd)
+	_ = newBakeLogCmd(&cmd)
+	_ = newBakeFinishCmd(&cmd)
+	_ = newBakeListCmd(&cmd)
+	_ = newBakeShowCmd(&cmd)
+
+	return &cmd
+}
+
+type BakeStartCmdOptions struct {
+	Dependency string
+	Scale      float64
+	Notes      string
+	At         string
+
+	Parent *BakeCmdOptions
+}
+
+type bakeStartCmd struct {
+	Opts BakeStartCmdOptions
+
+	parent  *BakeCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newBakeStartCmd(parent *BakeCmd) *bakeStartCmd {
+	var cmd bakeStartCmd
+	cmd.Opts.Parent = &parent.Opts
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("start").SetParent(parent.Flags)
+	cmd.Flags.StringVar(&cmd.Opts.Dependency, 'd', "dependency", "", "dependency the recipe is baked for, as in view (e.g. \"total_flour 1000g\")")
+	cmd.Flags.Float64Var(&cmd.Opts.Scale, 's', "scale", 1, "factor applied to fixed-amount ingredients")
+	cmd.Flags.StringVar(&cmd.Opts.Notes, 'n', "notes", "", "notes of the bake")
+	cmd.Flags.StringVar(&cmd.Opts.At, 0, "at", "", "when the bake started (default: now)")
+	cmd.Command = &ff.Command{
+		Name:      "start",
+		Usage:     CmdLabel + " bake start [flags] <recipe>",
+		ShortHelp: "start a bake of a recipe",
+		LongHelp: `  Starts a bake of a recipe, for the amounts calculated by
+  view with the same --dependency and --scale. The bake is
+  the current one until it's finished, and is logged to by
+  bake log and bake finish without a bake ID.
+
+  A time without a date, e.g. --at 07:30, is the last time
+  it was on the clock.
+
+`,
+		Flags: cmd.Flags,
+		Exec:  bakeStartCmdExec(&cmd.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func bakeStartCmdExec(opts *BakeStartCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return errors.New("requires a recipe ID or name")
+		}
+		if opts.Scale <= 0 {
+			return fmt.Errorf("invalid scale %g, must be more than 0", opts.Scale)
+		}
+
+		startedAt, err := parseBakeTime(opts.At, time.Now())
+		if err != nil {
+			return err
+		}
+
+		var dependencies []recipe.Dependency
+		if opts.Dependency != "" {
+			dep, err := recipe.ParseDependency(opts.Dependency)
+			if err != nil {
+				return err
+			}
+			dependencies = append(dependencies, dep)
+		}
+
+		db, err := opts.Parent.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		r, err := findRecipe(ctx, db, args[0])
+		if err != nil {
+			return err
+		}
+
+		template, err := loadRecipe(ctx, db, r)
+		if err != nil {
+			return err
+		}
+
+		params := query.CreateBakeParams{
+			RecipeID:   &r.ID,
+			RecipeName: r.Name,
+			Scale:      opts.Scale,
+			Notes:      strings.TrimSpace(opts.Notes),
+			StartedAt:  startedAt.UTC(),
+		}
+		if params.RecipeVersion, err = recipeVersion(template); err != nil {
+			return err
+		}
+		if len(dependencies) > 0 {
+			// Check the recipe can be calculated for the dependency, as
+			// view would.
+			if recipe.IsStaged(template.Ingredients) {
+				_, err = recipe.CalculateStages(template.Ingredients, dependencies, opts.Scale)
+			} else {
+				_, err = recipe.CalculateScaled(template.Ingredients, dependencies, opts.Scale)
+			}
+			if err != nil {
+				return err
+			}
+
+			dependency := formatDependency(dependencies[0])
+			params.Dependency = &dependency
+		}
+
+		bake, err := db.CreateBake(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to start bake: %w", err)
+		}
+
+		fmt.Fprintf(opts.Parent.Root.Stdout, "started bake %d of %s\n", bake.ID, bake.RecipeName)
+		return nil
+	}
+}
+
+type BakeLogCmdOptions struct {
+	Text     string
+	Stage    string
+	Temp     string
+	Duration string
+	At       string
+	Photos   []string
+
+	Parent *BakeCmdOptions
+}
+
+type bakeLogCmd struct {
+	Opts BakeLogCmdOptions
+
+	parent  *BakeCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newBakeLogCmd(parent *BakeCmd) *bakeLogCmd {
+	var cmd bakeLogCmd
+	cmd.Opts.Parent = &parent.Opts
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("log").SetParent(parent.Flags)
+	cmd.Flags.StringVar(&cmd.Opts.Text, 't', "text", "", "what happened (e.g. \"shaped\")")
+	cmd.Flags.StringVar(&cmd.Opts.Stage, 0, "stage", "", "fermentation stage the entry is of: bulk or proof")
+	cmd.Flags.StringVar(&cmd.Opts.Temp, 0, "temp", "", "actual temperature of the dough (e.g. 24C)")
+	cmd.Flags.StringVar(&cmd.Opts.Duration, 'd', "duration", "", "how long it took (e.g. 5h30m)")
+	cmd.Flags.StringVar(&cmd.Opts.At, 0, "at", "", "when it happened (default: now)")
+	cmd.Flags.StringListVar(&cmd.Opts.Photos, 'p', "photo", "photo to attach to the bake, copied into the photos directory")
+	cmd.Command = &ff.Command{
+		Name:      "log",
+		Usage:     CmdLabel + " bake log [flags] [bake]",
+		ShortHelp: "log the temperatures and times of a bake",
+		LongHelp: `  Logs what happened during a bake, by default the current
+  one. A bulk fermentation or proof logged with --stage,
+  --temp and --duration is also recorded as an observation
+  of ferment-estimate, calibrating its estimates.
+
+  Photos are copied into the photos directory next to the
+  config file.
+
+  Example:
+
+     $ sourdough bake log --text "autolyse" --temp 23C
+     $ sourdough bake log --stage bulk --temp 24C --duration 5h30m
+     $ sourdough bake log --text "shaped" --photo shaped.jpg 3
+
+`,
+		Flags: cmd.Flags,
+		Exec:  bakeLogCmdExec(&cmd.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func bakeLogCmdExec(opts *BakeLogCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) > 1 {
+			return errors.New("requires at most one bake ID")
+		}
+
+		entry, err := parseBakeLog(opts, time.Now())
+		if err != nil {
+			return err
+		}
+		if entry.Text == "" && entry.Stage == "" && entry.Temperature == nil && entry.Minutes == nil && len(opts.Photos) == 0 {
+			return errors.New("requires --text, --stage, --temp, --duration or --photo")
+		}
+		if err := checkPhotos(opts.Photos); err != nil {
+			return err
+		}
+
+		db, err := opts.Parent.Root.SetupDB(ctx)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		var (
+			bake   query.Bake
+			photos []string
+			logged bool
+		)
+		dir := photoDir(opts.Parent.Root)
+		err = InTx(ctx, db, func(q *query.Queries) error {
+			if bake, err = findBake(ctx, q, firstArg(args)); err != nil {
+				return err
+			}
+
+			if entry.Text != "" || entry.Stage != "" || entry.Temperature != nil || entry.Minutes != nil {
+				entry.BakeID = bake.ID
+				if _, err := q.CreateBakeLog(ctx, entry); err != nil {
+					return fmt.Errorf("failed to log bake %d: %w", bake.ID, err)
+				}
+				if logged, err = observeFerment(ctx, q, bake, entry); err != nil {
+					return err
+				}
+			}
+
+			photos, err = addPhotos(ctx, q, dir, bake.ID, opts.Photos)
+			return err
+		})
+		if err != nil {
+			removePhotos(dir, photos)
+			return err
+		}
+
+		fmt.Fprintf(opts.Parent.Root.Stdout, "logged bake %d of %s\n", bake.ID, bake.RecipeName)
+		if logged {
+			fmt.Fprintf(opts.Parent.Root.Stdout, "recorded %s of %s at %s for ferment-estimate\n", entry.Stage, formatMinutes(*entry.Minutes), formatLogTemperature(entry.Temperature, entry.TemperatureScale))
+		}
+		for _, photo := range photos {
+			fmt.Fprintf(opts.Parent.Root.Stdout, "added photo %s\n", filepath.Join(dir, photo))
+		}
+		return nil
+	}
+}
+
+// parseBakeLog parses the flags of bake log into a log entry, without the
+// bake.
+func parseBakeLog(opts *BakeLogCmdOptions, now time.Time) (query.CreateBakeLogParams, error) {
+	entry := query.CreateBakeLogParams{
+		Text:             strings.TrimSpace(opts.Text),
+		Stage:            recipe.FermentStage(strings.ToLower(strings.TrimSpace(opts.Stage))),
+		TemperatureScale: recipe.Celsius,
+	}
+	if entry.Stage != "" && entry.Stage != recipe.FermentBulk && entry.Stage != recipe.FermentProof {
+		return entry, fmt.Errorf("invalid stage %s, want bulk or proof", opts.Stage)
+	}
+
+	if opts.Temp != "" {
+		t, err := recipe.ParseTemperature(opts.Temp)
+		if err != nil {
+			return entry, fmt.Errorf("invalid temperature: %w", err)
+		}
+		entry.Temperature, entry.TemperatureScale = &t.Value, t.Scale
+	}
+
+	if opts.Duration != "" {
+		d, err := recipe.ParseDurationRange(opts.Duration)
+		if err != nil || d.Min != d.Max || d.Min < time.Minute {
+			return entry, fmt.Errorf("invalid duration %s, expected a duration such as 5h30m", opts.Duration)
+		}
+		minutes := int64(d.Min.Round(time.Minute) / time.Minute)
+		entry.Minutes = &minutes
+	}
+
+	at, err := parseBakeTime(opts.At, now)
+	if err != nil {
+		return entry, err
+	}
+	entry.LoggedAt = at.UTC()
+	return entry, nil
+}
+
+// observeFerment records a logged bulk fermentation or proof with its
+// temperature and duration as an observation of ferment-estimate. It returns
+// false if the entry isn't one or the recipe of the bake has no starter.
+func observeFerment(ctx context.Context, db *query.Queries, bake query.Bake, entry query.CreateBakeLogParams) (bool, error) {
+	if entry.Stage == "" || entry.Temperature == nil || entry.Minutes == nil || bake.RecipeID == nil {
+		return false, nil
+	}
+
+	r, err := db.GetRecipe(ctx, *bake.RecipeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get recipe %d: %w", *bake.RecipeID, err)
+	}
+
+	t := recipe.Temperature{Value: *entry.Temperature, Scale: entry.TemperatureScale}
+	f, err := recipeFermentation(ctx, db, r.ID, t)
+	if err != nil || f.Starter == 0 {
+		return false, err
+	}
+
+	if err := logFerment(ctx, db, &r, entry.Stage, f, time.Duration(*entry.Minutes)*time.Minute); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+type BakeFinishCmdOptions struct {
+	Rating int
+	Notes  string
+	At     string
+	Photos []string
+
+	Parent *BakeCmdOptions
+}
+
+type bakeFinishCmd struct {
+	Opts BakeFinishCmdOptions
+
+	parent  *BakeCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newBakeFinishCmd(parent *BakeCmd) *bakeFinishCmd {
+	var cmd bakeFinishCmd
+	cmd.Opts.Parent = &parent.Opts
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("finish").SetParent(parent.Flags)
+	cmd.Flags.IntVar(&cmd.Opts.Rating, 'r', "rating", 0, "rating of the bake from 1 to 5")
+	cmd.Flags.StringVar(&cmd.Opts.Notes, 'n', "notes", "", "notes of the outcome, added to the notes of the bake")
+	cmd.Flags.StringVar(&cmd.Opts.At, 0, "at", "", "when the bake finished (default: now)")
+	cmd.Flags.StringListVar(&cmd.Opts.Photos, 'p', "photo", "photo to attach to the bake, copied into the photos directory")
+	cmd.Command = &ff.Command{
+		Name:      "finish",
+		Usage:     CmdLabel + " bake finish [flags] [bake]",
+		ShortHelp: "finish a bake and rate it",
+		LongHelp: `  Finishes a bake, by default the current one. A finished
+  bake may be finished again to change its rating or to add
+  notes and photos, e.g. once the bread has been tasted.
+
+  Example:
+
+     $ sourdough bake finish --rating 4 --notes "open crumb" --photo crumb.jpg
+     $ sourdough bake finish --rating 5 3
+
+`,
+		Flags: cmd.Flags,
+		Exec:  bakeFinishCmdExec(&cmd.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func bakeFinishCmdExec(opts *BakeFinishCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) > 1 {
+			return errors.New("requires at most one bake ID")
+		}
+		if opts.Rating != 0 && (opts.Rating < 1 || opts.Rating > 5) {
+			return fmt.Errorf("invalid rating %d, must be from 1 to 5", opts.Rating)
+		}
+		if err := checkPhotos(opts.Photos); err != nil {
+			return err
+		}
+
+		finishedAt, err := parseBakeTime(opts.At, time.Now())
+		if err != nil {
+			return err
+		}
+
+		db, err := opts.Parent.Root.SetupDB(ctx)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		var (
+			bake   query.Bake
+			photos []string
+		)
+		dir := photoDir(opts.Parent.Root)
+		err = InTx(ctx, db, func(q *query.Queries) error {
+			if bake, err = findBake(ctx, q, firstArg(args)); err != nil {
+				return err
+			}
+
+			params := query.FinishBakeParams{
+				ID:         bake.ID,
+				Notes:      bake.Notes,
+				Rating:     bake.Rating,
+				FinishedAt: bake.FinishedAt,
+			}
+			if notes := strings.TrimSpace(opts.Notes); notes != "" && params.Notes != "" {
+				params.Notes += "\n" + notes
+			} else if notes != "" {
+				params.Notes = notes
+			}
+			if opts.Rating != 0 {
+				rating := int64(opts.Rating)
+				params.Rating = &rating
+			}
+			if params.FinishedAt == nil || opts.At != "" {
+				at := finishedAt.UTC()
+				params.FinishedAt = &at
+			}
+			if params.FinishedAt.Before(bake.StartedAt) {
+				return fmt.Errorf("bake %d can't finish before it started at %s", bake.ID, formatBakeTime(bake.StartedAt))
+			}
+
+			if bake, err = q.FinishBake(ctx, params); err != nil {
+				return fmt.Errorf("failed to finish bake %d: %w", params.ID, err)
+			}
+
+			photos, err = addPhotos(ctx, q, dir, bake.ID, opts.Photos)
+			return err
+		})
+		if err != nil {
+			removePhotos(dir, photos)
+			return err
+		}
+
+		fmt.Fprintf(opts.Parent.Root.Stdout, "finished bake %d of %s\n", bake.ID, bake.RecipeName)
+		for _, photo := range photos {
+			fmt.Fprintf(opts.Parent.Root.Stdout, "added photo %s\n", filepath.Join(dir, photo))
+		}
+		return nil
+	}
+}
+
+type bakeListCmd struct {
+	parent  *BakeCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newBakeListCmd(parent *BakeCmd) *bakeListCmd {
+	var cmd bakeListCmd
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("list").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "list",
+		Usage:     CmdLabel + " bake list [recipe]",
+		ShortHelp: "list bakes, latest first",
+		Flags:     cmd.Flags,
+		Exec:      bakeListCmdExec(&parent.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func bakeListCmdExec(opts *BakeCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) > 1 {
+			return errors.New("requires at most one recipe ID or name")
+		}
+
+		db, err := opts.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		title := "Bakes"
+		var bakes []query.Bake
+		if len(args) == 1 {
+			r, err := findRecipe(ctx, db, args[0])
+			if err != nil {
+				return err
+			}
+
+			title = fmt.Sprintf("Bakes: %s", r.Name)
+			// A negative limit is no limit in SQLite.
+			bakes, err = db.ListRecipeBakes(ctx, query.ListRecipeBakesParams{RecipeID: &r.ID, Limit: -1})
+			if err != nil {
+				return err
+			}
+		} else if bakes, err = db.ListBakes(ctx); err != nil {
+			return err
+		}
+
+		format := opts.Root.OutputFormat()
+		if format.Structured() {
+			return renderData(opts.Root.Stdout, format, bakeListOutput(bakes))
+		}
+		return renderBakes(opts.Root.Stdout, format, title, bakes)
+	}
+}
+
+type bakeShowCmd struct {
+	parent  *BakeCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newBakeShowCmd(parent *BakeCmd) *bakeShowCmd {
+	var cmd bakeShowCmd
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("show").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "show",
+		Usage:     CmdLabel + " bake show [bake]",
+		ShortHelp: "show a bake with its log and photos",
+		LongHelp: `  Shows a bake, by default the current one, with what was
+  logged and the paths of its photos. The version of the
+  recipe is marked as changed if the ingredients or steps of
+  the recipe have changed since the bake started.
+
+`,
+		Flags: cmd.Flags,
+		Exec:  bakeShowCmdExec(&parent.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func bakeShowCmdExec(opts *BakeCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) > 1 {
+			return errors.New("requires at most one bake ID")
+		}
+
+		db, err := opts.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		bake, err := findBake(ctx, db, firstArg(args))
+		if err != nil {
+			return err
+		}
+
+		view := BakeView{Bake: bake, PhotoDir: photoDir(opts.Root)}
+		if bake.RecipeID != nil {
+			r, err := db.GetRecipe(ctx, *bake.RecipeID)
+			if err != nil {
+				return fmt.Errorf("failed to get recipe %d: %w", *bake.RecipeID, err)
+			}
+
+			template, err := loadRecipe(ctx, db, r)
+			if err != nil {
+				return err
+			}
+
+			version, err := recipeVersion(template)
+			if err != nil {
+				return err
+			}
+			view.Changed = version != bake.RecipeVersion
+		}
+
+		if view.Logs, err = db.ListBakeLogs(ctx, bake.ID); err != nil {
+			return err
+		}
+		if view.Photos, err = db.ListBakePhotos(ctx, bake.ID); err != nil {
+			return err
+		}
+
+		return view.Render(ctx, opts.Root.Stdout, opts.Root.OutputFormat())
+	}
+}
+
+type BakeView struct {
+	Bake   query.Bake
+	Logs   []query.BakeLog
+	Photos []query.BakePhoto
+
+	// Changed is true if the recipe has changed since the bake started.
+	Changed bool
+
+	// PhotoDir is the directory the paths of the photos are relative to.
+	PhotoDir string
+}
+
+func (v BakeView) Render(ctx context.Context, w io.Writer, format OutputFormat) error {
+	if format.Structured() {
+		return renderData(w, format, v.Output())
+	}
+
+	tw := table.NewWriter()
+	tw.SetStyle(table.StyleLight)
+	tw.SetTitle(fmt.Sprintf("Bake %d: %s", v.Bake.ID, v.Bake.RecipeName))
+	tw.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 2, WidthMax: 64},
+	})
+
+	version := v.Bake.RecipeVersion
+	if v.Bake.RecipeID == nil {
+		version += " (recipe removed)"
+	} else if v.Changed {
+		version += " (recipe changed since)"
+	}
+	tw.AppendRow(table.Row{"Recipe version", version})
+	if v.Bake.Dependency != nil {
+		tw.AppendRow(table.Row{"Dependency", *v.Bake.Dependency})
+	}
+	if v.Bake.Scale != 1 {
+		tw.AppendRow(table.Row{"Scale", strconv.FormatFloat(v.Bake.Scale, 'g', -1, 64)})
+	}
+	tw.AppendRow(table.Row{"Started", formatBakeTime(v.Bake.StartedAt)})
+	if v.Bake.FinishedAt != nil {
+		tw.AppendRow(table.Row{"Finished", formatBakeTime(*v.Bake.FinishedAt)})
+		tw.AppendRow(table.Row{"Took", recipe.FixedDuration(v.Bake.FinishedAt.Sub(v.Bake.StartedAt).Round(time.Minute)).Format()})
+	} else {
+		tw.AppendRow(table.Row{"Finished", "in progress"})
+	}
+	if v.Bake.Rating != nil {
+		tw.AppendRow(table.Row{"Rating", formatRating(v.Bake.Rating)})
+	}
+	if v.Bake.Notes != "" {
+		tw.AppendRow(table.Row{"Notes", v.Bake.Notes})
+	}
+	if err := renderTable(w, format, tw); err != nil {
+		return err
+	}
+
+	if len(v.Logs) > 0 {
+		tw := table.NewWriter()
+		tw.SetStyle(table.StyleLight)
+		tw.SetTitle(fmt.Sprintf("Log: Bake %d", v.Bake.ID))
+		tw.SetColumnConfigs([]table.ColumnConfig{
+			{Number: 2, WidthMax: 48},
+			{Number: 4, Align: text.AlignRight},
+			{Number: 5, Align: text.AlignRight},
+		})
+		tw.AppendHeader(table.Row{"Time", "What", "Stage", "Temperature", "Duration"})
+		for _, entry := range v.Logs {
+			var duration string
+			if entry.Minutes != nil {
+				duration = formatMinutes(*entry.Minutes)
+			}
+			tw.AppendRow(table.Row{
+				formatBakeTime(entry.LoggedAt),
+				entry.Text,
+				entry.Stage,
+				formatLogTemperature(entry.Temperature, entry.TemperatureScale),
+				duration,
+			})
+		}
+		if err := renderTable(w, format, tw); err != nil {
+			return err
+		}
+	}
+
+	if len(v.Photos) > 0 {
+		tw := table.NewWriter()
+		tw.SetStyle(table.StyleLight)
+		tw.SetTitle(fmt.Sprintf("Photos: Bake %d", v.Bake.ID))
+		tw.AppendHeader(table.Row{"#", "Path"})
+		for i, photo := range v.Photos {
+			tw.AppendRow(table.Row{i + 1, filepath.Join(v.PhotoDir, photo.Path)})
+		}
+		if err := renderTable(w, format, tw); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderBakes renders a list of bakes, latest first.
+func renderBakes(w io.Writer, format OutputFormat, title string, bakes []query.Bake) error {
+	tw := table.NewWriter()
+	tw.SetStyle(table.StyleLight)
+	tw.SetTitle(title)
+	tw.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 7, WidthMax: 40},
+	})
+
+	tw.AppendHeader(table.Row{"#", "Recipe", "Started", "Finished", "Rating", "Dependency", "Notes"})
+	for _, bake := range bakes {
+		finished := "in progress"
+		if bake.FinishedAt != nil {
+			finished = formatBakeTime(*bake.FinishedAt)
+		}
+		var dependency string
+		if bake.Dependency != nil {
+			dependency = *bake.Dependency
+		}
+
+		tw.AppendRow(table.Row{
+			bake.ID,
+			bake.RecipeName,
+			formatBakeTime(bake.StartedAt),
+			finished,
+			formatRating(bake.Rating),
+			dependency,
+			bake.Notes,
+		})
+	}
+
+	return renderTable(w, format, tw)
+}
+
+// recentBakes is the number of bakes of a recipe shown by view.
+const recentBakes = 3
+
+// recipeVersion returns the version of a recipe, the start of the SHA-256 of
+// the recipe as exported without its name and notes. It changes with the
+// ingredients and steps, but not when the recipe is renamed.
+func recipeVersion(r recipe.Recipe) (string, error) {
+	r.Name, r.Notes = "", ""
+
+	var buf bytes.Buffer
+	if err := bundle.Write(&buf, bundle.New([]recipe.Recipe{r})); err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:6]), nil
+}
+
+// formatDependency formats a dependency as parsed by recipe.ParseDependency,
+// e.g. "total_flour 1000g".
+func formatDependency(dep recipe.Dependency) string {
+	return fmt.Sprintf("%s %s%s", dep.Label, strconv.FormatFloat(dep.Value.Value, 'f', -1, 64), dep.Value.Unit)
+}
+
+// parseBakeTime parses when something happened during a bake, now if value
+// is empty, to the second. A time without a date, such as "07:30", is the last time it was
+// on the clock, unlike the times of schedule.
+func parseBakeTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return now.Truncate(time.Second), nil
+	}
+
+	t, err := parseClockTime(value, now)
+	if err != nil {
+		return t, err
+	}
+	if _, err := time.Parse("15:04", strings.TrimSpace(value)); err == nil && t.After(now) {
+		t = t.AddDate(0, 0, -1)
+	}
+	return t, nil
+}
+
+func formatBakeTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func formatLogTemperature(value *float64, scale recipe.TemperatureScale) string {
+	if value == nil {
+		return ""
+	}
+	return recipe.Temperature{Value: *value, Scale: scale}.Format()
+}
+
+func formatMinutes(minutes int64) string {
+	return recipe.FixedDuration(time.Duration(minutes) * time.Minute).Format()
+}
+
+// formatRating formats a rating as stars, e.g. "★★★★☆" for 4.
+func formatRating(rating *int64) string {
+	if rating == nil {
+		return ""
+	}
+	return strings.Repeat("★", int(*rating)) + strings.Repeat("☆", 5-int(*rating))
+}
+
+func firstArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
+
+// photoDir returns the directory photos of bakes are copied into.
+func photoDir(opts *RootCmdOptions) string {
+	return filepath.Join(opts.ConfigDir(), "photos")
+}
+
+// checkPhotos checks that the photos to attach to a bake are files.
+func checkPhotos(paths []string) error {
+	for _, path := range paths {
+		info, err := os.Stat(expandHome(path))
+		if err != nil {
+			return fmt.Errorf("photo: %w", err)
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("photo %s is not a file", path)
+		}
+	}
+	return nil
+}
+
+// addPhotos copies photos into a directory of the bake in dir and records
+// them. It returns the paths of the copies relative to dir, also those
+// copied before an error, for the caller to remove if the bake isn't
+// updated.
+func addPhotos(ctx context.Context, db *query.Queries, dir string, bakeID int64, paths []string) ([]string, error) {
+	var copied []string
+	for _, path := range paths {
+		photo, err := copyPhoto(expandHome(path), filepath.Join(dir, strconv.FormatInt(bakeID, 10)))
+		if err != nil {
+			return copied, fmt.Errorf("failed to copy photo %s: %w", path, err)
+		}
+
+		rel, err := filepath.Rel(dir, photo)
+		if err != nil {
+			return copied, err
+		}
+		copied = append(copied, rel)
+
+		if _, err := db.CreateBakePhoto(ctx, query.CreateBakePhotoParams{BakeID: bakeID, Path: filepath.ToSlash(rel)}); err != nil {
+			return copied, fmt.Errorf("failed to add photo %s: %w", path, err)
+		}
+	}
+	return copied, nil
+}
+
+// copyPhoto copies a file into dir, keeping its name unless a file of that
+// name exists, e.g. crumb-2.jpg for the second crumb.jpg. It returns the path
+// of the copy.
+func copyPhoto(src, dir string) (string, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	base := filepath.Base(src)
+	ext := filepath.Ext(base)
+	for n := 1; ; n++ {
+		name := base
+		if n > 1 {
+			name = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(base, ext), n, ext)
+		}
+
+		dst := filepath.Join(dir, name)
+		out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+
+		if _, err := io.Copy(out, in); err != nil {
+			out.Close()
+			os.Remove(dst)
+			return "", err
+		}
+		if err := out.Close(); err != nil {
+			os.Remove(dst)
+			return "", err
+		}
+		return dst, nil
+	}
+}
+
+// removePhotos removes copied photos, relative to dir.
+func removePhotos(dir string, photos []string) {
+	for _, photo := range photos {
+		os.Remove(filepath.Join(dir, photo))
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/peterbourgon/ff/v4"
+)
+
+type DBCmdOptions struct {
+	Root *RootCmdOptions
+}
+
+type DBCmd struct {
+	Opts DBCmdOptions
+
+	root    *RootCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func NewDBCmd(parent *RootCmd) *DBCmd {
+	var cmd DBCmd
+	cmd.Opts.Root = &parent.Opts
+	cmd.root = parent
+	cmd.Flags = ff.NewFlagSet("db").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "db",
+		Usage:     CmdLabel + " db <subcommand> ...",
+		ShortHelp: "manage the database schema",
+		Flags:     cmd.Flags,
+		Exec: func(ctx context.Context, args []string) error {
+			return ff.ErrHelp
+		},
+	}
+	cmd.root.Command.Subcommands = append(cmd.root.Command.Subcommands, cmd.Command)
+	_ = newDBMigrateCmd(&cmd)
+	_ = newDBStatusCmd(&cmd)
+
+	return &cmd
+}
+
+type dbMigrateCmd struct {
+	parent  *DBCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newDBMigrateCmd(parent *DBCmd) *dbMigrateCmd {
+	var cmd dbMigrateCmd
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("migrate").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "migrate",
+		Usage:     CmdLabel + " db migrate [flags]",
+		ShortHelp: "apply pending schema migrations",
+		Flags:     cmd.Flags,
+		Exec:      dbMigrateCmdExec(&parent.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func dbMigrateCmdExec(opts *DBCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		db, err := OpenStore(ctx, opts.Root.DBPath())
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		applied, err := Migrate(ctx, db, func(m Migration) {
+			fmt.Fprintf(opts.Root.Stdout, "applied %04d_%s\n", m.Version, m.Name)
+		})
+		if err != nil {
+			return err
+		}
+
+		// Migrate drops the search index triggers, recreate them.
+		if err := ensureSearchIndex(ctx, db); err != nil {
+			return err
+		}
+
+		if len(applied) == 0 {
+			fmt.Fprintln(opts.Root.Stdout, "database is up to date")
+		}
+
+		return nil
+	}
+}
+
+type dbStatusCmd struct {
+	parent  *DBCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newDBStatusCmd(parent *DBCmd) *dbStatusCmd {
+	var cmd dbStatusCmd
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("status").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "status",
+		Usage:     CmdLabel + " db status [flags]",
+		ShortHelp: "show applied and pending schema migrations",
+		Flags:     cmd.Flags,
+		Exec:      dbStatusCmdExec(&parent.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func dbStatusCmdExec(opts *DBCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		db, err := OpenStore(ctx, opts.Root.DBPath())
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		migrations, err := Migrations(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		format := opts.Root.OutputFormat()
+		if format.Structured() {
+			return renderData(opts.Root.Stdout, format, migrationStatusOutput(opts.Root.DBPath(), migrations))
+		}
+
+		tw := table.NewWriter()
+		tw.SetStyle(table.StyleLight)
+		tw.SetTitle("Schema Migrations")
+		tw.SetCaption("database: %s", opts.Root.DBPath())
+		tw.AppendHeader(table.Row{"Version", "Name", "Status"})
+		for _, m := range migrations {
+			status := "pending"
+			if m.Applied {
+				status = "applied"
+			}
+			tw.AppendRow(table.Row{m.Version, m.Name, status})
+		}
+
+		return renderTable(opts.Root.Stdout, format, tw)
+	}
+}
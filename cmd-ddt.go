@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/peterbourgon/ff/v4"
+	"github.com/simonklee/sourdough/recipe"
+)
+
+type DDTCmdOptions struct {
+	Target   string `validate:"required"`
+	Room     string `validate:"required"`
+	Flour    string
+	Starter  string
+	Friction string
+
+	Root *RootCmdOptions
+}
+
+type DDTCmd struct {
+	Opts DDTCmdOptions
+
+	root    *RootCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func NewDDTCmd(parent *RootCmd) *DDTCmd {
+	var cmd DDTCmd
+	cmd.Opts.Root = &parent.Opts
+	cmd.root = parent
+	cmd.Flags = ff.NewFlagSet("ddt").SetParent(parent.Flags)
+	cmd.Flags.StringVar(&cmd.Opts.Target, 't', "target", "", "desired dough temperature (e.g. 25C or 78F)")
+	cmd.Flags.StringVar(&cmd.Opts.Room, 'r', "room", "", "room temperature")
+	cmd.Flags.StringVar(&cmd.Opts.Flour, 'f', "flour", "", "flour temperature (default: room temperature)")
+	cmd.Flags.StringVar(&cmd.Opts.Starter, 's', "starter", "", "starter temperature, omit for doughs without starter")
+	cmd.Flags.StringVar(&cmd.Opts.Friction, 0, "friction", "", "temperature rise from mixing (default: 0 on the scale of the target)")
+
+	cmd.Command = &ff.Command{
+		Name:      "ddt",
+		Usage:     CmdLabel + " ddt [flags]",
+		ShortHelp: "calculate the water temperature for a desired dough temperature",
+		LongHelp: `  Calculates the water temperature needed to reach a desired
+  dough temperature (DDT) from the room, flour and starter
+  temperatures and the friction factor of the mixer.
+
+  Example:
+
+     $ sourdough ddt --target 25C --room 22C --starter 24C --friction 1C
+
+`,
+		Flags: cmd.Flags,
+		Exec:  DDTCmdExec(&cmd.Opts),
+	}
+	cmd.root.Command.Subcommands = append(cmd.root.Command.Subcommands, cmd.Command)
+	return &cmd
+}
+
+func DDTCmdExec(opts *DDTCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		validate := validator.New(validator.WithRequiredStructEnabled())
+		if err := validate.Struct(opts); err != nil {
+			return err
+		}
+
+		ddt, err := parseDDT(opts.Target, opts.Room, opts.Flour, opts.Starter, opts.Friction, opts.Starter != "")
+		if err != nil {
+			return err
+		}
+
+		water := recipe.WaterTemperature(ddt)
+
+		format := opts.Root.OutputFormat()
+		if format.Structured() {
+			return renderData(opts.Root.Stdout, format, ddtOutput(ddt, water))
+		}
+
+		tw := table.NewWriter()
+		tw.SetStyle(table.StyleLight)
+		tw.SetTitle("DDT")
+		tw.SetColumnConfigs([]table.ColumnConfig{
+			{Number: 2, Align: text.AlignRight},
+		})
+		tw.AppendRow(table.Row{"Target", ddt.Target.Format()})
+		tw.AppendRow(table.Row{"Room", ddt.Room.Format()})
+		tw.AppendRow(table.Row{"Flour", ddt.Flour.Format()})
+		if ddt.Starter != nil {
+			tw.AppendRow(table.Row{"Starter", ddt.Starter.Format()})
+		}
+		tw.AppendRow(table.Row{"Friction", ddt.Friction.Format()})
+		tw.AppendSeparator()
+		tw.AppendRow(table.Row{"Water", water.Format()})
+
+		return renderTable(opts.Root.Stdout, format, tw)
+	}
+}
+
+// parseDDT parses the temperatures of a DDT calculation. An empty flour or
+// starter temperature defaults to the room temperature, an empty friction
+// factor to no rise on the scale of the target, and the starter is only
+// included if withStarter is true.
+func parseDDT(target, room, flour, starter, friction string, withStarter bool) (recipe.DDT, error) {
+	var (
+		ddt recipe.DDT
+		err error
+	)
+
+	if ddt.Target, err = recipe.ParseTemperature(target); err != nil {
+		return ddt, fmt.Errorf("invalid target temperature: %w", err)
+	}
+
+	if ddt.Room, err = recipe.ParseTemperature(room); err != nil {
+		return ddt, fmt.Errorf("invalid room temperature: %w", err)
+	}
+
+	ddt.Flour = ddt.Room
+	if flour != "" {
+		if ddt.Flour, err = recipe.ParseTemperature(flour); err != nil {
+			return ddt, fmt.Errorf("invalid flour temperature: %w", err)
+		}
+	}
+
+	if withStarter {
+		s := ddt.Room
+		if starter != "" {
+			if s, err = recipe.ParseTemperature(starter); err != nil {
+				return ddt, fmt.Errorf("invalid starter temperature: %w", err)
+			}
+		}
+		ddt.Starter = &s
+	}
+
+	ddt.Friction = recipe.Temperature{Scale: ddt.Target.Scale}
+	if friction != "" {
+		if ddt.Friction, err = recipe.ParseTemperature(friction); err != nil {
+			return ddt, fmt.Errorf("invalid friction factor: %w", err)
+		}
+	}
+
+	return ddt, nil
+}
@@ -0,0 +1,503 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/peterbourgon/ff/v4"
+	"github.com/simonklee/sourdough/query"
+	"github.com/simonklee/sourdough/recipe"
+)
+
+type EditCmdOptions struct {
+	Root *RootCmdOptions
+}
+
+type EditCmd struct {
+	Opts EditCmdOptions
+
+	root    *RootCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func NewEditCmd(parent *RootCmd) *EditCmd {
+	var cmd EditCmd
+	cmd.Opts.Root = &parent.Opts
+	cmd.root = parent
+	cmd.Flags = ff.NewFlagSet("edit").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "edit",
+		Usage:     CmdLabel + " edit <subcommand> ...",
+		ShortHelp: "edit recipes and ingredients",
+		Flags:     cmd.Flags,
+		Exec: func(ctx context.Context, args []string) error {
+			return ff.ErrHelp
+		},
+	}
+	cmd.root.Command.Subcommands = append(cmd.root.Command.Subcommands, cmd.Command)
+	_ = newEditRecipeCmd(&cmd)
+	_ = newEditIngredientCmd(&cmd)
+	_ = newEditStepCmd(&cmd)
+	_ = newEditCatalogCmd(&cmd)
+
+	return &cmd
+}
+
+// isSet returns true if the flag with the given long name was set.
+func isSet(fs *ff.FlagSet, name string) bool {
+	f, ok := fs.GetFlag(name)
+	return ok && f.IsSet()
+}
+
+type EditRecipeCmdOptions struct {
+	Name  string
+	Notes string
+
+	Parent *EditCmdOptions
+}
+
+type editRecipeCmd struct {
+	Opts EditRecipeCmdOptions
+
+	parent  *EditCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newEditRecipeCmd(parent *EditCmd) *editRecipeCmd {
+	var cmd editRecipeCmd
+	cmd.Opts.Parent = &parent.Opts
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("recipe").SetParent(parent.Flags)
+	cmd.Flags.StringVar(&cmd.Opts.Name, 'n', "name", "", "name of the recipe")
+	cmd.Flags.StringVar(&cmd.Opts.Notes, 0, "notes", "", "notes of the recipe")
+	cmd.Command = &ff.Command{
+		Name:      "recipe",
+		Usage:     CmdLabel + " edit recipe <recipe> [flags]",
+		ShortHelp: "edit a recipe",
+		Flags:     cmd.Flags,
+		Exec:      editRecipeCmdExec(&cmd.Opts, cmd.Flags),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func editRecipeCmdExec(opts *EditRecipeCmdOptions, fs *ff.FlagSet) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return errors.New("requires a recipe ID or name")
+		}
+
+		db, err := opts.Parent.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		r, err := findRecipe(ctx, db, args[0])
+		if err != nil {
+			return err
+		}
+
+		params := query.UpdateRecipeParams{
+			Name:  r.Name,
+			Notes: r.Notes,
+			ID:    r.ID,
+		}
+		if isSet(fs, "name") {
+			params.Name = opts.Name
+		}
+		if isSet(fs, "notes") {
+			params.Notes = opts.Notes
+		}
+
+		r, err = db.UpdateRecipe(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to update recipe %d: %w", params.ID, err)
+		}
+
+		fmt.Fprintf(opts.Parent.Root.Stdout, "updated recipe %d %s\n", r.ID, r.Name)
+		return nil
+	}
+}
+
+type EditIngredientCmdOptions struct {
+	PreferUnitCategory string
+	Percentage         float64
+	Amount             string
+	Dependency         string
+	Group              string
+
+	Parent *EditCmdOptions
+}
+
+type editIngredientCmd struct {
+	Opts EditIngredientCmdOptions
+
+	parent  *EditCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newEditIngredientCmd(parent *EditCmd) *editIngredientCmd {
+	var cmd editIngredientCmd
+	cmd.Opts.Parent = &parent.Opts
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("ingredient").SetParent(parent.Flags)
+	cmd.Flags.StringEnumVar(&cmd.Opts.PreferUnitCategory, 'u', "unit", "preferred output unit category", "weight", "volume", "count", "teaspoon")
+	cmd.Flags.Float64Var(&cmd.Opts.Percentage, 'p', "percentage", 0.0, "baker's percentage of the ingredient (e.g. 1.05 for 105%)")
+	cmd.Flags.StringVar(&cmd.Opts.Amount, 'a', "amount", "", "fixed amount of the ingredient (e.g. 10g, 2pc)")
+	cmd.Flags.StringVar(&cmd.Opts.Dependency, 'd', "dependency", "", "dependency of the ingredient (total_flour, total_water, total_dough, per_piece or an ingredient name)")
+	cmd.Flags.StringVar(&cmd.Opts.Group, 'g', "group", "", "group of the ingredient (e.g. topping), or none")
+	cmd.Command = &ff.Command{
+		Name:      "ingredient",
+		Usage:     CmdLabel + " edit ingredient <recipe-ingredient> [flags]",
+		ShortHelp: "edit an ingredient of a recipe",
+		LongHelp: `  Edits an ingredient of a recipe by the ID shown in the #
+  column of view. Only the given flags are changed. Setting
+  --percentage switches a fixed amount back to a percentage,
+  and --amount switches a percentage to a fixed amount.
+  Use --group none to remove the ingredient from its group.
+
+  Example:
+
+     $ sourdough edit ingredient --percentage .72 3
+     $ sourdough edit ingredient --group Topping 7
+
+`,
+		Flags: cmd.Flags,
+		Exec:  editIngredientCmdExec(&cmd.Opts, cmd.Flags),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func editIngredientCmdExec(opts *EditIngredientCmdOptions, fs *ff.FlagSet) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return errors.New("requires a recipe ingredient ID")
+		}
+
+		if isSet(fs, "percentage") && isSet(fs, "amount") {
+			return errors.New("--percentage and --amount are mutually exclusive")
+		}
+
+		db, err := opts.Parent.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		ri, err := findRecipeIngredient(ctx, db, args[0])
+		if err != nil {
+			return err
+		}
+
+		params := query.UpdateRecipeIngredientParams{
+			PreferUnitCategory: ri.PreferUnitCategory,
+			Percentage:         ri.Percentage,
+			Dependency:         ri.Dependency,
+			Mode:               ri.Mode,
+			Amount:             ri.Amount,
+			Unit:               ri.Unit,
+			IngredientID:       ri.IngredientID,
+			GroupName:          ri.GroupName,
+			ID:                 ri.ID,
+		}
+		if isSet(fs, "unit") {
+			params.PreferUnitCategory = recipe.UnitCategory(opts.PreferUnitCategory)
+		}
+		if isSet(fs, "dependency") {
+			params.Dependency = opts.Dependency
+		}
+		if isSet(fs, "group") {
+			params.GroupName = nil
+			if opts.Group != "none" && opts.Group != "" {
+				params.GroupName = &opts.Group
+			}
+		}
+		if isSet(fs, "percentage") {
+			if opts.Percentage <= 0 {
+				return fmt.Errorf("invalid percentage: %v", opts.Percentage)
+			}
+			params.Mode = recipe.ModePercentage
+			params.Percentage = opts.Percentage
+			params.Amount = nil
+			params.Unit = ""
+		}
+		if isSet(fs, "amount") {
+			amount, err := recipe.ParseTuple(opts.Amount)
+			if err != nil {
+				return err
+			}
+			params.Mode = recipe.ModeAmount
+			params.Percentage = 0
+			params.Dependency = ""
+			params.Amount = &amount.Value
+			params.Unit = amount.Unit
+		}
+
+		if params.Mode == recipe.ModePercentage && params.Dependency == "" {
+			return errors.New("a percentage requires a --dependency")
+		}
+
+		if _, err := db.UpdateRecipeIngredient(ctx, params); err != nil {
+			return fmt.Errorf("failed to update recipe ingredient %d: %w", ri.ID, err)
+		}
+
+		fmt.Fprintf(opts.Parent.Root.Stdout, "updated recipe ingredient %d\n", ri.ID)
+		return nil
+	}
+}
+
+type EditStepCmdOptions struct {
+	Text        string
+	Duration    string
+	Temperature string
+	Ingredients []string
+
+	Parent *EditCmdOptions
+}
+
+type editStepCmd struct {
+	Opts EditStepCmdOptions
+
+	parent  *EditCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newEditStepCmd(parent *EditCmd) *editStepCmd {
+	var cmd editStepCmd
+	cmd.Opts.Parent = &parent.Opts
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("step").SetParent(parent.Flags)
+	cmd.Flags.StringVar(&cmd.Opts.Text, 't', "text", "", "what to do in the step")
+	cmd.Flags.StringVar(&cmd.Opts.Duration, 'd', "duration", "", "duration or range of the step (e.g. 30m, 12-16h), or none")
+	cmd.Flags.StringVar(&cmd.Opts.Temperature, 0, "temp", "", "temperature of the step (e.g. 24C), or none")
+	cmd.Flags.StringListVar(&cmd.Opts.Ingredients, 'i', "ingredient", "ID or name of an ingredient added in the step, replacing the current ones, or none")
+	cmd.Command = &ff.Command{
+		Name:      "step",
+		Usage:     CmdLabel + " edit step <step> [flags]",
+		ShortHelp: "edit a step of the method of a recipe",
+		LongHelp: `  Edits a step by the ID shown in the # column of the method
+  of view. Only the given flags are changed, and the given
+  ingredients replace the ingredients of the step.
+
+  Example:
+
+     $ sourdough edit step --duration 4-5h --temp 24C 3
+     $ sourdough edit step --ingredient Salt --ingredient 5 2
+
+`,
+		Flags: cmd.Flags,
+		Exec:  editStepCmdExec(&cmd.Opts, cmd.Flags),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func editStepCmdExec(opts *EditStepCmdOptions, fs *ff.FlagSet) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return errors.New("requires a step ID")
+		}
+
+		db, err := opts.Parent.Root.SetupDB(ctx)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		return InTx(ctx, db, func(q *query.Queries) error {
+			row, err := findStep(ctx, q, args[0])
+			if err != nil {
+				return err
+			}
+
+			step := stepFromRow(row, nil)
+			if isSet(fs, "text") {
+				if step.Text = strings.TrimSpace(opts.Text); step.Text == "" {
+					return errors.New("the text of a step can't be empty")
+				}
+			}
+			if isSet(fs, "duration") {
+				step.Duration = recipe.DurationRange{}
+				if opts.Duration != "none" {
+					if step.Duration, err = recipe.ParseDurationRange(opts.Duration); err != nil {
+						return err
+					}
+				}
+			}
+			if isSet(fs, "temp") {
+				step.Temperature = nil
+				if opts.Temperature != "none" {
+					t, err := recipe.ParseTemperature(opts.Temperature)
+					if err != nil {
+						return err
+					}
+					step.Temperature = &t
+				}
+			}
+
+			params := query.UpdateStepParams{Text: step.Text, ID: row.ID}
+			params.DurationMin, params.DurationMax = durationColumns(step.Duration)
+			params.Temperature, params.TemperatureScale = temperatureColumns(step.Temperature)
+			if _, err := q.UpdateStep(ctx, params); err != nil {
+				return fmt.Errorf("failed to update step %d: %w", row.ID, err)
+			}
+
+			if isSet(fs, "ingredient") {
+				var values []string
+				for _, value := range opts.Ingredients {
+					if value != "none" {
+						values = append(values, value)
+					}
+				}
+
+				ids, err := findStepIngredients(ctx, q, row.RecipeID, values)
+				if err != nil {
+					return err
+				}
+				if err := q.DeleteStepIngredients(ctx, row.ID); err != nil {
+					return fmt.Errorf("failed to update ingredients of step %d: %w", row.ID, err)
+				}
+				if err := addStepIngredients(ctx, q, row.ID, ids); err != nil {
+					return err
+				}
+			}
+
+			fmt.Fprintf(opts.Parent.Root.Stdout, "updated step %d\n", row.ID)
+			return nil
+		})
+	}
+}
+
+type EditCatalogCmdOptions struct {
+	Kind        string
+	Hydration   string
+	Density     string
+	PieceWeight string
+
+	Parent *EditCmdOptions
+}
+
+type editCatalogCmd struct {
+	Opts EditCatalogCmdOptions
+
+	parent  *EditCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newEditCatalogCmd(parent *EditCmd) *editCatalogCmd {
+	var cmd editCatalogCmd
+	cmd.Opts.Parent = &parent.Opts
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("catalog").SetParent(parent.Flags)
+	cmd.Flags.StringVar(&cmd.Opts.Kind, 'k', "kind", "", "kind of the ingredient")
+	cmd.Flags.StringVar(&cmd.Opts.Hydration, 0, "hydration", "", "hydration of a sourdough starter (e.g. 1 for 100%), or none")
+	cmd.Flags.StringVar(&cmd.Opts.Density, 0, "density", "", "density in g/ml, or none")
+	cmd.Flags.StringVar(&cmd.Opts.PieceWeight, 0, "piece-weight", "", "weight of a single piece (e.g. 50g), or none")
+	cmd.Command = &ff.Command{
+		Name:      "catalog",
+		Usage:     CmdLabel + " edit catalog <ingredient> [flags]",
+		ShortHelp: "edit an ingredient of the catalog shared by all recipes",
+		Flags:     cmd.Flags,
+		Exec:      editCatalogCmdExec(&cmd.Opts, cmd.Flags),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func editCatalogCmdExec(opts *EditCatalogCmdOptions, fs *ff.FlagSet) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return errors.New("requires an ingredient ID or name")
+		}
+
+		// Parse everything up front so a typo doesn't leave the
+		// ingredient half updated.
+		var (
+			hydration, density, weight *float64
+			err                        error
+		)
+		if isSet(fs, "hydration") && opts.Hydration != "none" {
+			v, err := strconv.ParseFloat(opts.Hydration, 64)
+			if err != nil || v < 0 {
+				return fmt.Errorf("invalid hydration: %s", opts.Hydration)
+			}
+			hydration = &v
+		}
+		if isSet(fs, "density") {
+			if density, err = parseDensity(opts.Density); err != nil {
+				return err
+			}
+		}
+		if isSet(fs, "piece-weight") {
+			if weight, err = parsePieceWeight(opts.PieceWeight); err != nil {
+				return err
+			}
+		}
+
+		db, err := opts.Parent.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		ingredient, err := findIngredient(ctx, db, args[0])
+		if err != nil {
+			return err
+		}
+
+		if isSet(fs, "kind") {
+			ingredient, err = db.UpdateIngredient(ctx, query.UpdateIngredientParams{
+				Name: ingredient.Name,
+				Kind: recipe.Kind(opts.Kind),
+				ID:   ingredient.ID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to set kind of %s: %w", ingredient.Name, err)
+			}
+		}
+
+		if isSet(fs, "hydration") {
+			ingredient, err = db.SetIngredientHydration(ctx, query.SetIngredientHydrationParams{
+				Hydration: hydration,
+				ID:        ingredient.ID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to set hydration of %s: %w", ingredient.Name, err)
+			}
+		}
+
+		if isSet(fs, "density") {
+			ingredient, err = db.SetIngredientDensity(ctx, query.SetIngredientDensityParams{
+				Density: density,
+				ID:      ingredient.ID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to set density of %s: %w", ingredient.Name, err)
+			}
+		}
+
+		if isSet(fs, "piece-weight") {
+			ingredient, err = db.SetIngredientPieceWeight(ctx, query.SetIngredientPieceWeightParams{
+				PieceWeight: weight,
+				ID:          ingredient.ID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to set piece weight of %s: %w", ingredient.Name, err)
+			}
+		}
+
+		fmt.Fprintf(opts.Parent.Root.Stdout, "updated ingredient %d %s\n", ingredient.ID, ingredient.Name)
+		return nil
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peterbourgon/ff/v4"
+	"github.com/simonklee/sourdough/bundle"
+	"github.com/simonklee/sourdough/cooklang"
+	"github.com/simonklee/sourdough/query"
+	"github.com/simonklee/sourdough/recipe"
+	"github.com/simonklee/sourdough/schemaorg"
+)
+
+type ExportCmdOptions struct {
+	Format       string
+	Dependencies []string
+
+	Root *RootCmdOptions
+}
+
+type ExportCmd struct {
+	Opts ExportCmdOptions
+
+	root    *RootCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func NewExportCmd(parent *RootCmd) *ExportCmd {
+	var cmd ExportCmd
+	cmd.Opts.Root = &parent.Opts
+	cmd.root = parent
+	cmd.Flags = ff.NewFlagSet("export").SetParent(parent.Flags)
+	cmd.Flags.StringEnumVar(&cmd.Opts.Format, 0, "format", "format of the file: yaml, cooklang or jsonld", "yaml", "cooklang", "jsonld")
+	cmd.Flags.StringListVar(&cmd.Opts.Dependencies, 'd', "dependency", "dependency of a cooklang or jsonld export (e.g. --dependency \"total_flour 450g\")")
+	cmd.Command = &ff.Command{
+		Name:      "export",
+		Usage:     CmdLabel + " export [flags] [recipe...]",
+		ShortHelp: "export recipes to a portable file",
+		LongHelp: `  Writes the given recipes, or all recipes, and the ingredients
+  they use to stdout in a versioned YAML format which can be
+  read by import.
+
+  With --format cooklang or jsonld a single recipe is calculated
+  for the given dependencies, like view. Cooklang markup has a
+  section for each stage. JSON-LD is a schema.org Recipe with the
+  overall formula as its ingredients and a step for each stage,
+  ready to embed in a <script type="application/ld+json">.
+
+  Example:
+
+     $ sourdough export > recipes.yaml
+     $ sourdough export Country Brioche > breads.yaml
+     $ sourdough export --format cooklang --dependency "total_flour 500g" Country
+     $ sourdough export --format jsonld --dependency "total_dough 1800g" Country
+
+`,
+		Flags: cmd.Flags,
+		Exec:  ExportCmdExec(&cmd.Opts),
+	}
+	cmd.root.Command.Subcommands = append(cmd.root.Command.Subcommands, cmd.Command)
+	return &cmd
+}
+
+func ExportCmdExec(opts *ExportCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if opts.Format != "yaml" {
+			return exportFormula(ctx, opts, args)
+		}
+
+		db, err := opts.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		var recipes []query.Recipe
+		if len(args) == 0 {
+			if recipes, err = db.ListRecipes(ctx); err != nil {
+				return err
+			}
+		}
+		for _, arg := range args {
+			r, err := findRecipe(ctx, db, arg)
+			if err != nil {
+				return err
+			}
+			recipes = append(recipes, r)
+		}
+
+		templates := make([]recipe.Recipe, 0, len(recipes))
+		for _, r := range recipes {
+			template, err := loadRecipe(ctx, db, r)
+			if err != nil {
+				return err
+			}
+			templates = append(templates, template)
+		}
+
+		return bundle.Write(opts.Root.Stdout, bundle.New(templates))
+	}
+}
+
+// exportFormula writes a single recipe calculated for the dependencies of
+// opts in Cooklang markup or JSON-LD.
+func exportFormula(ctx context.Context, opts *ExportCmdOptions, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%s export requires a single recipe ID or name", opts.Format)
+	}
+
+	dependencies, err := recipe.ParseDependencies(opts.Dependencies)
+	if err != nil {
+		return err
+	}
+	if len(dependencies) == 0 {
+		return fmt.Errorf("%s export requires a dependency, e.g. --dependency \"total_flour 500g\"", opts.Format)
+	}
+
+	db, err := opts.Root.SetupStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	r, err := findRecipe(ctx, db, args[0])
+	if err != nil {
+		return err
+	}
+
+	template, err := loadRecipe(ctx, db, r)
+	if err != nil {
+		return err
+	}
+
+	var stages []recipe.StagePortion
+	if recipe.IsStaged(template.Ingredients) {
+		stages, err = recipe.CalculateStages(template.Ingredients, dependencies, 1)
+	} else {
+		var portions []recipe.PortionIngredient
+		portions, err = recipe.Calculate(template.Ingredients, dependencies)
+		stages = []recipe.StagePortion{{Name: recipe.FinalDough, Ingredients: portions}}
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.Format == "jsonld" {
+		return schemaorg.Write(opts.Root.Stdout, schemaorg.FromStages(r.Name, r.Notes, stages))
+	}
+	return cooklang.Write(opts.Root.Stdout, cooklang.FromStages(r.Name, r.Notes, stages))
+}
+
+// loadRecipe returns the template of a recipe.
+func loadRecipe(ctx context.Context, db *query.Queries, r query.Recipe) (recipe.Recipe, error) {
+	rows, err := db.ListRecipeIngredients(ctx, r.ID)
+	if err != nil {
+		return recipe.Recipe{}, err
+	}
+
+	template := recipe.Recipe{
+		Name:        r.Name,
+		Notes:       r.Notes,
+		Ingredients: make([]recipe.RecipeIngredient, 0, len(rows)),
+	}
+	for _, row := range rows {
+		template.Ingredients = append(template.Ingredients, recipeIngredientFromRow(row))
+	}
+
+	steps, err := loadSteps(ctx, db, r.ID)
+	if err != nil {
+		return recipe.Recipe{}, err
+	}
+	for _, step := range steps {
+		template.Steps = append(template.Steps, step.Step)
+	}
+	return template, nil
+}
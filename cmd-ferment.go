@@ -0,0 +1,333 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/peterbourgon/ff/v4"
+	"github.com/simonklee/sourdough/query"
+	"github.com/simonklee/sourdough/recipe"
+)
+
+type FermentCmdOptions struct {
+	Temp          string
+	ProofTemp     string
+	Starter       string
+	Flour         string
+	ObservedBulk  string
+	ObservedProof string
+
+	Root *RootCmdOptions
+}
+
+type FermentCmd struct {
+	Opts FermentCmdOptions
+
+	root    *RootCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func NewFermentCmd(parent *RootCmd) *FermentCmd {
+	var cmd FermentCmd
+	cmd.Opts.Root = &parent.Opts
+	cmd.root = parent
+	cmd.Flags = ff.NewFlagSet("ferment-estimate").SetParent(parent.Flags)
+	cmd.Flags.StringVar(&cmd.Opts.Temp, 't', "temp", "", "dough temperature during bulk fermentation (e.g. 24C)")
+	cmd.Flags.StringVar(&cmd.Opts.ProofTemp, 0, "proof-temp", "", "temperature during the proof (default: --temp)")
+	cmd.Flags.StringVar(&cmd.Opts.Starter, 's', "starter", "", "baker's percentage of starter, e.g. 20% (default: the recipe's or 20%)")
+	cmd.Flags.StringVar(&cmd.Opts.Flour, 'f', "flour", "", "flour type: white, whole-wheat or rye (default: the recipe's or white)")
+	cmd.Flags.StringVar(&cmd.Opts.ObservedBulk, 0, "observed-bulk", "", "record how long the bulk fermentation took, calibrating the estimates (e.g. 5h30m)")
+	cmd.Flags.StringVar(&cmd.Opts.ObservedProof, 0, "observed-proof", "", "record how long the proof took, calibrating the estimates")
+
+	cmd.Command = &ff.Command{
+		Name:      "ferment-estimate",
+		Usage:     CmdLabel + " ferment-estimate [flags] [recipe]",
+		ShortHelp: "estimate bulk fermentation and proof times",
+		LongHelp: `  Estimates how long the bulk fermentation and the proof take
+  from the dough temperature, the baker's percentage of
+  starter and the type of flour. With a recipe its starter
+  and main flour are used.
+
+  Fermentation is about 2.5 times slower for every 10°C
+  colder (Q10), takes twice as long with a quarter of the
+  starter, and is faster with whole wheat and rye flour.
+  Starting from a white dough with 20% starter which bulk
+  ferments in 5h and proofs in 2h at 24°C.
+
+  The model is calibrated by recording the times of your
+  own bakes with --observed-bulk and --observed-proof. With
+  bulk times observed at different temperatures Q10 is
+  fitted as well.
+
+  Example:
+
+     $ sourdough ferment-estimate --temp 26C --starter 15%
+     $ sourdough ferment-estimate --temp 25C --proof-temp 21C Country
+     $ sourdough ferment-estimate --temp 23C --observed-bulk 6h15m Country
+
+`,
+		Flags: cmd.Flags,
+		Exec:  FermentCmdExec(&cmd.Opts),
+	}
+	cmd.root.Command.Subcommands = append(cmd.root.Command.Subcommands, cmd.Command)
+	return &cmd
+}
+
+func FermentCmdExec(opts *FermentCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) > 1 {
+			return errors.New("requires at most one recipe ID or name")
+		}
+		if opts.Temp == "" {
+			return errors.New("requires the dough temperature, e.g. --temp 24C")
+		}
+
+		temp, err := recipe.ParseTemperature(opts.Temp)
+		if err != nil {
+			return fmt.Errorf("invalid dough temperature: %w", err)
+		}
+		proofTemp := temp
+		if opts.ProofTemp != "" {
+			if proofTemp, err = recipe.ParseTemperature(opts.ProofTemp); err != nil {
+				return fmt.Errorf("invalid proof temperature: %w", err)
+			}
+		}
+
+		observed := map[recipe.FermentStage]time.Duration{}
+		for stage, value := range map[recipe.FermentStage]string{
+			recipe.FermentBulk:  opts.ObservedBulk,
+			recipe.FermentProof: opts.ObservedProof,
+		} {
+			if value == "" {
+				continue
+			}
+			d, err := recipe.ParseDurationRange(value)
+			if err != nil || d.Min != d.Max {
+				return fmt.Errorf("invalid observed %s time %s, expected a duration such as 5h30m", stage, value)
+			}
+			observed[stage] = d.Min
+		}
+
+		db, err := opts.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		var (
+			r *query.Recipe
+			f = recipe.Fermentation{Temperature: temp, Starter: recipe.DefaultFermentModel.Reference.Starter, Flour: recipe.FlourWhite}
+		)
+		if len(args) == 1 {
+			found, err := findRecipe(ctx, db, args[0])
+			if err != nil {
+				return err
+			}
+			r = &found
+
+			if f, err = recipeFermentation(ctx, db, r.ID, temp); err != nil {
+				return err
+			}
+		}
+		if opts.Starter != "" {
+			if f.Starter, err = parsePercentage(opts.Starter); err != nil {
+				return fmt.Errorf("invalid starter: %w", err)
+			}
+		}
+		if opts.Flour != "" {
+			if f.Flour, err = recipe.ParseFlourType(opts.Flour); err != nil {
+				return err
+			}
+		}
+		if f.Starter <= 0 {
+			return errors.New("the dough has no starter, give its baker's percentage with --starter")
+		}
+
+		for _, stage := range []recipe.FermentStage{recipe.FermentBulk, recipe.FermentProof} {
+			d, ok := observed[stage]
+			if !ok {
+				continue
+			}
+
+			observation := f
+			if stage == recipe.FermentProof {
+				observation.Temperature = proofTemp
+			}
+			if err := logFerment(ctx, db, r, stage, observation, d); err != nil {
+				return err
+			}
+			fmt.Fprintf(opts.Root.Stderr, "recorded %s of %s at %s\n", stage, recipe.FixedDuration(d).Format(), observation.Temperature.Format())
+		}
+
+		model, err := fermentModel(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		estimate, err := newFermentEstimate(model, r, f, proofTemp)
+		if err != nil {
+			return err
+		}
+
+		format := opts.Root.OutputFormat()
+		if format.Structured() {
+			return renderData(opts.Root.Stdout, format, estimate.Output())
+		}
+
+		tw := table.NewWriter()
+		tw.SetStyle(table.StyleLight)
+		tw.SetTitle("Fermentation")
+		if r != nil {
+			tw.SetTitle(fmt.Sprintf("Fermentation: %s", r.Name))
+		}
+		tw.SetColumnConfigs([]table.ColumnConfig{
+			{Number: 2, Align: text.AlignRight},
+		})
+		tw.AppendRow(table.Row{"Dough temperature", f.Temperature.Format()})
+		tw.AppendRow(table.Row{"Proof temperature", proofTemp.Format()})
+		tw.AppendRow(table.Row{"Starter", formatPercentage(f.Starter)})
+		tw.AppendRow(table.Row{"Flour", f.Flour})
+		tw.AppendSeparator()
+		tw.AppendRow(table.Row{"Bulk", recipe.FixedDuration(estimate.BulkTime).Format()})
+		tw.AppendRow(table.Row{"Proof", recipe.FixedDuration(estimate.ProofTime).Format()})
+		tw.AppendSeparator()
+		tw.AppendRow(table.Row{"Q10", fmt.Sprintf("%.2f", model.Q10)})
+		tw.AppendRow(table.Row{"Observations", model.Observations})
+
+		return renderTable(opts.Root.Stdout, format, tw)
+	}
+}
+
+// FermentEstimate is the estimated bulk fermentation and proof times of a
+// dough. Recipe is nil if the dough isn't of a recipe.
+type FermentEstimate struct {
+	Recipe    *query.Recipe
+	Bulk      recipe.Fermentation
+	Proof     recipe.Fermentation
+	BulkTime  time.Duration
+	ProofTime time.Duration
+	Model     recipe.FermentModel
+}
+
+// estimateFerment returns the estimated fermentation times of a recipe, nil
+// if the recipe has no starter.
+func estimateFerment(ctx context.Context, db *query.Queries, r query.Recipe, bulk, proof recipe.Temperature) (*FermentEstimate, error) {
+	f, err := recipeFermentation(ctx, db, r.ID, bulk)
+	if err != nil || f.Starter == 0 {
+		return nil, err
+	}
+
+	model, err := fermentModel(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate, err := newFermentEstimate(model, &r, f, proof)
+	if err != nil {
+		return nil, err
+	}
+	return &estimate, nil
+}
+
+// newFermentEstimate estimates the bulk fermentation time of a dough and the
+// proof time at the proof temperature.
+func newFermentEstimate(model recipe.FermentModel, r *query.Recipe, f recipe.Fermentation, proofTemp recipe.Temperature) (FermentEstimate, error) {
+	estimate := FermentEstimate{Recipe: r, Bulk: f, Proof: f, Model: model}
+	estimate.Proof.Temperature = proofTemp
+
+	var err error
+	if estimate.BulkTime, err = model.Estimate(recipe.FermentBulk, estimate.Bulk); err != nil {
+		return estimate, err
+	}
+	if estimate.ProofTime, err = model.Estimate(recipe.FermentProof, estimate.Proof); err != nil {
+		return estimate, err
+	}
+	return estimate, nil
+}
+
+// fermentModel returns the default fermentation model calibrated by the
+// recorded observations.
+func fermentModel(ctx context.Context, db *query.Queries) (recipe.FermentModel, error) {
+	rows, err := db.ListFermentLogs(ctx)
+	if err != nil {
+		return recipe.FermentModel{}, err
+	}
+
+	observations := make([]recipe.FermentObservation, 0, len(rows))
+	for _, row := range rows {
+		observations = append(observations, recipe.FermentObservation{
+			Stage: row.Stage,
+			Fermentation: recipe.Fermentation{
+				Temperature: recipe.Temperature{Value: row.Temperature, Scale: row.TemperatureScale},
+				Starter:     row.Starter,
+				Flour:       row.Flour,
+			},
+			Duration: time.Duration(row.Minutes) * time.Minute,
+		})
+	}
+	return recipe.DefaultFermentModel.Calibrate(observations), nil
+}
+
+// logFerment records an observed fermentation time, of a recipe if r isn't
+// nil.
+func logFerment(ctx context.Context, db *query.Queries, r *query.Recipe, stage recipe.FermentStage, f recipe.Fermentation, d time.Duration) error {
+	params := query.CreateFermentLogParams{
+		Stage:            stage,
+		Temperature:      f.Temperature.Value,
+		TemperatureScale: f.Temperature.Scale,
+		Starter:          f.Starter,
+		Flour:            f.Flour,
+		Minutes:          int64(d.Round(time.Minute) / time.Minute),
+	}
+	if r != nil {
+		params.RecipeID = &r.ID
+	}
+
+	if _, err := db.CreateFermentLog(ctx, params); err != nil {
+		return fmt.Errorf("failed to record %s time: %w", stage, err)
+	}
+	return nil
+}
+
+// recipeFermentation returns the fermentation conditions of the final dough
+// of a recipe at a dough temperature.
+func recipeFermentation(ctx context.Context, db *query.Queries, recipeID int64, t recipe.Temperature) (recipe.Fermentation, error) {
+	rows, err := db.ListRecipeIngredients(ctx, recipeID)
+	if err != nil {
+		return recipe.Fermentation{}, err
+	}
+
+	ingredients := make([]recipe.RecipeIngredient, 0, len(rows))
+	for _, row := range rows {
+		ingredients = append(ingredients, recipeIngredientFromRow(row))
+	}
+
+	portions, err := finalDough(ingredients)
+	if err != nil {
+		return recipe.Fermentation{}, err
+	}
+	return recipe.FermentationOf(portions, t), nil
+}
+
+// finalDough returns the final dough of a recipe calculated for a nominal
+// amount of flour. Only the ratios of the result are meaningful.
+func finalDough(ingredients []recipe.RecipeIngredient) ([]recipe.PortionIngredient, error) {
+	if !recipe.IsStaged(ingredients) {
+		return recipe.Calculate(ingredients, []recipe.Dependency{nominalTotalFlour})
+	}
+
+	stages, err := recipe.CalculateStages(ingredients, []recipe.Dependency{nominalTotalFlour}, 1)
+	if err != nil {
+		return nil, err
+	}
+	for _, stage := range stages {
+		if stage.Name == recipe.FinalDough {
+			return stage.Ingredients, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,395 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/peterbourgon/ff/v4"
+	"github.com/simonklee/sourdough/bundle"
+	"github.com/simonklee/sourdough/cooklang"
+	"github.com/simonklee/sourdough/query"
+	"github.com/simonklee/sourdough/recipe"
+	"github.com/simonklee/sourdough/schemaorg"
+)
+
+type ImportCmdOptions struct {
+	DryRun     bool
+	OnConflict string
+	Format     string
+
+	Root *RootCmdOptions
+}
+
+type ImportCmd struct {
+	Opts ImportCmdOptions
+
+	root    *RootCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func NewImportCmd(parent *RootCmd) *ImportCmd {
+	var cmd ImportCmd
+	cmd.Opts.Root = &parent.Opts
+	cmd.root = parent
+	cmd.Flags = ff.NewFlagSet("import").SetParent(parent.Flags)
+	cmd.Flags.BoolVar(&cmd.Opts.DryRun, 0, "dry-run", "show what would be imported without changing the database")
+	cmd.Flags.StringEnumVar(&cmd.Opts.Format, 0, "format", "format of the file: yaml, cooklang or jsonld", "yaml", "cooklang", "jsonld")
+	cmd.Flags.StringEnumVar(&cmd.Opts.OnConflict, 0, "on-conflict", "what to do with a recipe whose name exists: error, skip, replace or rename", "error", "skip", "replace", "rename")
+	cmd.Command = &ff.Command{
+		Name:      "import",
+		Usage:     CmdLabel + " import [flags] [file]",
+		ShortHelp: "import recipes from a portable file",
+		LongHelp: `  Imports the recipes of a file written by export, or of stdin
+  without a file. Ingredients are matched to the catalog by name and
+  only created if they don't exist. Everything is imported in
+  a single transaction, so nothing is imported on error.
+
+  A recipe whose name exists is an error unless --on-conflict
+  is skip, replace to remove the existing recipe, or rename
+  to import it as e.g. "Country (2)".
+
+  With --format cooklang a single recipe is read from Cooklang
+  markup. Amounts are converted to baker's percentages of the
+  flour of their stage, where a section is a stage if a later
+  section uses it as an ingredient, e.g. "= Levain" and
+  "@levain{200%g}". The title is taken from the metadata, or
+  the file name. Steps and ingredients without a usable
+  amount are kept in the notes.
+
+  With --format jsonld the schema.org Recipe objects of a
+  JSON-LD file, or of the application/ld+json scripts of a
+  saved web page, are read. All ingredients belong to the
+  final dough, and their kinds are guessed from the names.
+
+  Example:
+
+     $ sourdough import --dry-run recipes.yaml
+     $ sourdough import --on-conflict rename recipes.yaml
+     $ sourdough import --format cooklang country.cook
+     $ sourdough import --format jsonld focaccia.html
+
+`,
+		Flags: cmd.Flags,
+		Exec:  ImportCmdExec(&cmd.Opts),
+	}
+	cmd.root.Command.Subcommands = append(cmd.root.Command.Subcommands, cmd.Command)
+	return &cmd
+}
+
+func ImportCmdExec(opts *ImportCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) > 1 {
+			return errors.New("requires a single file")
+		}
+
+		path := "stdin"
+		if len(args) == 1 {
+			path = args[0]
+		}
+
+		var (
+			templates []recipe.Recipe
+			err       error
+		)
+		switch opts.Format {
+		case "cooklang":
+			templates, err = readCooklang(args, opts.Root.Stdin)
+		case "jsonld":
+			templates, err = readJSONLD(args, opts.Root.Stdin)
+		default:
+			var b bundle.Bundle
+			if b, err = readBundle(args, opts.Root.Stdin); err == nil {
+				templates, err = b.RecipeTemplates()
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		return importRecipes(ctx, opts, templates)
+	}
+}
+
+// readBundle reads a bundle from the file given in args, or stdin.
+func readBundle(args []string, stdin io.Reader) (bundle.Bundle, error) {
+	if len(args) == 0 {
+		return bundle.Read(stdin)
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return bundle.Bundle{}, err
+	}
+	defer f.Close()
+
+	return bundle.Read(f)
+}
+
+// readCooklang reads a recipe template from the Cooklang file given in args,
+// or stdin. A recipe without a title is named after the file.
+func readCooklang(args []string, stdin io.Reader) ([]recipe.Recipe, error) {
+	var (
+		r    io.Reader = stdin
+		name string
+	)
+	if len(args) > 0 {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		r = f
+		name = strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+	}
+
+	c, err := cooklang.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := c.Template(name)
+	if err != nil {
+		return nil, err
+	}
+	return []recipe.Recipe{template}, nil
+}
+
+// readJSONLD reads the recipe templates of the schema.org recipes of the
+// JSON-LD or HTML file given in args, or stdin.
+func readJSONLD(args []string, stdin io.Reader) ([]recipe.Recipe, error) {
+	r := stdin
+	if len(args) > 0 {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	recipes, err := schemaorg.Extract(r)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]recipe.Recipe, 0, len(recipes))
+	for _, r := range recipes {
+		template, err := r.Template()
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+// importRecipes creates the given recipes in a single transaction, resolving
+// name conflicts with existing recipes according to --on-conflict. With
+// --dry-run the transaction is rolled back.
+func importRecipes(ctx context.Context, opts *ImportCmdOptions, templates []recipe.Recipe) (err error) {
+	db, err := opts.Root.SetupDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil || opts.DryRun {
+			tx.Rollback()
+		}
+	}()
+
+	q := query.New(db).WithTx(tx)
+
+	existing, err := q.ListRecipes(ctx)
+	if err != nil {
+		return err
+	}
+
+	names := map[string]int64{}
+	for _, r := range existing {
+		names[strings.ToLower(r.Name)] = r.ID
+	}
+
+	w := opts.Root.Stdout
+	for _, template := range templates {
+		id, exists := names[strings.ToLower(template.Name)]
+		if exists {
+			switch opts.OnConflict {
+			case "skip":
+				fmt.Fprintf(w, "skipped recipe %s, it already exists\n", template.Name)
+				continue
+			case "replace":
+				if err := q.DeleteRecipe(ctx, id); err != nil {
+					return fmt.Errorf("failed to replace recipe %s: %w", template.Name, err)
+				}
+			case "rename":
+				template.Name = uniqueRecipeName(template.Name, names)
+			default:
+				return fmt.Errorf("recipe %s already exists, use --on-conflict to skip, replace or rename it", template.Name)
+			}
+		}
+
+		r, err := createRecipe(ctx, q, template)
+		if err != nil {
+			return err
+		}
+		names[strings.ToLower(r.Name)] = r.ID
+
+		action := "imported"
+		if exists && opts.OnConflict == "replace" {
+			action = "replaced"
+		}
+		fmt.Fprintf(w, "%s recipe %d %s with %d ingredients\n", action, r.ID, r.Name, len(template.Ingredients))
+	}
+
+	if opts.DryRun {
+		fmt.Fprintln(w, "dry run, nothing was imported")
+		return nil
+	}
+
+	return tx.Commit()
+}
+
+// uniqueRecipeName returns name with the lowest number appended which makes
+// it unique, e.g. "Country (2)".
+func uniqueRecipeName(name string, names map[string]int64) string {
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)", name, i)
+		if _, ok := names[strings.ToLower(candidate)]; !ok {
+			return candidate
+		}
+	}
+}
+
+// createRecipe creates a recipe from a template. Ingredients missing from
+// the catalog are created with the properties of the template.
+func createRecipe(ctx context.Context, db *query.Queries, template recipe.Recipe) (query.Recipe, error) {
+	r, err := db.CreateRecipe(ctx, query.CreateRecipeParams{
+		Name:  template.Name,
+		Notes: template.Notes,
+	})
+	if err != nil {
+		return r, fmt.Errorf("failed to create recipe %s: %w", template.Name, err)
+	}
+
+	added := make([]stepCandidate, 0, len(template.Ingredients))
+	for _, ingredient := range template.Ingredients {
+		_, err := db.GetIngredientByName(ctx, ingredient.Name)
+		if errors.Is(err, query.ErrNotFound) {
+			_, err = copyIngredient(ctx, db, catalogIngredient(ingredient), ingredient.Name)
+		}
+		if err != nil {
+			return r, fmt.Errorf("failed to get ingredient %s: %w", ingredient.Name, err)
+		}
+
+		ri, err := addRecipeIngredient(ctx, db, AddIngredientParams{
+			Name:               ingredient.Name,
+			RecipeID:           r.ID,
+			PreferUnitCategory: ingredient.PreferUnitCategory,
+			Mode:               ingredient.Mode,
+			Percentage:         ingredient.Percentage,
+			Amount:             ingredient.Amount,
+			Dependency:         ingredient.Dependency,
+			Kind:               ingredient.Kind,
+			Stage:              ingredient.Stage,
+			Group:              ingredient.Group,
+		})
+		if err != nil {
+			return r, fmt.Errorf("recipe %s: %w", template.Name, err)
+		}
+		added = append(added, stepCandidate{ID: ri.ID, Name: ingredient.Name, Stage: ingredient.Stage})
+	}
+
+	for _, step := range template.Steps {
+		stepIngredients := make([]int64, 0, len(step.Ingredients))
+		for _, ingredient := range step.Ingredients {
+			id, err := matchStepIngredient(added, ingredient)
+			if err != nil {
+				return r, fmt.Errorf("recipe %s: step %s: %w", template.Name, step.Text, err)
+			}
+			stepIngredients = append(stepIngredients, id)
+		}
+
+		if _, err := addStep(ctx, db, r.ID, step, stepIngredients); err != nil {
+			return r, fmt.Errorf("recipe %s: %w", template.Name, err)
+		}
+	}
+
+	return r, nil
+}
+
+// stepCandidate is a recipe ingredient a step of an imported recipe can add.
+type stepCandidate struct {
+	ID    int64
+	Name  string
+	Stage string
+}
+
+// matchStepIngredient returns the ID of the recipe ingredient a step adds.
+// The ingredient must match on name and stage, except for a reference
+// without a stage, written by bundles before steps recorded it, which matches
+// the name in any stage if the final dough has none.
+func matchStepIngredient(candidates []stepCandidate, ingredient recipe.StepIngredient) (int64, error) {
+	var matches []stepCandidate
+	for _, c := range candidates {
+		if strings.EqualFold(c.Name, ingredient.Name) && strings.EqualFold(c.Stage, ingredient.Stage) {
+			matches = append(matches, c)
+		}
+	}
+	if len(matches) == 0 && ingredient.Stage == recipe.FinalDough {
+		for _, c := range candidates {
+			if strings.EqualFold(c.Name, ingredient.Name) {
+				matches = append(matches, c)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		if ingredient.Stage != recipe.FinalDough {
+			return 0, fmt.Errorf("recipe has no ingredient %s in stage %s", ingredient.Name, ingredient.Stage)
+		}
+		return 0, fmt.Errorf("recipe has no ingredient %s", ingredient.Name)
+	case 1:
+		return matches[0].ID, nil
+	default:
+		var b strings.Builder
+		fmt.Fprintf(&b, "ingredient %s is ambiguous, it's one of:", ingredient.Name)
+		for _, c := range matches {
+			stage := "final dough"
+			if c.Stage != recipe.FinalDough {
+				stage = c.Stage
+			}
+			fmt.Fprintf(&b, "\n  %s (%s)", c.Name, stage)
+		}
+		return 0, errors.New(b.String())
+	}
+}
+
+// catalogIngredient returns the catalog properties of a template ingredient.
+func catalogIngredient(ingredient recipe.RecipeIngredient) query.Ingredient {
+	out := query.Ingredient{Name: ingredient.Name, Kind: ingredient.Kind}
+	if ingredient.Hydration > 0 {
+		out.Hydration = &ingredient.Hydration
+	}
+	if ingredient.Density > 0 {
+		out.Density = &ingredient.Density
+	}
+	if ingredient.PieceWeight > 0 {
+		out.PieceWeight = &ingredient.PieceWeight
+	}
+	return out
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/peterbourgon/ff/v4"
+	"github.com/simonklee/sourdough/query"
+	"github.com/simonklee/sourdough/recipe"
+)
+
+type IngredientCmdOptions struct {
+	Root *RootCmdOptions
+}
+
+type IngredientCmd struct {
+	Opts IngredientCmdOptions
+
+	root    *RootCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func NewIngredientCmd(parent *RootCmd) *IngredientCmd {
+	var cmd IngredientCmd
+	cmd.Opts.Root = &parent.Opts
+	cmd.root = parent
+	cmd.Flags = ff.NewFlagSet("ingredient").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "ingredient",
+		Usage:     CmdLabel + " ingredient <subcommand> ...",
+		ShortHelp: "manage the ingredient catalog",
+		Flags:     cmd.Flags,
+		Exec: func(ctx context.Context, args []string) error {
+			return ff.ErrHelp
+		},
+	}
+	cmd.root.Command.Subcommands = append(cmd.root.Command.Subcommands, cmd.Command)
+	_ = newIngredientListCmd(&cmd)
+	_ = newIngredientSetDensityCmd(&cmd)
+	_ = newIngredientSetPieceWeightCmd(&cmd)
+
+	return &cmd
+}
+
+type ingredientListCmd struct {
+	parent  *IngredientCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newIngredientListCmd(parent *IngredientCmd) *ingredientListCmd {
+	var cmd ingredientListCmd
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("list").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "list",
+		Usage:     CmdLabel + " ingredient list [flags]",
+		ShortHelp: "list all ingredients",
+		Flags:     cmd.Flags,
+		Exec:      ingredientListCmdExec(&parent.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func ingredientListCmdExec(opts *IngredientCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		db, err := opts.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		ingredients, err := db.GetIngredients(ctx)
+		if err != nil {
+			return err
+		}
+
+		format := opts.Root.OutputFormat()
+		if format.Structured() {
+			return renderData(opts.Root.Stdout, format, ingredientListOutput(ingredients))
+		}
+
+		tw := table.NewWriter()
+		tw.SetStyle(table.StyleLight)
+		tw.SetTitle("Ingredients")
+		tw.SetColumnConfigs([]table.ColumnConfig{
+			{Number: 4, Align: text.AlignRight},
+			{Number: 5, Align: text.AlignRight},
+			{Number: 6, Align: text.AlignRight},
+		})
+		tw.AppendHeader(table.Row{"ID", "Name", "Kind", "Hydration", "Density", "Piece Weight"})
+		for _, ingredient := range ingredients {
+			var hydration, density, pieceWeight string
+			if ingredient.Hydration != nil {
+				hydration = formatPercentage(*ingredient.Hydration)
+			}
+			if ingredient.Density != nil {
+				density = formatDensity(*ingredient.Density)
+			}
+			if ingredient.PieceWeight != nil {
+				pieceWeight = recipe.UnitGrams.Format(*ingredient.PieceWeight)
+			}
+
+			tw.AppendRow(table.Row{
+				ingredient.ID,
+				ingredient.Name,
+				ingredient.Kind,
+				hydration,
+				density,
+				pieceWeight,
+			})
+		}
+
+		return renderTable(opts.Root.Stdout, format, tw)
+	}
+}
+
+type ingredientSetDensityCmd struct {
+	parent  *IngredientCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newIngredientSetDensityCmd(parent *IngredientCmd) *ingredientSetDensityCmd {
+	var cmd ingredientSetDensityCmd
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("set-density").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "set-density",
+		Usage:     CmdLabel + " ingredient set-density <ingredient> <g/ml|none>",
+		ShortHelp: "set the density of an ingredient",
+		LongHelp: `  Sets the density of an ingredient in g/ml, used to convert
+  between weights and volumes instead of the default density
+  of the ingredient's kind. Use none to remove it.
+
+  Example:
+
+     $ sourdough ingredient set-density "Rye Flour" 0.8
+     $ sourdough ingredient set-density Honey 1.42
+
+`,
+		Flags: cmd.Flags,
+		Exec:  ingredientSetDensityCmdExec(&parent.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func ingredientSetDensityCmdExec(opts *IngredientCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 2 {
+			return errors.New("requires an ingredient and a density")
+		}
+
+		density, err := parseDensity(args[1])
+		if err != nil {
+			return err
+		}
+
+		db, err := opts.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		ingredient, err := findIngredient(ctx, db, args[0])
+		if err != nil {
+			return err
+		}
+
+		ingredient, err = db.SetIngredientDensity(ctx, query.SetIngredientDensityParams{
+			Density: density,
+			ID:      ingredient.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set density of %s: %w", ingredient.Name, err)
+		}
+
+		if density == nil {
+			fmt.Fprintf(opts.Root.Stdout, "removed density of %s\n", ingredient.Name)
+		} else {
+			fmt.Fprintf(opts.Root.Stdout, "set density of %s to %s\n", ingredient.Name, formatDensity(*density))
+		}
+
+		return nil
+	}
+}
+
+type ingredientSetPieceWeightCmd struct {
+	parent  *IngredientCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newIngredientSetPieceWeightCmd(parent *IngredientCmd) *ingredientSetPieceWeightCmd {
+	var cmd ingredientSetPieceWeightCmd
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("set-piece-weight").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "set-piece-weight",
+		Usage:     CmdLabel + " ingredient set-piece-weight <ingredient> <weight|none>",
+		ShortHelp: "set the weight of a single piece of an ingredient",
+		LongHelp: `  Sets the weight of a single piece of an ingredient, used to
+  convert between pieces and weights. Ingredients added with
+  --unit count are then shown in pieces. Use none to remove it.
+
+  Example:
+
+     $ sourdough ingredient set-piece-weight Egg 50g
+
+`,
+		Flags: cmd.Flags,
+		Exec:  ingredientSetPieceWeightCmdExec(&parent.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func ingredientSetPieceWeightCmdExec(opts *IngredientCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 2 {
+			return errors.New("requires an ingredient and a weight")
+		}
+
+		weight, err := parsePieceWeight(args[1])
+		if err != nil {
+			return err
+		}
+
+		db, err := opts.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		ingredient, err := findIngredient(ctx, db, args[0])
+		if err != nil {
+			return err
+		}
+
+		ingredient, err = db.SetIngredientPieceWeight(ctx, query.SetIngredientPieceWeightParams{
+			PieceWeight: weight,
+			ID:          ingredient.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set piece weight of %s: %w", ingredient.Name, err)
+		}
+
+		if weight == nil {
+			fmt.Fprintf(opts.Root.Stdout, "removed piece weight of %s\n", ingredient.Name)
+		} else {
+			fmt.Fprintf(opts.Root.Stdout, "set piece weight of %s to %s\n", ingredient.Name, recipe.UnitGrams.Format(*weight))
+		}
+
+		return nil
+	}
+}
+
+// formatDensity formats a density in g/ml.
+func formatDensity(density float64) string {
+	return fmt.Sprintf("%.2fg/ml", density)
+}
+
+// parseDensity parses a density in g/ml. It returns nil for "none".
+func parseDensity(value string) (*float64, error) {
+	if value == "none" {
+		return nil, nil
+	}
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil || v <= 0 {
+		return nil, fmt.Errorf("invalid density: %s", value)
+	}
+	return &v, nil
+}
+
+// parsePieceWeight parses the weight of a single piece, e.g. "50g", into
+// grams. It returns nil for "none".
+func parsePieceWeight(value string) (*float64, error) {
+	if value == "none" {
+		return nil, nil
+	}
+
+	weight, err := recipe.ParseTuple(value)
+	if err != nil {
+		return nil, err
+	}
+
+	weight, err = weight.Convert(recipe.UnitGrams)
+	if err != nil || weight.Value <= 0 {
+		return nil, fmt.Errorf("invalid piece weight: %s", value)
+	}
+	return &weight.Value, nil
+}
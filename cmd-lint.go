@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/peterbourgon/ff/v4"
+	"github.com/simonklee/sourdough/query"
+	"github.com/simonklee/sourdough/recipe"
+)
+
+type LintCmdOptions struct {
+	Strict bool
+
+	Root *RootCmdOptions
+}
+
+type LintCmd struct {
+	Opts LintCmdOptions
+
+	root    *RootCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func NewLintCmd(parent *RootCmd) *LintCmd {
+	var cmd LintCmd
+	cmd.Opts.Root = &parent.Opts
+	cmd.root = parent
+	cmd.Flags = ff.NewFlagSet("lint").SetParent(parent.Flags)
+	cmd.Flags.BoolVar(&cmd.Opts.Strict, 0, "strict", "fail on warnings as well as errors")
+	cmd.Command = &ff.Command{
+		Name:      "lint",
+		Usage:     CmdLabel + " lint [flags] [recipe...]",
+		ShortHelp: "check the baker's math of recipes",
+		LongHelp: `  Checks the given recipes, or all recipes, for errors which
+  make a recipe wrong or impossible to calculate and warnings
+  about unusual baker's math. It fails if any recipe has an
+  error, or a warning with --strict.
+
+  Errors:
+
+     no-flour            the recipe has no flour
+     flour-sum           flour percentages don't sum to 100%
+     unknown-dependency  a dependency isn't an ingredient or label
+     calculation         the recipe can't be calculated, e.g. a cycle
+
+  Warnings:
+
+     no-flour            a stage has no flour
+     duplicate           an ingredient is listed more than once
+     missing-kind        an ingredient has no kind
+     hydration           hydration outside 50-100%
+     salt                salt outside 1.5-3%, or no salt
+     starter             starter outside 5-100% of the flour
+
+  Example:
+
+     $ sourdough lint
+     $ sourdough lint --strict Country
+
+`,
+		Flags: cmd.Flags,
+		Exec:  LintCmdExec(&cmd.Opts),
+	}
+	cmd.root.Command.Subcommands = append(cmd.root.Command.Subcommands, cmd.Command)
+	return &cmd
+}
+
+func LintCmdExec(opts *LintCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		db, err := opts.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		var recipes []query.Recipe
+		if len(args) == 0 {
+			if recipes, err = db.ListRecipes(ctx); err != nil {
+				return err
+			}
+		}
+		for _, arg := range args {
+			r, err := findRecipe(ctx, db, arg)
+			if err != nil {
+				return err
+			}
+			recipes = append(recipes, r)
+		}
+
+		var (
+			results []lintResult
+			failed  int
+		)
+		for _, r := range recipes {
+			template, err := loadRecipe(ctx, db, r)
+			if err != nil {
+				return err
+			}
+
+			issues := recipe.Validate(template.Ingredients)
+			if recipe.HasErrors(issues) || (opts.Strict && len(issues) > 0) {
+				failed++
+			}
+			results = append(results, lintResult{Recipe: r, Issues: issues})
+		}
+
+		format := opts.Root.OutputFormat()
+		if format.Structured() {
+			err = renderData(opts.Root.Stdout, format, lintOutput(results))
+		} else {
+			err = renderLint(opts.Root.Stdout, format, results)
+		}
+		if err != nil {
+			return err
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d recipes failed lint", failed, len(recipes))
+		}
+		return nil
+	}
+}
+
+type lintResult struct {
+	Recipe query.Recipe
+	Issues []recipe.Issue
+}
+
+// renderLint renders a table of the issues of every recipe with issues.
+func renderLint(w io.Writer, format OutputFormat, results []lintResult) error {
+	for _, result := range results {
+		if len(result.Issues) == 0 {
+			continue
+		}
+
+		tw := table.NewWriter()
+		tw.SetStyle(table.StyleLight)
+		tw.SetTitle(fmt.Sprintf("Lint: %s", result.Recipe.Name))
+		tw.AppendHeader(table.Row{"Severity", "Check", "Stage", "Ingredient", "Message"})
+		for _, issue := range result.Issues {
+			tw.AppendRow(table.Row{issue.Severity, issue.Check, issue.Stage, issue.Ingredient, issue.Message})
+		}
+
+		if err := renderTable(w, format, tw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lintRecipe validates a recipe and writes its issues to w, one per line.
+// Issues of the skipped checks are left out.
+func lintRecipe(ctx context.Context, db *query.Queries, w io.Writer, r query.Recipe, skip ...string) ([]recipe.Issue, error) {
+	template, err := loadRecipe(ctx, db, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []recipe.Issue
+	for _, issue := range recipe.Validate(template.Ingredients) {
+		if slices.Contains(skip, issue.Check) {
+			continue
+		}
+		issues = append(issues, issue)
+		fmt.Fprintf(w, "%s: %s\n", r.Name, issue)
+	}
+	return issues, nil
+}
@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"html"
 	"io"
+	"strings"
 
 	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/peterbourgon/ff/v4"
 	"github.com/simonklee/sourdough/query"
 )
@@ -49,31 +52,82 @@ func ListCmdExec(opts *ListCmdOptions) CmdExec {
 			return err
 		}
 
+		format := opts.Root.OutputFormat()
+		if format.Structured() {
+			return renderData(opts.Root.Stdout, format, recipeListOutput(recipes))
+		}
+
 		if len(recipes) == 0 {
 			return nil
 		}
 
-		return listRecipes(opts.Root.Stdout, opts.Root.OutputFormat(), recipes)
+		return listRecipes(opts.Root.Stdout, format, "Recipes", recipes, nil)
 	}
 }
 
-func listRecipes(w io.Writer, format OutputFormat, recipes []query.Recipe) error {
+// listRecipes renders a table of recipes. If snippets is not nil, the
+// snippet of each recipe is shown in a Match column with the matches
+// highlighted.
+func listRecipes(w io.Writer, format OutputFormat, title string, recipes []query.Recipe, snippets []string) error {
 	// Create a new table writer
 	tw := table.NewWriter()
 
 	// Set table title
-	tw.SetTitle("Recipes")
+	tw.SetTitle(title)
 
 	// Set table style
 	tw.SetStyle(table.StyleLight)
 
 	// Append a header row
-	tw.AppendHeader(table.Row{"ID", "Name"})
+	if snippets == nil {
+		tw.AppendHeader(table.Row{"ID", "Name"})
+	} else {
+		tw.AppendHeader(table.Row{"ID", "Name", "Match"})
+
+		// Snippets are escaped by highlightSnippet.
+		if format == OutputFormatHTML {
+			tw.Style().HTML.EscapeText = false
+			tw.SetTitle(html.EscapeString(title))
+		}
+	}
 
 	// Append data rows
-	for _, recipe := range recipes {
-		tw.AppendRow(table.Row{recipe.ID, recipe.Name})
+	for i, recipe := range recipes {
+		if snippets == nil {
+			tw.AppendRow(table.Row{recipe.ID, recipe.Name})
+			continue
+		}
+
+		name := recipe.Name
+		if format == OutputFormatHTML {
+			name = html.EscapeString(name)
+		}
+		tw.AppendRow(table.Row{recipe.ID, name, highlightSnippet(format, snippets[i])})
 	}
 
 	return renderTable(w, format, tw)
 }
+
+// highlightSnippet replaces the match markers in a search snippet with bold
+// text in the terminal, ** in markdown and <mark> in HTML.
+func highlightSnippet(format OutputFormat, snippet string) string {
+	switch format {
+	case OutputFormatHTML:
+		snippet = html.EscapeString(snippet)
+		return strings.NewReplacer(searchMatchStart, "<mark>", searchMatchEnd, "</mark>").Replace(snippet)
+	case OutputFormatMarkdown:
+		return strings.NewReplacer(searchMatchStart, "**", searchMatchEnd, "**").Replace(snippet)
+	default:
+		var b strings.Builder
+		for {
+			before, rest, ok := strings.Cut(snippet, searchMatchStart)
+			b.WriteString(before)
+			if !ok {
+				return b.String()
+			}
+			match, after, _ := strings.Cut(rest, searchMatchEnd)
+			b.WriteString(text.Bold.Sprint(match))
+			snippet = after
+		}
+	}
+}
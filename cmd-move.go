@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/peterbourgon/ff/v4"
+	"github.com/simonklee/sourdough/query"
+	"github.com/simonklee/sourdough/recipe"
+)
+
+type MoveCmdOptions struct {
+	Before string
+	After  string
+
+	Root *RootCmdOptions
+}
+
+type MoveCmd struct {
+	Opts MoveCmdOptions
+
+	root    *RootCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func NewMoveCmd(parent *RootCmd) *MoveCmd {
+	var cmd MoveCmd
+	cmd.Opts.Root = &parent.Opts
+	cmd.root = parent
+	cmd.Flags = ff.NewFlagSet("move").SetParent(parent.Flags)
+	cmd.Flags.StringVar(&cmd.Opts.Before, 'b', "before", "", "move before the recipe ingredient with this ID")
+	cmd.Flags.StringVar(&cmd.Opts.After, 'a', "after", "", "move after the recipe ingredient with this ID")
+	cmd.Command = &ff.Command{
+		Name:      "move",
+		Usage:     CmdLabel + " move <recipe-ingredient> [<position>] [flags]",
+		ShortHelp: "change the order of the ingredients of a recipe",
+		LongHelp: `  Moves an ingredient of a recipe, by the ID shown in the #
+  column of view, to a position within its stage. Positions
+  start at 1 for the first ingredient of the stage. Instead
+  of a position the ingredient may be moved before or after
+  another ingredient of the same stage.
+
+  Example:
+
+     $ sourdough move 7 1
+     $ sourdough move --after 3 7
+
+`,
+		Flags: cmd.Flags,
+		Exec:  MoveCmdExec(&cmd.Opts),
+	}
+	cmd.root.Command.Subcommands = append(cmd.root.Command.Subcommands, cmd.Command)
+	return &cmd
+}
+
+func MoveCmdExec(opts *MoveCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) == 0 {
+			return errors.New("requires a recipe ingredient ID")
+		}
+
+		var targets int
+		for _, set := range []bool{len(args) > 1, opts.Before != "", opts.After != ""} {
+			if set {
+				targets++
+			}
+		}
+		if targets != 1 || len(args) > 2 {
+			return errors.New("requires either a position, --before or --after")
+		}
+
+		db, err := opts.Root.SetupDB(ctx)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		return InTx(ctx, db, func(q *query.Queries) error {
+			ri, err := findRecipeIngredient(ctx, q, args[0])
+			if err != nil {
+				return err
+			}
+
+			r, err := q.GetRecipe(ctx, ri.RecipeID)
+			if err != nil {
+				return err
+			}
+
+			rows, err := q.ListRecipeIngredients(ctx, r.ID)
+			if err != nil {
+				return err
+			}
+
+			var (
+				moved query.ListRecipeIngredientsRow
+				stage []query.ListRecipeIngredientsRow
+			)
+			for _, row := range rows {
+				if row.ID == ri.ID {
+					moved = row
+				}
+			}
+			for _, row := range rows {
+				if row.ID != ri.ID && rowStage(row) == rowStage(moved) {
+					stage = append(stage, row)
+				}
+			}
+
+			var index int
+			switch {
+			case len(args) > 1:
+				position, err := strconv.Atoi(args[1])
+				if err != nil || position < 1 || position > len(stage)+1 {
+					return fmt.Errorf("invalid position %s, want 1 to %d", args[1], len(stage)+1)
+				}
+				index = position - 1
+			case opts.Before != "":
+				if index, err = stageIndex(stage, moved, opts.Before); err != nil {
+					return err
+				}
+			default:
+				if index, err = stageIndex(stage, moved, opts.After); err != nil {
+					return err
+				}
+				index++
+			}
+
+			stage = append(stage[:index], append([]query.ListRecipeIngredientsRow{moved}, stage[index:]...)...)
+
+			// Renumber the whole recipe, keeping the other stages
+			// where they are.
+			var position int64
+			for _, row := range rows {
+				if rowStage(row) == rowStage(moved) {
+					row, stage = stage[0], stage[1:]
+				}
+
+				position++
+				if row.Position == position {
+					continue
+				}
+				err := q.SetRecipeIngredientPosition(ctx, query.SetRecipeIngredientPositionParams{
+					Position: position,
+					ID:       row.ID,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to move %s: %w", row.Name, err)
+				}
+			}
+
+			fmt.Fprintf(opts.Root.Stdout, "moved %s to position %d of recipe %s\n", moved.Name, index+1, r.Name)
+			return nil
+		})
+	}
+}
+
+// rowStage returns the stage of a recipe ingredient, recipe.FinalDough for
+// the final dough.
+func rowStage(row query.ListRecipeIngredientsRow) string {
+	if row.Stage == nil {
+		return recipe.FinalDough
+	}
+	return *row.Stage
+}
+
+// stageIndex returns the index of the recipe ingredient with the given ID in
+// the ingredients of a stage. The ingredient must be in the same stage as
+// moved.
+func stageIndex(stage []query.ListRecipeIngredientsRow, moved query.ListRecipeIngredientsRow, value string) (int, error) {
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid recipe ingredient ID: %w", err)
+	}
+	if id == moved.ID {
+		return 0, fmt.Errorf("can't move %s relative to itself", moved.Name)
+	}
+
+	for i, row := range stage {
+		if row.ID == id {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("recipe ingredient %d is not in the same recipe and stage as %s", id, moved.Name)
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/peterbourgon/ff/v4"
+	"github.com/simonklee/sourdough/query"
+)
+
+type RenameCmdOptions struct {
+	Root *RootCmdOptions
+}
+
+type RenameCmd struct {
+	Opts RenameCmdOptions
+
+	root    *RootCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func NewRenameCmd(parent *RootCmd) *RenameCmd {
+	var cmd RenameCmd
+	cmd.Opts.Root = &parent.Opts
+	cmd.root = parent
+	cmd.Flags = ff.NewFlagSet("rename").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "rename",
+		Usage:     CmdLabel + " rename <subcommand> ...",
+		ShortHelp: "rename recipes and ingredients",
+		Flags:     cmd.Flags,
+		Exec: func(ctx context.Context, args []string) error {
+			return ff.ErrHelp
+		},
+	}
+	cmd.root.Command.Subcommands = append(cmd.root.Command.Subcommands, cmd.Command)
+	_ = newRenameRecipeCmd(&cmd)
+	_ = newRenameIngredientCmd(&cmd)
+	_ = newRenameCatalogCmd(&cmd)
+
+	return &cmd
+}
+
+type renameRecipeCmd struct {
+	parent  *RenameCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newRenameRecipeCmd(parent *RenameCmd) *renameRecipeCmd {
+	var cmd renameRecipeCmd
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("recipe").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "recipe",
+		Usage:     CmdLabel + " rename recipe <recipe> <name>",
+		ShortHelp: "rename a recipe",
+		Flags:     cmd.Flags,
+		Exec:      renameRecipeCmdExec(&parent.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func renameRecipeCmdExec(opts *RenameCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 2 || args[1] == "" {
+			return errors.New("requires a recipe ID or name and a new name")
+		}
+
+		db, err := opts.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		r, err := findRecipe(ctx, db, args[0])
+		if err != nil {
+			return err
+		}
+
+		renamed, err := db.UpdateRecipe(ctx, query.UpdateRecipeParams{
+			Name:  args[1],
+			Notes: r.Notes,
+			ID:    r.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to rename recipe %s: %w", r.Name, err)
+		}
+
+		fmt.Fprintf(opts.Root.Stdout, "renamed recipe %s to %s\n", r.Name, renamed.Name)
+		return nil
+	}
+}
+
+type renameIngredientCmd struct {
+	parent  *RenameCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newRenameIngredientCmd(parent *RenameCmd) *renameIngredientCmd {
+	var cmd renameIngredientCmd
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("ingredient").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "ingredient",
+		Usage:     CmdLabel + " rename ingredient <recipe-ingredient> <name>",
+		ShortHelp: "rename an ingredient of a single recipe",
+		LongHelp: `  Renames an ingredient of a single recipe, e.g. to fix a typo,
+  leaving other recipes using the ingredient untouched. The
+  recipe ingredient is linked to the catalog ingredient with
+  the new name, which is created with the same kind and
+  properties if it doesn't exist. Use rename catalog to
+  rename an ingredient in every recipe.
+
+`,
+		Flags: cmd.Flags,
+		Exec:  renameIngredientCmdExec(&parent.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func renameIngredientCmdExec(opts *RenameCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 2 || args[1] == "" {
+			return errors.New("requires a recipe ingredient ID and a new name")
+		}
+
+		db, err := opts.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		ri, err := findRecipeIngredient(ctx, db, args[0])
+		if err != nil {
+			return err
+		}
+
+		old, err := db.GetIngredient(ctx, ri.IngredientID)
+		if err != nil {
+			return err
+		}
+
+		ingredient, err := db.GetIngredientByName(ctx, args[1])
+		if errors.Is(err, query.ErrNotFound) {
+			ingredient, err = copyIngredient(ctx, db, old, args[1])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get ingredient %s: %w", args[1], err)
+		}
+
+		_, err = db.UpdateRecipeIngredient(ctx, query.UpdateRecipeIngredientParams{
+			PreferUnitCategory: ri.PreferUnitCategory,
+			Percentage:         ri.Percentage,
+			Dependency:         ri.Dependency,
+			Mode:               ri.Mode,
+			Amount:             ri.Amount,
+			Unit:               ri.Unit,
+			IngredientID:       ingredient.ID,
+			GroupName:          ri.GroupName,
+			ID:                 ri.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to rename %s: %w", old.Name, err)
+		}
+
+		fmt.Fprintf(opts.Root.Stdout, "renamed %s to %s\n", old.Name, ingredient.Name)
+		return nil
+	}
+}
+
+// copyIngredient creates a catalog ingredient with the given name and the
+// kind and properties of ingredient.
+func copyIngredient(ctx context.Context, db *query.Queries, ingredient query.Ingredient, name string) (query.Ingredient, error) {
+	out, err := db.CreateIngredient(ctx, query.CreateIngredientParams{
+		Name:      name,
+		Kind:      ingredient.Kind,
+		Hydration: ingredient.Hydration,
+	})
+	if err != nil {
+		return out, err
+	}
+
+	if ingredient.Density != nil {
+		out, err = db.SetIngredientDensity(ctx, query.SetIngredientDensityParams{
+			Density: ingredient.Density,
+			ID:      out.ID,
+		})
+		if err != nil {
+			return out, err
+		}
+	}
+
+	if ingredient.PieceWeight != nil {
+		out, err = db.SetIngredientPieceWeight(ctx, query.SetIngredientPieceWeightParams{
+			PieceWeight: ingredient.PieceWeight,
+			ID:          out.ID,
+		})
+		if err != nil {
+			return out, err
+		}
+	}
+
+	return out, nil
+}
+
+type renameCatalogCmd struct {
+	parent  *RenameCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newRenameCatalogCmd(parent *RenameCmd) *renameCatalogCmd {
+	var cmd renameCatalogCmd
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("catalog").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "catalog",
+		Usage:     CmdLabel + " rename catalog <ingredient> <name>",
+		ShortHelp: "rename an ingredient in the catalog and all recipes",
+		Flags:     cmd.Flags,
+		Exec:      renameCatalogCmdExec(&parent.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func renameCatalogCmdExec(opts *RenameCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 2 || args[1] == "" {
+			return errors.New("requires an ingredient ID or name and a new name")
+		}
+
+		db, err := opts.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		ingredient, err := findIngredient(ctx, db, args[0])
+		if err != nil {
+			return err
+		}
+
+		if existing, err := db.GetIngredientByName(ctx, args[1]); err == nil && existing.ID != ingredient.ID {
+			return fmt.Errorf("ingredient %s already exists", existing.Name)
+		} else if err != nil && !errors.Is(err, query.ErrNotFound) {
+			return err
+		}
+
+		recipes, err := db.ListRecipesByIngredient(ctx, ingredient.ID)
+		if err != nil {
+			return err
+		}
+
+		renamed, err := db.UpdateIngredient(ctx, query.UpdateIngredientParams{
+			Name: args[1],
+			Kind: ingredient.Kind,
+			ID:   ingredient.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to rename ingredient %s: %w", ingredient.Name, err)
+		}
+
+		fmt.Fprintf(opts.Root.Stdout, "renamed ingredient %s to %s\n", ingredient.Name, renamed.Name)
+		if len(recipes) > 0 {
+			fmt.Fprintf(opts.Root.Stdout, "%s is used by:\n", renamed.Name)
+		}
+		for _, r := range recipes {
+			fmt.Fprintf(opts.Root.Stdout, "  %d %s\n", r.ID, r.Name)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/peterbourgon/ff/v4"
+	"github.com/simonklee/sourdough/query"
+)
+
+type RmCmdOptions struct {
+	Yes bool
+
+	Root *RootCmdOptions
+}
+
+type RmCmd struct {
+	Opts RmCmdOptions
+
+	root    *RootCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func NewRmCmd(parent *RootCmd) *RmCmd {
+	var cmd RmCmd
+	cmd.Opts.Root = &parent.Opts
+	cmd.root = parent
+	cmd.Flags = ff.NewFlagSet("rm").SetParent(parent.Flags)
+	cmd.Flags.BoolVar(&cmd.Opts.Yes, 'y', "yes", "don't ask for confirmation")
+	cmd.Command = &ff.Command{
+		Name:      "rm",
+		Usage:     CmdLabel + " rm <subcommand> ...",
+		ShortHelp: "remove recipes and ingredients",
+		Flags:     cmd.Flags,
+		Exec: func(ctx context.Context, args []string) error {
+			return ff.ErrHelp
+		},
+	}
+	cmd.root.Command.Subcommands = append(cmd.root.Command.Subcommands, cmd.Command)
+	_ = newRmRecipeCmd(&cmd)
+	_ = newRmIngredientCmd(&cmd)
+	_ = newRmStepCmd(&cmd)
+	_ = newRmCatalogCmd(&cmd)
+
+	return &cmd
+}
+
+// confirm asks a yes/no question and returns true if the answer is yes. It
+// always returns true with --yes.
+func (opts *RmCmdOptions) confirm(format string, args ...any) (bool, error) {
+	if opts.Yes {
+		return true, nil
+	}
+
+	fmt.Fprintf(opts.Root.Stdout, format+" [y/N] ", args...)
+	answer, err := bufio.NewReader(opts.Root.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+type rmRecipeCmd struct {
+	parent  *RmCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newRmRecipeCmd(parent *RmCmd) *rmRecipeCmd {
+	var cmd rmRecipeCmd
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("recipe").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "recipe",
+		Usage:     CmdLabel + " rm recipe <recipe> [flags]",
+		ShortHelp: "remove a recipe and its ingredients",
+		Flags:     cmd.Flags,
+		Exec:      rmRecipeCmdExec(&parent.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func rmRecipeCmdExec(opts *RmCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return errors.New("requires a recipe ID or name")
+		}
+
+		db, err := opts.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		r, err := findRecipe(ctx, db, args[0])
+		if err != nil {
+			return err
+		}
+
+		affected, err := recipeDependents(ctx, db, r.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(affected) > 0 {
+			fmt.Fprintf(opts.Root.Stdout, "%s has:\n", r.Name)
+			for _, line := range affected {
+				fmt.Fprintf(opts.Root.Stdout, "  %s\n", line)
+			}
+		}
+
+		ok, err := opts.confirm("remove recipe %s?", r.Name)
+		if err != nil || !ok {
+			return err
+		}
+
+		if err := db.DeleteRecipe(ctx, r.ID); err != nil {
+			return fmt.Errorf("failed to remove recipe %s: %w", r.Name, err)
+		}
+
+		fmt.Fprintf(opts.Root.Stdout, "removed recipe %d %s\n", r.ID, r.Name)
+		return nil
+	}
+}
+
+// recipeDependents describes what depends on a recipe. Its ingredients and
+// steps are removed with it, its bakes and fermentation logs are kept
+// without the recipe.
+func recipeDependents(ctx context.Context, db *query.Queries, recipeID int64) ([]string, error) {
+	ingredients, err := db.ListRecipeIngredients(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := db.ListSteps(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	bakes, err := db.ListRecipeBakes(ctx, query.ListRecipeBakesParams{RecipeID: &recipeID, Limit: -1})
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := db.ListFermentLogs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var fermentLogs int
+	for _, log := range logs {
+		if log.RecipeID != nil && *log.RecipeID == recipeID {
+			fermentLogs++
+		}
+	}
+
+	var affected []string
+	if n := len(ingredients); n > 0 {
+		affected = append(affected, fmt.Sprintf("%d ingredients, which are removed", n))
+	}
+	if n := len(steps); n > 0 {
+		affected = append(affected, fmt.Sprintf("%d steps, which are removed", n))
+	}
+	if n := len(bakes); n > 0 {
+		affected = append(affected, fmt.Sprintf("%d bakes, which are kept", n))
+	}
+	if fermentLogs > 0 {
+		affected = append(affected, fmt.Sprintf("%d fermentation logs, which are kept", fermentLogs))
+	}
+	return affected, nil
+}
+
+type rmIngredientCmd struct {
+	parent  *RmCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newRmIngredientCmd(parent *RmCmd) *rmIngredientCmd {
+	var cmd rmIngredientCmd
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("ingredient").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "ingredient",
+		Usage:     CmdLabel + " rm ingredient <recipe-ingredient> [flags]",
+		ShortHelp: "remove an ingredient from a recipe",
+		Flags:     cmd.Flags,
+		Exec:      rmIngredientCmdExec(&parent.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func rmIngredientCmdExec(opts *RmCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return errors.New("requires a recipe ingredient ID")
+		}
+
+		db, err := opts.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		ri, err := findRecipeIngredient(ctx, db, args[0])
+		if err != nil {
+			return err
+		}
+
+		r, err := db.GetRecipe(ctx, ri.RecipeID)
+		if err != nil {
+			return err
+		}
+
+		ingredient, err := db.GetIngredient(ctx, ri.IngredientID)
+		if err != nil {
+			return err
+		}
+
+		if err := db.DeleteRecipeIngredient(ctx, ri.ID); err != nil {
+			return fmt.Errorf("failed to remove %s from %s: %w", ingredient.Name, r.Name, err)
+		}
+
+		fmt.Fprintf(opts.Root.Stdout, "removed %s from recipe %s\n", ingredient.Name, r.Name)
+		return nil
+	}
+}
+
+type rmStepCmd struct {
+	parent  *RmCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newRmStepCmd(parent *RmCmd) *rmStepCmd {
+	var cmd rmStepCmd
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("step").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "step",
+		Usage:     CmdLabel + " rm step <step> [flags]",
+		ShortHelp: "remove a step from the method of a recipe",
+		Flags:     cmd.Flags,
+		Exec:      rmStepCmdExec(&parent.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func rmStepCmdExec(opts *RmCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return errors.New("requires a step ID")
+		}
+
+		db, err := opts.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		step, err := findStep(ctx, db, args[0])
+		if err != nil {
+			return err
+		}
+
+		r, err := db.GetRecipe(ctx, step.RecipeID)
+		if err != nil {
+			return err
+		}
+
+		if err := db.DeleteStep(ctx, step.ID); err != nil {
+			return fmt.Errorf("failed to remove step %d from %s: %w", step.ID, r.Name, err)
+		}
+
+		fmt.Fprintf(opts.Root.Stdout, "removed step %d %s from recipe %s\n", step.ID, step.Text, r.Name)
+		return nil
+	}
+}
+
+type rmCatalogCmd struct {
+	parent  *RmCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func newRmCatalogCmd(parent *RmCmd) *rmCatalogCmd {
+	var cmd rmCatalogCmd
+	cmd.parent = parent
+	cmd.Flags = ff.NewFlagSet("catalog").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "catalog",
+		Usage:     CmdLabel + " rm catalog <ingredient> [flags]",
+		ShortHelp: "remove an ingredient from the catalog and all recipes",
+		Flags:     cmd.Flags,
+		Exec:      rmCatalogCmdExec(&parent.Opts),
+	}
+	cmd.parent.Command.Subcommands = append(cmd.parent.Command.Subcommands, cmd.Command)
+
+	return &cmd
+}
+
+func rmCatalogCmdExec(opts *RmCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return errors.New("requires an ingredient ID or name")
+		}
+
+		db, err := opts.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		ingredient, err := findIngredient(ctx, db, args[0])
+		if err != nil {
+			return err
+		}
+
+		recipes, err := db.ListRecipesByIngredient(ctx, ingredient.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(recipes) > 0 {
+			fmt.Fprintf(opts.Root.Stdout, "%s is used by:\n", ingredient.Name)
+			for _, r := range recipes {
+				fmt.Fprintf(opts.Root.Stdout, "  %d %s\n", r.ID, r.Name)
+			}
+
+			ok, err := opts.confirm("remove %s from the catalog and %d recipes?", ingredient.Name, len(recipes))
+			if err != nil || !ok {
+				return err
+			}
+		}
+
+		if err := db.DeleteIngredient(ctx, ingredient.ID); err != nil {
+			return fmt.Errorf("failed to remove ingredient %s: %w", ingredient.Name, err)
+		}
+
+		fmt.Fprintf(opts.Root.Stdout, "removed ingredient %d %s\n", ingredient.ID, ingredient.Name)
+		return nil
+	}
+}
@@ -2,11 +2,14 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"io"
+	"path/filepath"
 
 	"github.com/peterbourgon/ff/v4"
 	"github.com/peterbourgon/ff/v4/ffval"
 	"github.com/simonklee/sourdough/query"
+	"github.com/simonklee/sourdough/recipe"
 )
 
 type RootCmdOptions struct {
@@ -17,10 +20,47 @@ type RootCmdOptions struct {
 	FormatTerm     bool
 	FormatHTML     bool
 	FormatMarkdown bool
+	FormatJSON     bool
+	FormatYAML     bool
+
+	// Output is the default output format, overridden by the format flags
+	// such as --json.
+	Output string
+	Units  string
+
+	DB      string
+	Config  string
+	Profile string
+}
+
+// DBPath returns the path of the database file.
+func (cfg *RootCmdOptions) DBPath() string {
+	if cfg.DB != "" {
+		return expandHome(cfg.DB)
+	}
+	return defaultDBPath()
+}
+
+// ConfigDir returns the directory of the config file, where files such as
+// the photos of bakes are kept.
+func (cfg *RootCmdOptions) ConfigDir() string {
+	if cfg.Config != "" {
+		return filepath.Dir(expandHome(cfg.Config))
+	}
+	return filepath.Dir(defaultConfigPath())
+}
+
+// UnitSystem returns the system of units amounts are displayed in.
+func (cfg *RootCmdOptions) UnitSystem() recipe.UnitSystem {
+	return recipe.UnitSystem(cfg.Units)
 }
 
 func (cfg *RootCmdOptions) SetupStore(ctx context.Context) (*query.Queries, error) {
-	return InitStore(ctx, defaultDBPath())
+	return InitStore(ctx, cfg.DBPath())
+}
+
+func (cfg *RootCmdOptions) SetupDB(ctx context.Context) (*sql.DB, error) {
+	return InitDB(ctx, cfg.DBPath())
 }
 
 type OutputFormat string
@@ -29,16 +69,33 @@ const (
 	OutputFormatTerm     OutputFormat = "terminal"
 	OutputFormatMarkdown OutputFormat = "markdown"
 	OutputFormatHTML     OutputFormat = "html"
+	OutputFormatJSON     OutputFormat = "json"
+	OutputFormatYAML     OutputFormat = "yaml"
 )
 
+// Structured returns true for the machine-readable formats, which are
+// rendered with renderData instead of renderTable.
+func (f OutputFormat) Structured() bool {
+	return f == OutputFormatJSON || f == OutputFormatYAML
+}
+
 func (cfg *RootCmdOptions) OutputFormat() OutputFormat {
+	if cfg.FormatJSON {
+		return OutputFormatJSON
+	}
+	if cfg.FormatYAML {
+		return OutputFormatYAML
+	}
 	if cfg.FormatHTML {
 		return OutputFormatHTML
 	}
 	if cfg.FormatMarkdown {
 		return OutputFormatMarkdown
 	}
-	return OutputFormatTerm
+	if cfg.FormatTerm || cfg.Output == "" {
+		return OutputFormatTerm
+	}
+	return OutputFormat(cfg.Output)
 }
 
 type RootCmd struct {
@@ -68,6 +125,13 @@ func NewRootCmd(stdin io.Reader, stdout, stderr io.Writer) *RootCmd {
 	cmd.Flags.BoolVar(&cmd.Opts.FormatTerm, 0, "term", "output in terminal format")
 	cmd.Flags.BoolVar(&cmd.Opts.FormatHTML, 0, "html", "output in HTML format")
 	cmd.Flags.BoolVar(&cmd.Opts.FormatMarkdown, 0, "markdown", "output in Markdown format")
+	cmd.Flags.BoolVar(&cmd.Opts.FormatJSON, 0, "json", "output in JSON format")
+	cmd.Flags.BoolVar(&cmd.Opts.FormatYAML, 0, "yaml", "output in YAML format")
+	cmd.Flags.StringEnumVar(&cmd.Opts.Output, 0, "output", "default output format: terminal, markdown, html, json or yaml", "terminal", "markdown", "html", "json", "yaml")
+	cmd.Flags.StringEnumVar(&cmd.Opts.Units, 0, "units", "unit system of displayed amounts: metric or imperial", "metric", "imperial")
+	cmd.Flags.StringVar(&cmd.Opts.DB, 0, "db", "", "path of the database file")
+	cmd.Flags.StringVar(&cmd.Opts.Config, 0, "config", "", "path of the config file")
+	cmd.Flags.StringVar(&cmd.Opts.Profile, 0, "profile", "", "profile of the config file to use")
 	cmd.Command = &ff.Command{
 		Name:      CmdLabel,
 		ShortHelp: "sourdough is a CLI tool for managing recipes and baking sourdough bread",
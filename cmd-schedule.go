@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/peterbourgon/ff/v4"
+	"github.com/simonklee/sourdough/ical"
+	"github.com/simonklee/sourdough/query"
+	"github.com/simonklee/sourdough/recipe"
+)
+
+type ScheduleCmdOptions struct {
+	ReadyAt string
+	StartAt string
+	ICS     bool
+	Alarm   time.Duration
+
+	Root *RootCmdOptions
+}
+
+type ScheduleCmd struct {
+	Opts ScheduleCmdOptions
+
+	root    *RootCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func NewScheduleCmd(parent *RootCmd) *ScheduleCmd {
+	var cmd ScheduleCmd
+	cmd.Opts.Root = &parent.Opts
+	cmd.root = parent
+	cmd.Flags = ff.NewFlagSet("schedule").SetParent(parent.Flags)
+	cmd.Flags.StringVar(&cmd.Opts.ReadyAt, 'r', "ready-at", "", "when the last step should end (e.g. \"2026-10-20 08:00\" or 08:00)")
+	cmd.Flags.StringVar(&cmd.Opts.StartAt, 's', "start-at", "", "when the first step starts, instead of --ready-at")
+	cmd.Flags.BoolVar(&cmd.Opts.ICS, 0, "ics", "write an iCalendar file with an event for each step to stdout")
+	cmd.Flags.DurationVar(&cmd.Opts.Alarm, 0, "alarm", 10*time.Minute, "how long before a step starts to remind of it in the --ics file")
+	cmd.Command = &ff.Command{
+		Name:      "schedule",
+		Usage:     CmdLabel + " schedule <recipe> [flags]",
+		ShortHelp: "plan the steps of a recipe on the clock",
+		LongHelp: `  Calculates when each step of the method of a recipe starts
+  and ends. With --ready-at the timeline is calculated back
+  from when the last step should end, e.g. when the bread
+  comes out of the oven. With --start-at it's calculated
+  forwards from when the first step starts.
+
+  A step with a duration range, such as a 12-16h cold retard,
+  makes the times of the steps before it, or after it with
+  --start-at, a window: the step may start at any time within
+  it. A time without a date is the next time it's on the
+  clock.
+
+  With --ics the schedule is written as an iCalendar file to
+  import into a calendar app, with an event for each step and
+  a reminder before it starts. A step with a duration range
+  lasts its longest duration and has a second reminder when
+  its shortest duration has passed.
+
+  Example:
+
+     $ sourdough schedule 1 --ready-at "2026-10-20 08:00"
+     $ sourdough schedule Country --start-at 09:00
+     $ sourdough schedule 1 --ready-at 08:00 --ics > country.ics
+
+`,
+		Flags: cmd.Flags,
+		Exec:  ScheduleCmdExec(&cmd.Opts),
+	}
+	cmd.root.Command.Subcommands = append(cmd.root.Command.Subcommands, cmd.Command)
+	return &cmd
+}
+
+func ScheduleCmdExec(opts *ScheduleCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return errors.New("requires a recipe ID or name")
+		}
+
+		if (opts.ReadyAt == "") == (opts.StartAt == "") {
+			return errors.New("requires either --ready-at or --start-at")
+		}
+
+		now := time.Now()
+		var (
+			at  time.Time
+			err error
+		)
+		if opts.ReadyAt != "" {
+			at, err = parseClockTime(opts.ReadyAt, now)
+		} else {
+			at, err = parseClockTime(opts.StartAt, now)
+		}
+		if err != nil {
+			return err
+		}
+
+		db, err := opts.Root.SetupStore(ctx)
+		if err != nil {
+			return err
+		}
+
+		r, err := findRecipe(ctx, db, args[0])
+		if err != nil {
+			return err
+		}
+
+		steps, err := loadSteps(ctx, db, r.ID)
+		if err != nil {
+			return err
+		}
+		if len(steps) == 0 {
+			return fmt.Errorf("recipe %s has no steps, add them with %s add step", r.Name, CmdLabel)
+		}
+
+		method := make([]recipe.Step, len(steps))
+		for i, step := range steps {
+			method[i] = step.Step
+		}
+
+		if opts.ICS {
+			icsOpts := ical.Options{Alarm: opts.Alarm}
+			if opts.ReadyAt != "" {
+				icsOpts.ReadyAt = at
+			} else {
+				icsOpts.StartAt = at
+			}
+
+			cal, err := ical.Schedule(r.Name, method, icsOpts)
+			if err != nil {
+				return err
+			}
+			return ical.Write(opts.Root.Stdout, cal)
+		}
+
+		var scheduled []recipe.ScheduledStep
+		if opts.ReadyAt != "" {
+			scheduled = recipe.Schedule(method, at)
+		} else {
+			scheduled = recipe.ScheduleFrom(method, at)
+		}
+
+		return ScheduleView{
+			Recipe: r,
+			Steps:  steps,
+			Times:  scheduled,
+		}.Render(ctx, opts.Root.Stdout, opts.Root.OutputFormat())
+	}
+}
+
+// clockTimeLayouts are the layouts accepted by parseClockTime, in the local
+// time zone unless the time has an offset.
+var clockTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+// parseClockTime parses a date and time such as "2026-10-20 08:00". A time
+// without a date, such as "08:00", is the next time it's on the clock after
+// now.
+func parseClockTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range clockTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	clock, err := time.ParseInLocation("15:04", value, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %s, expected e.g. \"2026-10-20 08:00\" or 08:00", value)
+	}
+
+	t := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !t.After(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+type ScheduleView struct {
+	Recipe query.Recipe
+	Steps  []recipeStep
+	Times  []recipe.ScheduledStep
+}
+
+func (v ScheduleView) Render(ctx context.Context, w io.Writer, format OutputFormat) error {
+	if format.Structured() {
+		return renderData(w, format, v.Output())
+	}
+
+	tw := table.NewWriter()
+	tw.SetStyle(table.StyleLight)
+	tw.SetTitle(fmt.Sprintf("Schedule: %s", v.Recipe.Name))
+	tw.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 4, WidthMax: 48},
+		{Number: 5, Align: text.AlignRight},
+		{Number: 6, Align: text.AlignRight},
+	})
+
+	tw.AppendHeader(table.Row{"#", "Start", "End", "Step", "Duration", "Temperature"})
+	for i, step := range v.Times {
+		tw.AppendRow(table.Row{
+			v.Steps[i].ID,
+			formatTimeRange(step.Start),
+			formatTimeRange(step.End),
+			step.Text,
+			formatStepDuration(step.Duration),
+			formatStepTemperature(step.Temperature),
+		})
+	}
+
+	return renderTable(w, format, tw)
+}
+
+// formatTimeRange formats a window of clock times, e.g. "Mon 19 Oct 15:20"
+// or "Mon 19 Oct 15:20–19:20".
+func formatTimeRange(t recipe.TimeRange) string {
+	const layout = "Mon 02 Jan 15:04"
+	if t.IsFixed() {
+		return t.Earliest.Format(layout)
+	}
+
+	latest := t.Latest.Format(layout)
+	if t.Earliest.Format(time.DateOnly) == t.Latest.Format(time.DateOnly) {
+		latest = t.Latest.Format("15:04")
+	}
+	return t.Earliest.Format(layout) + "–" + latest
+}
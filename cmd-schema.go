@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+
+	"github.com/peterbourgon/ff/v4"
+)
+
+type SchemaCmdOptions struct {
+	Root *RootCmdOptions
+}
+
+type SchemaCmd struct {
+	Opts SchemaCmdOptions
+
+	root    *RootCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func NewSchemaCmd(parent *RootCmd) *SchemaCmd {
+	var cmd SchemaCmd
+	cmd.Opts.Root = &parent.Opts
+	cmd.root = parent
+	cmd.Flags = ff.NewFlagSet("schema").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "schema",
+		Usage:     CmdLabel + " schema",
+		ShortHelp: "print the JSON Schema of the --json and --yaml output",
+		Flags:     cmd.Flags,
+		Exec:      SchemaCmdExec(&cmd.Opts),
+	}
+	cmd.root.Command.Subcommands = append(cmd.root.Command.Subcommands, cmd.Command)
+	return &cmd
+}
+
+func SchemaCmdExec(opts *SchemaCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		_, err := opts.Root.Stdout.Write(outputSchema)
+		return err
+	}
+}
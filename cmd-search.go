@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/peterbourgon/ff/v4"
+	"github.com/simonklee/sourdough/query"
+)
+
+type SearchCmdOptions struct {
+	Root *RootCmdOptions
+}
+
+type SearchCmd struct {
+	Opts SearchCmdOptions
+
+	root    *RootCmd
+	Flags   *ff.FlagSet
+	Command *ff.Command
+}
+
+func NewSearchCmd(parent *RootCmd) *SearchCmd {
+	var cmd SearchCmd
+	cmd.Opts.Root = &parent.Opts
+	cmd.root = parent
+	cmd.Flags = ff.NewFlagSet("search").SetParent(parent.Flags)
+	cmd.Command = &ff.Command{
+		Name:      "search",
+		Usage:     CmdLabel + " search [flags] <query>",
+		ShortHelp: "search recipes",
+		LongHelp: `  Searches the names, ingredients, ingredient kinds and notes
+  of all recipes, best match first. All terms must match and
+  are matched as prefixes, so "whol" matches "Whole". Quote a
+  term to match a phrase, and restrict a term to a column with
+  name:, ingredient:, kind: or notes:.
+
+  Example:
+
+     $ sourdough search country
+     $ sourdough search kind:sourdough ingredient:"whole grain"
+
+`,
+		Flags: cmd.Flags,
+		Exec:  SearchCmdExec(&cmd.Opts),
+	}
+	cmd.root.Command.Subcommands = append(cmd.root.Command.Subcommands, cmd.Command)
+	return &cmd
+}
+
+func SearchCmdExec(opts *SearchCmdOptions) CmdExec {
+	return func(ctx context.Context, args []string) error {
+		q := strings.Join(args, " ")
+		if strings.TrimSpace(q) == "" {
+			return errors.New("requires a search query")
+		}
+
+		db, err := opts.Root.SetupDB(ctx)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		results, err := searchRecipes(ctx, db, q)
+		if err != nil {
+			return err
+		}
+
+		format := opts.Root.OutputFormat()
+		if format.Structured() {
+			return renderData(opts.Root.Stdout, format, searchOutput(q, results))
+		}
+
+		if len(results) == 0 {
+			return nil
+		}
+
+		recipes := make([]query.Recipe, len(results))
+		snippets := make([]string, len(results))
+		for i, r := range results {
+			recipes[i] = query.Recipe{ID: r.ID, Name: r.Name}
+			snippets[i] = r.Snippet
+		}
+
+		return listRecipes(opts.Root.Stdout, format, "Search: "+q, recipes, snippets)
+	}
+}
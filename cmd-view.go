@@ -5,7 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
@@ -16,7 +17,14 @@ import (
 
 type ViewCmdOptions struct {
 	Dependencies    []string
+	Limits          []string
+	Scale           float64
 	OnlyIngredients bool
+	DDT             string
+	RoomTemp        string
+	FlourTemp       string
+	StarterTemp     string
+	Friction        string
 
 	Root *RootCmdOptions
 }
@@ -34,8 +42,15 @@ func NewViewCmd(parent *RootCmd) *ViewCmd {
 	cmd.Opts.Root = &parent.Opts
 	cmd.root = parent
 	cmd.Flags = ff.NewFlagSet("view").SetParent(parent.Flags)
-	cmd.Flags.StringListVar(&cmd.Opts.Dependencies, 'd', "dependency", "dependency (e.g. --dependency \"total_floor 450g\")")
+	cmd.Flags.StringListVar(&cmd.Opts.Dependencies, 'd', "dependency", "dependency (e.g. --dependency \"total_flour 450g\")")
+	cmd.Flags.StringListVar(&cmd.Opts.Limits, 'l', "limit", "available amount of an ingredient (e.g. --limit \"Sourdough Starter 230g\")")
+	cmd.Flags.Float64Var(&cmd.Opts.Scale, 's', "scale", 1, "factor applied to fixed-amount ingredients")
 	cmd.Flags.BoolVar(&cmd.Opts.OnlyIngredients, 'i', "ingredients", "only display ingredients")
+	cmd.Flags.StringVar(&cmd.Opts.DDT, 0, "ddt", "", "desired dough temperature, shows the water temperature to use (e.g. 25C)")
+	cmd.Flags.StringVar(&cmd.Opts.RoomTemp, 0, "room-temp", "21C", "room temperature used with --ddt")
+	cmd.Flags.StringVar(&cmd.Opts.FlourTemp, 0, "flour-temp", "", "flour temperature used with --ddt (default: room temperature)")
+	cmd.Flags.StringVar(&cmd.Opts.StarterTemp, 0, "starter-temp", "", "starter temperature used with --ddt (default: room temperature)")
+	cmd.Flags.StringVar(&cmd.Opts.Friction, 0, "friction", "", "temperature rise from mixing used with --ddt (default: 0 on the scale of the target)")
 
 	cmd.Command = &ff.Command{
 		Name:      "view",
@@ -46,9 +61,27 @@ func NewViewCmd(parent *RootCmd) *ViewCmd {
   a specific portion use the --dependencies flag to specify 
   the dependencies.
   
+  An ingredient's dependency may be total_flour, total_water,
+  total_dough, per_piece or the name of another ingredient.
+  Instead of total_flour any derived amount may be given and
+  total flour is solved from it.
+
   Example:
   
      $ sourdough view --dependency "total_flour 450g" 1
+     $ sourdough view --dependency "total_dough 1800g" 1
+
+  To scale the recipe to what you have on hand use --limit.
+  With several limits the one allowing the smallest batch
+  is used.
+
+     $ sourdough view --limit "Sourdough Starter 230g" 1
+
+  To get the water temperature for a desired dough temperature
+  use --ddt. The starter is included if the recipe has a
+  sourdough ingredient or a preferment stage.
+
+     $ sourdough view --ddt 25C --room-temp 22C 1
 
 `,
 		Flags: cmd.Flags,
@@ -64,71 +97,285 @@ func ViewCmdExec(opts *ViewCmdOptions) CmdExec {
 			return errors.New("requires a recipe ID or name")
 		}
 
-		value := args[0]
-		recipeID, err := strconv.ParseInt(value, 10, 64)
+		dependencies, err := recipe.ParseDependencies(opts.Dependencies)
 		if err != nil {
-			return fmt.Errorf("invalid recipe ID: %w", err)
+			return err
 		}
 
-		dependencies, err := recipe.ParseDependencies(opts.Dependencies)
+		db, err := opts.Root.SetupStore(ctx)
 		if err != nil {
 			return err
 		}
 
-		db, err := opts.Root.SetupStore(ctx)
+		r, err := findRecipe(ctx, db, args[0])
+		if err != nil {
+			return err
+		}
+
+		ingredientRows, err := db.ListRecipeIngredients(ctx, r.ID)
 		if err != nil {
 			return err
 		}
 
-		r, err := db.GetRecipe(ctx, recipeID)
-		if err != nil && errors.Is(err, query.ErrNotFound) {
-			fmt.Fprintf(opts.Root.Stdout, "recipe %d not found\n", recipeID)
-			return nil
-		} else if err != nil {
+		steps, err := loadSteps(ctx, db, r.ID)
+		if err != nil {
 			return err
 		}
 
-		ingredientRows, err := db.ListRecipeIngredients(ctx, recipeID)
+		limits, err := recipe.ParseDependencies(opts.Limits)
 		if err != nil {
 			return err
 		}
 
-		var portionIngredients []recipe.PortionIngredient
-		if len(dependencies) > 0 {
-			ingredients := make([]recipe.RecipeIngredient, 0, len(ingredientRows))
-			for _, row := range ingredientRows {
-				ingredients = append(ingredients, recipe.RecipeIngredient{
-					Name:               row.Name,
-					Kind:               row.Kind,
-					PreferUnitCategory: row.PreferUnitCategory,
-					Percentage:         row.Percentage,
-					Dependency:         row.Dependency,
-				})
+		ingredients := make([]recipe.RecipeIngredient, 0, len(ingredientRows))
+		for _, row := range ingredientRows {
+			ingredients = append(ingredients, recipeIngredientFromRow(row))
+		}
+
+		var (
+			bounds  []recipe.Bound
+			binding int
+		)
+		if len(limits) > 0 {
+			bounds, binding, err = recipe.Limit(ingredients, limits)
+			if err != nil {
+				return err
 			}
 
-			portionIngredients, err = recipe.Calculate(ingredients, dependencies)
+			dependencies = append(dependencies, recipe.Dependency{
+				Label: recipe.DependencyTotalFlour,
+				Value: bounds[binding].TotalFlour,
+			})
+		}
+
+		var (
+			portionIngredients []recipe.PortionIngredient
+			stages             []recipe.StagePortion
+		)
+		if len(dependencies) > 0 && recipe.IsStaged(ingredients) {
+			stages, err = recipe.CalculateStages(ingredients, dependencies, opts.Scale)
 			if err != nil {
 				return err
 			}
+		} else if len(dependencies) > 0 {
+			portionIngredients, err = recipe.CalculateScaled(ingredients, dependencies, opts.Scale)
+			if err != nil {
+				return err
+			}
+		}
+
+		summary, nominal := summarizeRecipe(ingredients, portionIngredients, stages)
+
+		var (
+			target, water *recipe.Temperature
+			ferment       *FermentEstimate
+		)
+		if opts.DDT != "" {
+			ddt, err := parseDDT(opts.DDT, opts.RoomTemp, opts.FlourTemp, opts.StarterTemp, opts.Friction, hasStarter(ingredients))
+			if err != nil {
+				return err
+			}
+
+			t := recipe.WaterTemperature(ddt)
+			target, water = &ddt.Target, &t
+
+			if ferment, err = estimateFerment(ctx, db, r, ddt.Target, ddt.Room); err != nil {
+				return err
+			}
+		}
+
+		bakes, err := db.ListRecipeBakes(ctx, query.ListRecipeBakesParams{RecipeID: &r.ID, Limit: recentBakes})
+		if err != nil {
+			return err
 		}
 
 		return RecipeView{
 			Recipe:       r,
 			Ingredients:  ingredientRows,
+			Steps:        steps,
 			Portions:     portionIngredients,
+			Stages:       stages,
+			Summary:      summary,
+			Nominal:      nominal,
+			Bounds:       bounds,
+			Binding:      binding,
 			OnlyPortions: opts.OnlyIngredients,
+			Units:        opts.Root.UnitSystem(),
+			Target:       target,
+			Water:        water,
+			Ferment:      ferment,
+			Bakes:        bakes,
 		}.Render(ctx, opts.Root.Stdout, opts.Root.OutputFormat())
 	}
 }
 
+// nominalTotalFlour is used to summarize a recipe template when no
+// dependencies are given. Only the ratios of the result are meaningful.
+var nominalTotalFlour = recipe.Dependency{
+	Label: recipe.DependencyTotalFlour,
+	Value: recipe.UnitGrams.Tuple(1000),
+}
+
+// summarizeRecipe returns the baker's math of the calculated portions or
+// stages. Without either the template is calculated for a nominal amount of
+// flour and true is returned to signal that only the ratios are meaningful.
+func summarizeRecipe(ingredients []recipe.RecipeIngredient, portions []recipe.PortionIngredient, stages []recipe.StagePortion) (*recipe.Summary, bool) {
+	var summary recipe.Summary
+	switch {
+	case len(stages) > 0:
+		summary = recipe.SummarizeStages(stages)
+	case len(portions) > 0:
+		summary = recipe.Summarize(portions)
+	case recipe.IsStaged(ingredients):
+		stages, err := recipe.CalculateStages(ingredients, []recipe.Dependency{nominalTotalFlour}, 1)
+		if err != nil {
+			return nil, false
+		}
+		return summarizeNominal(recipe.SummarizeStages(stages))
+	default:
+		portions, err := recipe.Calculate(ingredients, []recipe.Dependency{nominalTotalFlour})
+		if err != nil {
+			return nil, false
+		}
+		return summarizeNominal(recipe.Summarize(portions))
+	}
+
+	return &summary, false
+}
+
+func summarizeNominal(summary recipe.Summary) (*recipe.Summary, bool) {
+	if summary.Flour == 0 {
+		return nil, false
+	}
+	return &summary, true
+}
+
+// hasStarter returns true if the recipe is leavened by a starter, either a
+// sourdough ingredient or a preferment stage.
+func hasStarter(ingredients []recipe.RecipeIngredient) bool {
+	for _, ingredient := range ingredients {
+		if ingredient.Kind == recipe.KindSourdough {
+			return true
+		}
+	}
+	return recipe.IsStaged(ingredients)
+}
+
+// recipeIngredientFromRow converts a database row into a recipe template
+// ingredient.
+func recipeIngredientFromRow(row query.ListRecipeIngredientsRow) recipe.RecipeIngredient {
+	ingredient := recipe.RecipeIngredient{
+		Name:               row.Name,
+		Kind:               row.Kind,
+		PreferUnitCategory: row.PreferUnitCategory,
+		Mode:               row.Mode,
+		Percentage:         row.Percentage,
+		Dependency:         row.Dependency,
+	}
+	if row.Stage != nil {
+		ingredient.Stage = *row.Stage
+	}
+	if row.GroupName != nil {
+		ingredient.Group = *row.GroupName
+	}
+	if row.Hydration != nil {
+		ingredient.Hydration = *row.Hydration
+	}
+	if row.Density != nil {
+		ingredient.Density = *row.Density
+	}
+	if row.PieceWeight != nil {
+		ingredient.PieceWeight = *row.PieceWeight
+	}
+	if row.Amount != nil {
+		ingredient.Amount = row.Unit.Tuple(*row.Amount)
+	}
+	return ingredient
+}
+
+// recipeStep is a step of a recipe and the ID of its row.
+type recipeStep struct {
+	ID int64
+	recipe.Step
+}
+
+// loadSteps returns the steps of a recipe in order.
+func loadSteps(ctx context.Context, db *query.Queries, recipeID int64) ([]recipeStep, error) {
+	rows, err := db.ListSteps(ctx, recipeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list steps: %w", err)
+	}
+
+	ingredientRows, err := db.ListStepIngredients(ctx, recipeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list step ingredients: %w", err)
+	}
+
+	ingredients := map[int64][]recipe.StepIngredient{}
+	for _, row := range ingredientRows {
+		ingredient := recipe.StepIngredient{Name: row.Name}
+		if row.Stage != nil {
+			ingredient.Stage = *row.Stage
+		}
+		ingredients[row.StepID] = append(ingredients[row.StepID], ingredient)
+	}
+
+	steps := make([]recipeStep, 0, len(rows))
+	for _, row := range rows {
+		steps = append(steps, recipeStep{ID: row.ID, Step: stepFromRow(row, ingredients[row.ID])})
+	}
+	return steps, nil
+}
+
+// stepFromRow converts a database row into a recipe step.
+func stepFromRow(row query.Step, ingredients []recipe.StepIngredient) recipe.Step {
+	step := recipe.Step{Text: row.Text, Ingredients: ingredients}
+	if row.DurationMin != nil && row.DurationMax != nil {
+		step.Duration = recipe.DurationRange{
+			Min: time.Duration(*row.DurationMin) * time.Minute,
+			Max: time.Duration(*row.DurationMax) * time.Minute,
+		}
+	}
+	if row.Temperature != nil {
+		step.Temperature = &recipe.Temperature{Value: *row.Temperature, Scale: row.TemperatureScale}
+	}
+	return step
+}
+
 type RecipeView struct {
 	Recipe       query.Recipe
 	Ingredients  []query.ListRecipeIngredientsRow
+	Steps        []recipeStep
 	Portions     []recipe.PortionIngredient
+	Stages       []recipe.StagePortion
+	Summary      *recipe.Summary
+	Nominal      bool
+	Bounds       []recipe.Bound
+	Binding      int
 	OnlyPortions bool
+
+	// Units is the unit system amounts are displayed in.
+	Units recipe.UnitSystem
+
+	// Target is the desired dough temperature and Water the water
+	// temperature needed to reach it.
+	Target *recipe.Temperature
+	Water  *recipe.Temperature
+
+	// Ferment is the estimated bulk fermentation time at the desired
+	// dough temperature and proof time at room temperature, nil for a
+	// dough without starter.
+	Ferment *FermentEstimate
+
+	// Bakes are the latest bakes of the recipe, latest first.
+	Bakes []query.Bake
 }
 
 func (r RecipeView) Render(ctx context.Context, w io.Writer, format OutputFormat) error {
+	if format.Structured() {
+		return renderData(w, format, r.Output())
+	}
+
 	if !r.OnlyPortions {
 		// Create a new table writer
 		tw := table.NewWriter()
@@ -139,18 +386,53 @@ func (r RecipeView) Render(ctx context.Context, w io.Writer, format OutputFormat
 		// Set table title
 		tw.SetTitle(fmt.Sprintf("Recipe: %s", r.Recipe.Name))
 
+		staged, grouped := false, false
+		for _, ingredient := range r.Ingredients {
+			staged = staged || ingredient.Stage != nil
+			grouped = grouped || ingredient.GroupName != nil
+		}
+
 		// Configure columns
-		tw.AppendHeader(table.Row{"#", "Ingredient", "Kind", "Prefer Category", "Percentage", "Dependency"})
+		header := table.Row{"#", "Ingredient", "Kind", "Prefer Category", "Percentage", "Amount", "Dependency"}
+		if staged {
+			header = append(header, "Stage")
+		}
+		if grouped {
+			header = append(header, "Group")
+		}
+		tw.AppendHeader(header)
 		for _, ingredient := range r.Ingredients {
-			// v, _ := strconv.ParseFloat(fmt.Sprintf("%f", ingredient.Percentage*100), 64)
-			tw.AppendRow(table.Row{
+			var percentage, amount any = "", ""
+			if ingredient.Mode == recipe.ModeAmount && ingredient.Amount != nil {
+				amount = ingredient.Unit.Format(*ingredient.Amount)
+			} else {
+				percentage = ingredient.Percentage
+			}
+
+			row := table.Row{
 				ingredient.ID,
 				ingredient.Name,
 				ingredient.Kind,
 				ingredient.PreferUnitCategory,
-				ingredient.Percentage,
+				percentage,
+				amount,
 				ingredient.Dependency,
-			})
+			}
+			if staged {
+				stage := "final dough"
+				if ingredient.Stage != nil {
+					stage = *ingredient.Stage
+				}
+				row = append(row, stage)
+			}
+			if grouped {
+				var group string
+				if ingredient.GroupName != nil {
+					group = *ingredient.GroupName
+				}
+				row = append(row, group)
+			}
+			tw.AppendRow(row)
 		}
 
 		if err := renderTable(w, format, tw); err != nil {
@@ -159,27 +441,106 @@ func (r RecipeView) Render(ctx context.Context, w io.Writer, format OutputFormat
 	}
 
 	if len(r.Portions) > 0 {
+		title := fmt.Sprintf("Ingredients for: %s", r.Recipe.Name)
+		if err := renderPortions(w, format, title, r.Portions, false, r.Units); err != nil {
+			return err
+		}
+	}
+
+	for _, stage := range r.Stages {
+		title := fmt.Sprintf("%s for: %s", stage.Name, r.Recipe.Name)
+		if stage.Name == recipe.FinalDough {
+			title = fmt.Sprintf("Final Dough for: %s", r.Recipe.Name)
+		}
+		if err := renderPortions(w, format, title, stage.Ingredients, true, r.Units); err != nil {
+			return err
+		}
+	}
+
+	if len(r.Stages) > 0 {
+		title := fmt.Sprintf("Overall Formula: %s", r.Recipe.Name)
+		if err := renderPortions(w, format, title, recipe.Overall(r.Stages), true, r.Units); err != nil {
+			return err
+		}
+	}
+
+	if len(r.Steps) > 0 && !r.OnlyPortions {
+		if err := renderSteps(w, format, fmt.Sprintf("Method: %s", r.Recipe.Name), r.Steps); err != nil {
+			return err
+		}
+	}
+
+	if r.Summary != nil && !(r.OnlyPortions && r.Nominal) {
 		tw := table.NewWriter()
 		tw.SetStyle(table.StyleLight)
-		tw.SetTitle(fmt.Sprintf("Ingredients for: %s", r.Recipe.Name))
+		tw.SetTitle(fmt.Sprintf("Summary: %s", r.Recipe.Name))
 		tw.SetColumnConfigs([]table.ColumnConfig{
+			{Number: 2, Align: text.AlignRight},
+		})
+
+		if !r.Nominal {
+			tw.AppendRows([]table.Row{
+				{"Total flour", r.Units.Convert(recipe.UnitGrams.Tuple(r.Summary.Flour)).Format()},
+				{"Total water", r.Units.Convert(recipe.UnitGrams.Tuple(r.Summary.Water)).Format()},
+				{"Total dough", r.Units.Convert(recipe.UnitGrams.Tuple(r.Summary.Dough)).Format()},
+			})
+		}
+		tw.AppendRows([]table.Row{
+			{"Hydration", formatPercentage(r.Summary.Hydration())},
+			{"Prefermented flour", formatPercentage(r.Summary.PrefermentedFlourRatio())},
+			{"Salt", formatPercentage(r.Summary.SaltRatio())},
+		})
+
+		if r.Water != nil {
+			tw.AppendRows([]table.Row{
+				{"Dough temperature", r.Target.Format()},
+				{"Water temperature", r.Water.Format()},
+			})
+		}
+		appendFermentRows(tw, r.Ferment)
+
+		if err := renderTable(w, format, tw); err != nil {
+			return err
+		}
+	} else if r.Water != nil {
+		tw := table.NewWriter()
+		tw.SetStyle(table.StyleLight)
+		tw.SetTitle(fmt.Sprintf("DDT: %s", r.Recipe.Name))
+		tw.SetColumnConfigs([]table.ColumnConfig{
+			{Number: 2, Align: text.AlignRight},
+		})
+		tw.AppendRows([]table.Row{
+			{"Dough temperature", r.Target.Format()},
+			{"Water temperature", r.Water.Format()},
+		})
+		appendFermentRows(tw, r.Ferment)
+
+		if err := renderTable(w, format, tw); err != nil {
+			return err
+		}
+	}
+
+	if len(r.Bounds) > 0 {
+		tw := table.NewWriter()
+		tw.SetStyle(table.StyleLight)
+		tw.SetTitle("Limits")
+		tw.SetColumnConfigs([]table.ColumnConfig{
+			{Number: 2, Align: text.AlignRight},
 			{Number: 3, Align: text.AlignRight},
 		})
 
-		tw.AppendHeader(table.Row{"#", "Ingredient", "Amount"})
-		for i, portion := range r.Portions {
-			value := portion.Value
-			if v, err := value.ConvertIngredient(
-				recipe.DefaultFromUnitCategory(portion.PreferUnitCategory),
-				portion.Kind,
-			); err == nil {
-				value = v
+		tw.AppendHeader(table.Row{"Limit", "Available", "Total Flour", ""})
+		for i, bound := range r.Bounds {
+			var binding string
+			if i == r.Binding {
+				binding = "limiting"
 			}
 
 			tw.AppendRow(table.Row{
-				i + 1,
-				portion.Name,
-				value.Appropriate().Format(),
+				bound.Limit.Label,
+				r.Units.Convert(bound.Limit.Value).Format(),
+				r.Units.Convert(bound.TotalFlour).Format(),
+				binding,
 			})
 		}
 
@@ -188,5 +549,141 @@ func (r RecipeView) Render(ctx context.Context, w io.Writer, format OutputFormat
 		}
 	}
 
+	if len(r.Bakes) > 0 && !r.OnlyPortions {
+		if err := renderBakes(w, format, fmt.Sprintf("Recent Bakes: %s", r.Recipe.Name), r.Bakes); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// renderPortions renders the amounts of a list of portion ingredients. With
+// percentages each amount is also shown as a baker's percentage of the flour
+// in the list. Amounts are displayed in the given unit system. Grouped
+// ingredients are listed together, followed by the subtotal of the group.
+func renderPortions(w io.Writer, format OutputFormat, title string, portions []recipe.PortionIngredient, percentages bool, units recipe.UnitSystem) error {
+	tw := table.NewWriter()
+	tw.SetStyle(table.StyleLight)
+	tw.SetTitle(title)
+	tw.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 3, Align: text.AlignRight},
+		{Number: 4, Align: text.AlignRight},
+	})
+
+	totalFlour := recipe.TotalFlour(portions)
+	percentage := func(grams float64) string {
+		if totalFlour == 0 {
+			return ""
+		}
+		return formatPercentage(grams / totalFlour)
+	}
+
+	header := table.Row{"#", "Ingredient", "Amount"}
+	if percentages {
+		header = append(header, "Percentage")
+	}
+	tw.AppendHeader(header)
+
+	var n int
+	for i, group := range recipe.GroupPortions(portions) {
+		if i > 0 {
+			tw.AppendSeparator()
+		}
+
+		for _, portion := range group.Ingredients {
+			n++
+			row := table.Row{
+				n,
+				portion.Name,
+				portion.PreferredIn(units).Format(),
+			}
+			if percentages {
+				var p string
+				if v, err := portion.Value.ConvertWith(recipe.UnitGrams, portion.Properties()); err == nil {
+					p = percentage(v.Value)
+				}
+				row = append(row, p)
+			}
+			tw.AppendRow(row)
+		}
+
+		if group.Name == "" {
+			continue
+		}
+
+		weight := group.Weight()
+		row := table.Row{
+			"",
+			fmt.Sprintf("%s subtotal", group.Name),
+			units.Convert(recipe.UnitGrams.Tuple(weight)).Format(),
+		}
+		if percentages {
+			row = append(row, percentage(weight))
+		}
+		tw.AppendRow(row)
+	}
+
+	return renderTable(w, format, tw)
+}
+
+// appendFermentRows appends the estimated fermentation times to a summary.
+func appendFermentRows(tw table.Writer, e *FermentEstimate) {
+	if e == nil {
+		return
+	}
+	tw.AppendRows([]table.Row{
+		{fmt.Sprintf("Bulk at %s (est.)", e.Bulk.Temperature.Format()), recipe.FixedDuration(e.BulkTime).Format()},
+		{fmt.Sprintf("Proof at %s (est.)", e.Proof.Temperature.Format()), recipe.FixedDuration(e.ProofTime).Format()},
+	})
+}
+
+// formatPercentage formats a ratio as a percentage, e.g. 0.725 as 72.5%.
+func formatPercentage(ratio float64) string {
+	return fmt.Sprintf("%.1f%%", ratio*100)
+}
+
+// renderSteps renders the method of a recipe followed by its total
+// duration.
+func renderSteps(w io.Writer, format OutputFormat, title string, steps []recipeStep) error {
+	tw := table.NewWriter()
+	tw.SetStyle(table.StyleLight)
+	tw.SetTitle(title)
+	tw.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 2, WidthMax: 48},
+		{Number: 3, Align: text.AlignRight},
+		{Number: 4, Align: text.AlignRight},
+		{Number: 5, WidthMax: 32},
+	})
+
+	tw.AppendHeader(table.Row{"#", "Step", "Duration", "Temperature", "Ingredients"})
+	var total recipe.DurationRange
+	for _, step := range steps {
+		total = total.Add(step.Duration)
+		tw.AppendRow(table.Row{
+			step.ID,
+			step.Text,
+			formatStepDuration(step.Duration),
+			formatStepTemperature(step.Temperature),
+			strings.Join(step.IngredientNames(), ", "),
+		})
+	}
+	tw.AppendSeparator()
+	tw.AppendRow(table.Row{"", "Total", formatStepDuration(total), "", ""})
+
+	return renderTable(w, format, tw)
+}
+
+func formatStepDuration(d recipe.DurationRange) string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format()
+}
+
+func formatStepTemperature(t *recipe.Temperature) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format()
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/peterbourgon/ff/v4"
+)
+
+// EnvVarPrefix is the prefix of the environment variables which set flags,
+// e.g. SOURDOUGH_DB for --db.
+const EnvVarPrefix = "SOURDOUGH"
+
+func defaultConfigPath() string {
+	configDir, _ := os.UserConfigDir()
+	return filepath.Join(configDir, "sourdough/config")
+}
+
+// expandHome replaces a leading "~/" in path with the home directory.
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// configurable are the root flags which may be set by the config file.
+var configurable = map[string]bool{
+	"db":      true,
+	"output":  true,
+	"units":   true,
+	"verbose": true,
+}
+
+// LoadConfig applies the config file to the root flags which weren't set on
+// the command line or in the environment.
+//
+// The config file is in the plain format of ff, a flag name and its value on
+// each line. Settings of a profile are prefixed with its name, e.g.
+// "bakery.db", and take precedence over the unprefixed settings when the
+// profile is selected by --profile or a "profile" line.
+//
+//	db ~/bread/sourdough.sqlite
+//	profile home
+//
+//	home.units metric
+//
+//	bakery.db /srv/bakery/sourdough.sqlite
+//	bakery.output markdown
+//	bakery.units imperial
+func (cmd *RootCmd) LoadConfig() error {
+	path := cmd.Opts.Config
+	if path == "" {
+		path = defaultConfigPath()
+	}
+
+	values, err := readConfig(expandHome(path))
+	if errors.Is(err, fs.ErrNotExist) && cmd.Opts.Config == "" {
+		values = map[string]string{}
+	} else if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+
+	profiles := map[string]bool{}
+	settings := map[string]string{}
+	for key, value := range values {
+		profile, name, ok := strings.Cut(key, ".")
+		if !ok {
+			name = key
+		}
+		if !configurable[name] && !(name == "profile" && !ok) {
+			return fmt.Errorf("config file %s: unknown setting %s", path, key)
+		}
+		if ok {
+			profiles[profile] = true
+		} else {
+			settings[name] = value
+		}
+	}
+
+	profile := cmd.Opts.Profile
+	if !isSet(cmd.Flags, "profile") {
+		profile = settings["profile"]
+		cmd.Opts.Profile = profile
+	}
+	delete(settings, "profile")
+
+	if profile != "" {
+		if !profiles[profile] {
+			return fmt.Errorf("unknown profile %s, the config file %s has %s", profile, path, formatProfiles(profiles))
+		}
+		for key, value := range values {
+			if name, ok := strings.CutPrefix(key, profile+"."); ok {
+				settings[name] = value
+			}
+		}
+	}
+
+	for name, value := range settings {
+		if isSet(cmd.Flags, name) {
+			continue
+		}
+
+		f, _ := cmd.Flags.GetFlag(name)
+		if err := f.SetValue(value); err != nil {
+			return fmt.Errorf("config file %s: %s: %w", path, name, err)
+		}
+	}
+
+	return nil
+}
+
+// readConfig reads the settings of a config file. A setting given twice
+// keeps the last value.
+func readConfig(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := map[string]string{}
+	err = ff.PlainParser(f, func(name, value string) error {
+		values[name] = value
+		return nil
+	})
+	return values, err
+}
+
+func formatProfiles(profiles map[string]bool) string {
+	if len(profiles) == 0 {
+		return "no profiles"
+	}
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "profiles " + strings.Join(names, ", ")
+}
@@ -0,0 +1,416 @@
+// Package cooklang reads and writes recipes in the Cooklang markup language,
+// https://cooklang.org.
+//
+// Only what maps to a recipe template is understood: metadata, sections,
+// steps with ingredients, and notes. Cookware and timers are kept as text.
+package cooklang
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/simonklee/sourdough/recipe"
+	"gopkg.in/yaml.v3"
+)
+
+// Recipe is a parsed Cooklang recipe.
+type Recipe struct {
+	// Metadata from YAML front matter or ">> key: value" lines.
+	Metadata map[string]string
+	Sections []Section
+	Notes    []string
+}
+
+// Title returns the title of the recipe from its metadata.
+func (r Recipe) Title() string {
+	return r.Metadata["title"]
+}
+
+// Section is a named part of a recipe, e.g. "= Levain". The steps before the
+// first section are in a section without a name.
+type Section struct {
+	Name  string
+	Steps []Step
+}
+
+// Step is a paragraph of a recipe in Cooklang markup.
+type Step struct {
+	Source      string
+	Ingredients []Ingredient
+}
+
+// Ingredient is an ingredient of a step, e.g. "@bread flour{500%g}".
+// Quantity and Unit are empty if not given.
+type Ingredient struct {
+	Name     string
+	Quantity string
+	Unit     string
+}
+
+var (
+	// ingredientRegexp matches "@name{qty%unit}" where the name may have
+	// several words, or "@name" of a single word. Modifiers such as "@?"
+	// and notes such as "(chopped)" are ignored.
+	ingredientRegexp = regexp.MustCompile(`@[?&+\-]?(?:([^@#~{}\n]+?)\{([^}]*)\}|([^\s@#~{}.,;:!?()]+))(?:\([^)]*\))?`)
+
+	// cookwareRegexp and timerRegexp match "#pot{}", "#pot" and "~{10%minutes}",
+	// "~rise{1%hour}".
+	cookwareRegexp = regexp.MustCompile(`#(?:([^@#~{}\n]+?)\{[^}]*\}|([^\s@#~{}.,;:!?()]+))`)
+	timerRegexp    = regexp.MustCompile(`~[^@#~{}\n]*\{([^}]*)\}`)
+
+	blockCommentRegexp = regexp.MustCompile(`(?s)\[-.*?-\]`)
+	blockCommentLine   = regexp.MustCompile(`(?ms)^[ \t]*\[-.*?-\][ \t]*\n`)
+	sectionRegexp      = regexp.MustCompile(`^=+\s*(.*?)\s*=*$`)
+)
+
+// Text returns the step as plain text without markup.
+func (s Step) Text() string {
+	text := ingredientRegexp.ReplaceAllStringFunc(s.Source, func(m string) string {
+		return parseIngredient(m).Name
+	})
+	text = cookwareRegexp.ReplaceAllString(text, "$1$2")
+	text = timerRegexp.ReplaceAllStringFunc(text, func(m string) string {
+		qty, unit := splitQuantity(timerRegexp.FindStringSubmatch(m)[1])
+		return strings.TrimSpace(qty + " " + unit)
+	})
+	return text
+}
+
+func parseIngredient(m string) Ingredient {
+	matches := ingredientRegexp.FindStringSubmatch(m)
+	if matches[3] != "" {
+		return Ingredient{Name: matches[3]}
+	}
+
+	qty, unit := splitQuantity(matches[2])
+	return Ingredient{Name: strings.TrimSpace(matches[1]), Quantity: qty, Unit: unit}
+}
+
+// splitQuantity splits "500%g" into "500" and "g".
+func splitQuantity(value string) (string, string) {
+	qty, unit, _ := strings.Cut(value, "%")
+	return strings.TrimSpace(qty), strings.TrimSpace(unit)
+}
+
+// Parse parses a Cooklang recipe.
+func Parse(r io.Reader) (Recipe, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Recipe{}, err
+	}
+
+	out := Recipe{Metadata: map[string]string{}}
+
+	text, err := parseFrontMatter(string(data), out.Metadata)
+	if err != nil {
+		return out, err
+	}
+	text = blockCommentLine.ReplaceAllString(text, "")
+	text = blockCommentRegexp.ReplaceAllString(text, "")
+
+	section := Section{}
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+
+		step := Step{Source: strings.Join(paragraph, " ")}
+		for _, m := range ingredientRegexp.FindAllString(step.Source, -1) {
+			step.Ingredients = append(step.Ingredients, parseIngredient(m))
+		}
+		section.Steps = append(section.Steps, step)
+		paragraph = nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "--"); i >= 0 {
+			// A line holding only a comment doesn't end the step.
+			if strings.TrimSpace(line[:i]) == "" {
+				continue
+			}
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, ">>"):
+			flush()
+			key, value, _ := strings.Cut(strings.TrimPrefix(line, ">>"), ":")
+			out.Metadata[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		case strings.HasPrefix(line, ">"):
+			flush()
+			out.Notes = append(out.Notes, strings.TrimSpace(strings.TrimPrefix(line, ">")))
+		case strings.HasPrefix(line, "="):
+			flush()
+			if section.Name != "" || len(section.Steps) > 0 {
+				out.Sections = append(out.Sections, section)
+			}
+			section = Section{Name: sectionRegexp.FindStringSubmatch(line)[1]}
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return out, err
+	}
+
+	flush()
+	if section.Name != "" || len(section.Steps) > 0 {
+		out.Sections = append(out.Sections, section)
+	}
+
+	return out, nil
+}
+
+// parseFrontMatter decodes YAML front matter delimited by "---" lines into
+// metadata and returns the rest of the text.
+func parseFrontMatter(text string, metadata map[string]string) (string, error) {
+	trimmed := strings.TrimLeft(text, "\r\n\t ")
+	if !strings.HasPrefix(trimmed, "---") {
+		return text, nil
+	}
+
+	rest := strings.TrimPrefix(trimmed, "---")
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return text, fmt.Errorf("front matter is not terminated")
+	}
+
+	var fields map[string]any
+	if err := yaml.Unmarshal([]byte(rest[:end]), &fields); err != nil {
+		return text, fmt.Errorf("invalid front matter: %w", err)
+	}
+	for key, value := range fields {
+		metadata[strings.ToLower(key)] = fmt.Sprint(value)
+	}
+
+	rest = rest[end+len("\n---"):]
+	if i := strings.IndexByte(rest, '\n'); i >= 0 {
+		rest = rest[i+1:]
+	} else {
+		rest = ""
+	}
+	return rest, nil
+}
+
+// Template converts the recipe into a recipe template with baker's
+// percentages of the flour of each stage. A section is a stage if a later
+// section uses an ingredient of the same name, e.g. "= Levain" and
+// "@levain{200%g}"; all other sections belong to the final dough. The title
+// is used as the name of the template, or name if there is no title.
+//
+// Ingredients without a quantity or with a unit that isn't understood, e.g.
+// "@garlic{2%cloves}", are listed in the notes together with the steps.
+func (r Recipe) Template(name string) (recipe.Recipe, error) {
+	if title := r.Title(); title != "" {
+		name = title
+	}
+	if name == "" {
+		return recipe.Recipe{}, fmt.Errorf("recipe without a title")
+	}
+
+	var (
+		templates []recipe.RecipeIngredient
+		other     []string
+		method    []string
+	)
+	for i, section := range r.Sections {
+		stage := recipe.FinalDough
+		if r.usedAfter(i) {
+			stage = section.Name
+		}
+
+		for _, step := range section.Steps {
+			method = append(method, step.Text())
+
+			for _, ingredient := range step.Ingredients {
+				amount, err := ingredient.amount()
+				if err != nil {
+					other = append(other, strings.TrimSpace(strings.Join([]string{ingredient.Quantity, ingredient.Unit, ingredient.Name}, " ")))
+					continue
+				}
+
+				templates = append(templates, recipe.RecipeIngredient{
+					Name:   ingredient.Name,
+					Kind:   recipe.GuessKind(ingredient.Name),
+					Mode:   recipe.ModeAmount,
+					Amount: amount,
+					Stage:  stage,
+				})
+			}
+		}
+	}
+
+	templates, err := recipe.ToPercentages(templates)
+	if err != nil {
+		return recipe.Recipe{}, fmt.Errorf("recipe %s: %w", name, err)
+	}
+
+	var notes []string
+	if len(r.Notes) > 0 {
+		notes = append(notes, strings.Join(r.Notes, "\n"))
+	}
+	if len(other) > 0 {
+		notes = append(notes, "Also: "+strings.Join(other, ", "))
+	}
+	notes = append(notes, method...)
+
+	return recipe.Recipe{
+		Name:        name,
+		Notes:       strings.Join(notes, "\n\n"),
+		Ingredients: templates,
+	}, nil
+}
+
+// usedAfter returns true if the section at index i is used as an ingredient
+// by a later section.
+func (r Recipe) usedAfter(i int) bool {
+	name := r.Sections[i].Name
+	if name == "" {
+		return false
+	}
+
+	for _, later := range r.Sections[i+1:] {
+		for _, step := range later.Steps {
+			for _, ingredient := range step.Ingredients {
+				if strings.EqualFold(ingredient.Name, name) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// amount returns the quantity of the ingredient. A quantity without a unit
+// is a number of pieces.
+func (i Ingredient) amount() (recipe.Tuple, error) {
+	if i.Quantity == "" {
+		return recipe.Tuple{}, fmt.Errorf("%s has no quantity", i.Name)
+	}
+
+	unit := i.Unit
+	if unit == "" {
+		unit = string(recipe.UnitPieces)
+	}
+	return recipe.ParseTuple(i.Quantity + " " + unit)
+}
+
+// FromStages returns a recipe with a section for each calculated stage,
+// listing its ingredients in a single step.
+func FromStages(title, notes string, stages []recipe.StagePortion) Recipe {
+	out := Recipe{Metadata: map[string]string{"title": title}}
+	for _, note := range strings.Split(notes, "\n") {
+		if note = strings.TrimSpace(note); note != "" {
+			out.Notes = append(out.Notes, note)
+		}
+	}
+
+	for _, stage := range stages {
+		section := Section{Name: stage.Name}
+		if stage.Name == recipe.FinalDough && len(stages) > 1 {
+			section.Name = "Final Dough"
+		}
+
+		var (
+			markup      []string
+			ingredients []Ingredient
+		)
+		for _, portion := range stage.Ingredients {
+			ingredient := portionIngredient(portion)
+			ingredients = append(ingredients, ingredient)
+			markup = append(markup, ingredient.String())
+		}
+		section.Steps = []Step{{
+			Source:      "Mix " + recipe.JoinList(markup) + ".",
+			Ingredients: ingredients,
+		}}
+
+		out.Sections = append(out.Sections, section)
+	}
+
+	return out
+}
+
+// portionIngredient returns the ingredient of a calculated portion in its
+// preferred unit category.
+func portionIngredient(portion recipe.PortionIngredient) Ingredient {
+	value := portion.Preferred()
+	out := Ingredient{
+		Name:     portion.Name,
+		Quantity: strconv.FormatFloat(math.Round(value.Value*100)/100, 'f', -1, 64),
+		Unit:     value.Unit.String(),
+	}
+	if value.Unit == recipe.UnitPieces {
+		out.Unit = ""
+	}
+	return out
+}
+
+// String returns the ingredient in Cooklang markup.
+func (i Ingredient) String() string {
+	quantity := i.Quantity
+	if i.Unit != "" {
+		quantity += "%" + i.Unit
+	}
+	return "@" + i.Name + "{" + quantity + "}"
+}
+
+// Write writes a recipe in Cooklang markup.
+func Write(w io.Writer, r Recipe) error {
+	bw := bufio.NewWriter(w)
+
+	if len(r.Metadata) > 0 {
+		keys := make([]string, 0, len(r.Metadata))
+		for key := range r.Metadata {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		fmt.Fprintln(bw, "---")
+		for _, key := range keys {
+			value, err := yaml.Marshal(r.Metadata[key])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(bw, "%s: %s", key, value)
+		}
+		fmt.Fprintln(bw, "---")
+		fmt.Fprintln(bw)
+	}
+
+	for _, note := range r.Notes {
+		fmt.Fprintf(bw, "> %s\n", note)
+	}
+	if len(r.Notes) > 0 {
+		fmt.Fprintln(bw)
+	}
+
+	for i, section := range r.Sections {
+		if i > 0 {
+			fmt.Fprintln(bw)
+		}
+		if section.Name != "" {
+			fmt.Fprintf(bw, "= %s\n\n", section.Name)
+		}
+		for j, step := range section.Steps {
+			if j > 0 {
+				fmt.Fprintln(bw)
+			}
+			fmt.Fprintln(bw, step.Source)
+		}
+	}
+
+	return bw.Flush()
+}
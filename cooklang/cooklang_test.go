@@ -0,0 +1,193 @@
+package cooklang
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/simonklee/sourdough/recipe"
+)
+
+func parseFile(t *testing.T, path string) Recipe {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	r, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return r
+}
+
+func TestParse(t *testing.T) {
+	r := parseFile(t, "testdata/country.cook")
+
+	if got := r.Title(); got != "Country Loaf" {
+		t.Errorf("Title() got = %q, want %q", got, "Country Loaf")
+	}
+	if want := []string{"Adapted from Tartine."}; !reflect.DeepEqual(r.Notes, want) {
+		t.Errorf("Parse() notes = %q, want %q", r.Notes, want)
+	}
+	if len(r.Sections) != 2 || r.Sections[0].Name != "Levain" || r.Sections[1].Name != "Dough" {
+		t.Fatalf("Parse() sections = %+v", r.Sections)
+	}
+
+	levain := r.Sections[0].Steps[0]
+	want := []Ingredient{
+		{Name: "whole wheat flour", Quantity: "50", Unit: "g"},
+		{Name: "water", Quantity: "50", Unit: "g"},
+		{Name: "sourdough starter", Quantity: "10", Unit: "g"},
+	}
+	if !reflect.DeepEqual(levain.Ingredients, want) {
+		t.Errorf("Parse() levain ingredients = %+v, want %+v", levain.Ingredients, want)
+	}
+
+	wantText := "Mix whole wheat flour, water and sourdough starter in a jar. Leave for 8 hours."
+	if got := levain.Text(); got != wantText {
+		t.Errorf("Text() got = %q, want %q", got, wantText)
+	}
+
+	dough := r.Sections[1].Steps
+	if len(dough) != 2 {
+		t.Fatalf("Parse() dough steps = %+v", dough)
+	}
+	if got := dough[0].Ingredients[5]; got != (Ingredient{Name: "walnuts"}) {
+		t.Errorf("Parse() ingredient without quantity = %+v", got)
+	}
+	if got, want := dough[1].Text(), "Shape and bake in a dutch oven for 45 minutes."; got != want {
+		t.Errorf("Text() got = %q, want %q", got, want)
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	r := parseFile(t, "testdata/country.cook")
+
+	got, err := r.Template("country")
+	if err != nil {
+		t.Fatalf("Template() error = %v", err)
+	}
+
+	if got.Name != "Country Loaf" {
+		t.Errorf("Template() name = %q, want %q", got.Name, "Country Loaf")
+	}
+	if !strings.Contains(got.Notes, "Also: walnuts, 2 cloves garlic") {
+		t.Errorf("Template() notes = %q, want unquantified ingredients", got.Notes)
+	}
+
+	want := []struct {
+		name       string
+		kind       recipe.Kind
+		stage      string
+		percentage float64
+	}{
+		{"whole wheat flour", recipe.KindFlour, "Levain", 1},
+		{"water", recipe.KindWater, "Levain", 1},
+		{"sourdough starter", recipe.KindSourdough, "Levain", 0.2},
+		{"bread flour", recipe.KindFlour, recipe.FinalDough, 1},
+		{"water", recipe.KindWater, recipe.FinalDough, 325.0 / 450},
+		{"levain", recipe.KindSourdough, recipe.FinalDough, 110.0 / 450},
+		{"salt", recipe.KindSalt, recipe.FinalDough, 10.0 / 450},
+	}
+	if len(got.Ingredients) != len(want) {
+		t.Fatalf("Template() got %d ingredients, want %d: %+v", len(got.Ingredients), len(want), got.Ingredients)
+	}
+	for i, w := range want {
+		g := got.Ingredients[i]
+		if g.Name != w.name || g.Kind != w.kind || g.Stage != w.stage || math.Abs(g.Percentage-w.percentage) > 1e-9 {
+			t.Errorf("Template() ingredient %d = %+v, want %+v", i, g, w)
+		}
+	}
+
+	// The template calculates back to the amounts of the file.
+	stages, err := recipe.CalculateStages(got.Ingredients, []recipe.Dependency{
+		{Label: recipe.DependencyTotalFlour, Value: recipe.UnitGrams.Tuple(450)},
+	}, 1)
+	if err != nil {
+		t.Fatalf("CalculateStages() error = %v", err)
+	}
+	if v := stages[0].Ingredients[0].Value.Value; math.Abs(v-50) > 1e-9 {
+		t.Errorf("CalculateStages() levain flour = %v, want 50", v)
+	}
+}
+
+func TestTemplateWithoutTitle(t *testing.T) {
+	r, err := Parse(strings.NewReader("Mix @flour{1%kg} and @water{700%ml}.\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Template(""); err == nil {
+		t.Error("Template() expected an error without a title")
+	}
+
+	got, err := r.Template("Simple")
+	if err != nil {
+		t.Fatalf("Template() error = %v", err)
+	}
+	if got.Name != "Simple" || got.Ingredients[1].PreferUnitCategory != recipe.UnitCategoryVolume {
+		t.Errorf("Template() got = %+v", got)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	stages := []recipe.StagePortion{
+		{
+			Name: "Levain",
+			Ingredients: []recipe.PortionIngredient{
+				{Name: "Rye Flour", Kind: recipe.KindFlour, PreferUnitCategory: recipe.UnitCategoryWeight, Value: recipe.UnitGrams.Tuple(50)},
+				{Name: "Water", Kind: recipe.KindWater, PreferUnitCategory: recipe.UnitCategoryWeight, Value: recipe.UnitGrams.Tuple(50)},
+			},
+		},
+		{
+			Name: recipe.FinalDough,
+			Ingredients: []recipe.PortionIngredient{
+				{Name: "Bread Flour", Kind: recipe.KindFlour, PreferUnitCategory: recipe.UnitCategoryWeight, Value: recipe.UnitGrams.Tuple(1200)},
+				{Name: "Levain", Kind: recipe.KindSourdough, PreferUnitCategory: recipe.UnitCategoryWeight, Value: recipe.UnitGrams.Tuple(100)},
+				{Name: "Egg", Kind: recipe.KindEgg, PreferUnitCategory: recipe.UnitCategoryCount, Value: recipe.UnitGrams.Tuple(100)},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FromStages("Rye: Light", "Bake hot.", stages)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	want := `---
+title: 'Rye: Light'
+---
+
+> Bake hot.
+
+= Levain
+
+Mix @Rye Flour{50%g} and @Water{50%g}.
+
+= Final Dough
+
+Mix @Bread Flour{1.2%kg}, @Levain{100%g} and @Egg{2}.
+`
+	if got := buf.String(); got != want {
+		t.Errorf("Write() got =\n%s\nwant =\n%s", got, want)
+	}
+
+	// What is written is parsed back to the same ingredients.
+	r, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := r.Title(); got != "Rye: Light" {
+		t.Errorf("Title() got = %q", got)
+	}
+	if got := r.Sections[1].Steps[0].Ingredients[2]; got != (Ingredient{Name: "Egg", Quantity: "2"}) {
+		t.Errorf("Parse() egg = %+v", got)
+	}
+}
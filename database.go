@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"database/sql"
-	_ "embed"
 	"os"
 	"path/filepath"
 
@@ -12,35 +11,75 @@ import (
 	"github.com/simonklee/sourdough/query"
 )
 
-//go:embed schema.sql
-var ddl string
-
 func defaultDBPath() string {
 	configDir, _ := os.UserConfigDir()
 	return filepath.Join(configDir, "sourdough/sourdough.sqlite")
 }
 
-func InitStore(ctx context.Context, dbpath string) (*query.Queries, error) {
+// OpenStore opens the database at dbpath without applying migrations.
+func OpenStore(ctx context.Context, dbpath string) (*sql.DB, error) {
 	dir := filepath.Dir(dbpath)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return nil, err
 	}
 
-	db, err := sql.Open("sqlite3", "file:"+dbpath+"?cache=shared&mode=rwc")
+	// Enable foreign key constraints on every connection in the pool.
+	db, err := sql.Open("sqlite3", "file:"+dbpath+"?cache=shared&mode=rwc&_foreign_keys=on")
 	if err != nil {
 		return nil, err
 	}
 
-	// Enable foreign key constraints.
-	_, err = db.Exec("PRAGMA foreign_keys = ON")
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// InitDB opens the database at dbpath, brings its schema up to date and
+// makes sure the search index is in place.
+func InitDB(ctx context.Context, dbpath string) (*sql.DB, error) {
+	db, err := OpenStore(ctx, dbpath)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create tables.
-	if _, err := db.ExecContext(ctx, ddl); err != nil {
+	if _, err := Migrate(ctx, db, nil); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err := ensureSearchIndex(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// InitStore opens the database at dbpath with InitDB.
+func InitStore(ctx context.Context, dbpath string) (*query.Queries, error) {
+	db, err := InitDB(ctx, dbpath)
+	if err != nil {
 		return nil, err
 	}
 
 	return query.New(db), nil
 }
+
+// InTx calls fn with queries in a transaction on db. The transaction is
+// committed if fn returns nil and rolled back otherwise.
+func InTx(ctx context.Context, db *sql.DB, fn func(q *query.Queries) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(query.New(db).WithTx(tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
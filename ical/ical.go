@@ -0,0 +1,305 @@
+// Package ical writes bake schedules as iCalendar files, RFC 5545, to import
+// into a calendar app.
+//
+// A schedule is a calendar with an event for each step of the method of a
+// recipe and a reminder before the step starts:
+//
+//	cal, err := ical.Schedule("Country", steps, ical.Options{
+//		ReadyAt: time.Date(2026, 10, 20, 8, 0, 0, 0, time.Local),
+//		Alarm:   10 * time.Minute,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	return ical.Write(os.Stdout, cal)
+//
+// Only what a schedule needs is supported: events with a summary, a
+// description and display alarms. Times are written in UTC.
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/simonklee/sourdough/recipe"
+)
+
+// ProdID identifies sourdough as the product which created a calendar.
+const ProdID = "-//sourdough//sourdough//EN"
+
+type Calendar struct {
+	Name   string
+	Events []Event
+
+	// Stamp is when the calendar was created, written as the DTSTAMP of
+	// every event. The current time is used if it's zero.
+	Stamp time.Time
+}
+
+// Event is a calendar event. End is zero for an event without a duration,
+// such as a step that takes no time.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	Alarms      []Alarm
+}
+
+// Alarm is a reminder of an event. Trigger is relative to the start of the
+// event, negative before it.
+type Alarm struct {
+	Trigger     time.Duration
+	Description string
+}
+
+// Options of a schedule. Exactly one of ReadyAt and StartAt is set.
+type Options struct {
+	// ReadyAt is when the last step should end, e.g. when the bread
+	// comes out of the oven.
+	ReadyAt time.Time
+
+	// StartAt is when the first step starts.
+	StartAt time.Time
+
+	// Alarm is how long before a step starts to remind of it. A step
+	// with a duration range is also reminded of when its shortest
+	// duration has passed.
+	Alarm time.Duration
+
+	// Stamp is the creation time of the calendar, see Calendar.Stamp.
+	Stamp time.Time
+}
+
+// Schedule returns a calendar of the steps of a recipe, planned back from
+// ReadyAt or forwards from StartAt with recipe.Schedule and
+// recipe.ScheduleFrom.
+//
+// An event starts at the earliest time the step may start. A step whose
+// start is a window, because of a duration range before or after it, has the
+// window in its description. A step with a duration range lasts its longest
+// duration, so the events of a schedule planned back from ReadyAt follow each
+// other and end when the last step should end.
+func Schedule(name string, steps []recipe.Step, opts Options) (Calendar, error) {
+	if len(steps) == 0 {
+		return Calendar{}, errors.New("no steps to schedule")
+	}
+	if opts.ReadyAt.IsZero() == opts.StartAt.IsZero() {
+		return Calendar{}, errors.New("requires either a ready at or a start at time")
+	}
+	if opts.Alarm < 0 {
+		return Calendar{}, fmt.Errorf("invalid alarm %s, must be before the step", opts.Alarm)
+	}
+
+	var (
+		scheduled []recipe.ScheduledStep
+		anchor    time.Time
+	)
+	if !opts.ReadyAt.IsZero() {
+		scheduled = recipe.Schedule(steps, opts.ReadyAt)
+		anchor = opts.ReadyAt
+	} else {
+		scheduled = recipe.ScheduleFrom(steps, opts.StartAt)
+		anchor = opts.StartAt
+	}
+
+	cal := Calendar{
+		Name:   name,
+		Events: make([]Event, 0, len(scheduled)),
+		Stamp:  opts.Stamp,
+	}
+	for i, step := range scheduled {
+		event := Event{
+			UID:         fmt.Sprintf("%s-%s-%d@sourdough", slug(name), anchor.UTC().Format(dateTimeLayout), i+1),
+			Summary:     fmt.Sprintf("%s: %s", name, step.Text),
+			Description: description(step),
+			Start:       step.Start.Earliest,
+			Alarms: []Alarm{{
+				Trigger:     -opts.Alarm,
+				Description: step.Text,
+			}},
+		}
+		if step.Duration.Max > 0 {
+			event.End = event.Start.Add(step.Duration.Max)
+		}
+		if step.Duration.Min != step.Duration.Max {
+			event.Alarms = append(event.Alarms, Alarm{
+				Trigger:     step.Duration.Min,
+				Description: fmt.Sprintf("%s may be done, after %s", step.Text, recipe.FixedDuration(step.Duration.Min).Format()),
+			})
+		}
+		cal.Events = append(cal.Events, event)
+	}
+
+	return cal, nil
+}
+
+// description returns the description of the event of a step, e.g.
+// "Cold retard for 12–16h at 4.0°C.".
+func description(step recipe.ScheduledStep) string {
+	var b strings.Builder
+	b.WriteString(step.Text)
+	if !step.Duration.IsZero() {
+		fmt.Fprintf(&b, " for %s", step.Duration.Format())
+	}
+	if step.Temperature != nil {
+		fmt.Fprintf(&b, " at %s", step.Temperature.Format())
+	}
+	b.WriteString(".")
+
+	if len(step.Ingredients) > 0 {
+		fmt.Fprintf(&b, "\nAdd %s.", strings.Join(step.IngredientNames(), ", "))
+	}
+	if !step.Start.IsFixed() {
+		fmt.Fprintf(&b, "\nStart between %s and %s.", step.Start.Earliest.Format(windowLayout), step.Start.Latest.Format(windowLayout))
+	}
+	return b.String()
+}
+
+const (
+	dateTimeLayout = "20060102T150405Z"
+	windowLayout   = "Mon 02 Jan 15:04"
+)
+
+var slugRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slug returns a name in lower case with runs of other characters than
+// letters and digits replaced by a dash, e.g. "country-loaf".
+func slug(name string) string {
+	return strings.Trim(slugRegexp.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// Write encodes a calendar.
+func Write(w io.Writer, cal Calendar) error {
+	stamp := cal.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	e := encoder{w: bufio.NewWriter(w)}
+	e.line("BEGIN", "VCALENDAR")
+	e.line("VERSION", "2.0")
+	e.line("PRODID", ProdID)
+	e.line("CALSCALE", "GREGORIAN")
+	e.line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		e.line("X-WR-CALNAME", escape(cal.Name))
+	}
+
+	for _, event := range cal.Events {
+		if event.UID == "" {
+			return errors.New("event without a UID")
+		}
+		if !event.End.IsZero() && !event.End.After(event.Start) {
+			return fmt.Errorf("event %s ends before it starts", event.Summary)
+		}
+
+		e.line("BEGIN", "VEVENT")
+		e.line("UID", event.UID)
+		e.line("DTSTAMP", formatTime(stamp))
+		e.line("DTSTART", formatTime(event.Start))
+		if !event.End.IsZero() {
+			e.line("DTEND", formatTime(event.End))
+		}
+		e.line("SUMMARY", escape(event.Summary))
+		if event.Description != "" {
+			e.line("DESCRIPTION", escape(event.Description))
+		}
+		for _, alarm := range event.Alarms {
+			e.line("BEGIN", "VALARM")
+			e.line("ACTION", "DISPLAY")
+			e.line("DESCRIPTION", escape(alarm.Description))
+			e.line("TRIGGER", formatDuration(alarm.Trigger))
+			e.line("END", "VALARM")
+		}
+		e.line("END", "VEVENT")
+	}
+
+	e.line("END", "VCALENDAR")
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// encoder writes content lines, folded to 75 octets and ended by CRLF. The
+// first error is kept and later writes are skipped.
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+const maxLineLength = 75
+
+func (e *encoder) line(name, value string) {
+	if e.err != nil {
+		return
+	}
+
+	line := name + ":" + value
+	for len(line) > maxLineLength {
+		// Fold at a rune boundary. Continuation lines start with a
+		// space, which counts towards their length.
+		n := maxLineLength
+		for n > 0 && !utf8.RuneStart(line[n]) {
+			n--
+		}
+		if _, e.err = e.w.WriteString(line[:n] + "\r\n"); e.err != nil {
+			return
+		}
+		line = " " + line[n:]
+	}
+	_, e.err = e.w.WriteString(line + "\r\n")
+}
+
+// escape escapes a TEXT value.
+func escape(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// formatDuration formats a duration value, e.g. "-PT10M" or "PT12H".
+func formatDuration(d time.Duration) string {
+	var b strings.Builder
+	if d < 0 {
+		b.WriteString("-")
+		d = -d
+	}
+	b.WriteString("P")
+
+	if days := d / (24 * time.Hour); days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+		if d -= days * 24 * time.Hour; d == 0 {
+			return b.String()
+		}
+	}
+
+	b.WriteString("T")
+	hours, minutes, seconds := d/time.Hour, (d%time.Hour)/time.Minute, (d%time.Minute)/time.Second
+	if hours > 0 {
+		fmt.Fprintf(&b, "%dH", hours)
+	}
+	if minutes > 0 {
+		fmt.Fprintf(&b, "%dM", minutes)
+	}
+	if seconds > 0 || (hours == 0 && minutes == 0) {
+		fmt.Fprintf(&b, "%dS", seconds)
+	}
+	return b.String()
+}
@@ -0,0 +1,164 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/simonklee/sourdough/recipe"
+)
+
+var country = []recipe.Step{
+	{Text: "Autolyse", Duration: recipe.FixedDuration(30 * time.Minute), Ingredients: []recipe.StepIngredient{{Name: "Bread Flour"}, {Name: "Water"}}},
+	{Text: "Bulk ferment", Duration: recipe.FixedDuration(5 * time.Hour), Temperature: &recipe.Temperature{Value: 24, Scale: recipe.Celsius}},
+	{Text: "Shape"},
+	{Text: "Cold retard", Duration: recipe.DurationRange{Min: 12 * time.Hour, Max: 16 * time.Hour}},
+	{Text: "Bake", Duration: recipe.FixedDuration(40 * time.Minute)},
+}
+
+func TestSchedule(t *testing.T) {
+	readyAt := time.Date(2026, 10, 20, 8, 0, 0, 0, time.UTC)
+	cal, err := Schedule("Country Loaf", country, Options{ReadyAt: readyAt, Alarm: 10 * time.Minute})
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC)
+	}
+	want := []struct {
+		start, end time.Time
+		alarms     int
+	}{
+		{at(19, 9, 50), at(19, 10, 20), 1},
+		{at(19, 10, 20), at(19, 15, 20), 1},
+		{at(19, 15, 20), time.Time{}, 1},
+		{at(19, 15, 20), at(20, 7, 20), 2},
+		{at(20, 7, 20), at(20, 8, 0), 1},
+	}
+	if len(cal.Events) != len(want) {
+		t.Fatalf("Schedule() got %d events, want %d", len(cal.Events), len(want))
+	}
+	for i, w := range want {
+		event := cal.Events[i]
+		if !event.Start.Equal(w.start) || !event.End.Equal(w.end) {
+			t.Errorf("event %s got %v–%v, want %v–%v", event.Summary, event.Start, event.End, w.start, w.end)
+		}
+		if len(event.Alarms) != w.alarms {
+			t.Errorf("event %s got %d alarms, want %d", event.Summary, len(event.Alarms), w.alarms)
+		}
+		if event.Alarms[0].Trigger != -10*time.Minute {
+			t.Errorf("event %s alarm got %v, want -10m", event.Summary, event.Alarms[0].Trigger)
+		}
+	}
+
+	if got := cal.Events[0].UID; got != "country-loaf-20261020T080000Z-1@sourdough" {
+		t.Errorf("UID got %s", got)
+	}
+	if got := cal.Events[3].Alarms[1].Trigger; got != 12*time.Hour {
+		t.Errorf("Cold retard done alarm got %v, want 12h", got)
+	}
+	if got := cal.Events[0].Description; got != "Autolyse for 30min.\nAdd Bread Flour, Water.\nStart between Mon 19 Oct 09:50 and Mon 19 Oct 13:50." {
+		t.Errorf("description got %q", got)
+	}
+
+	from, err := Schedule("Country Loaf", country, Options{StartAt: at(19, 9, 0)})
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if last := from.Events[len(from.Events)-1]; !last.Start.Equal(at(20, 2, 30)) {
+		t.Errorf("Bake from start got %v, want %v", last.Start, at(20, 2, 30))
+	}
+
+	for _, opts := range []Options{{}, {ReadyAt: readyAt, StartAt: readyAt}, {ReadyAt: readyAt, Alarm: -time.Minute}} {
+		if _, err := Schedule("Country", country, opts); err == nil {
+			t.Errorf("Schedule(%+v) expected an error", opts)
+		}
+	}
+	if _, err := Schedule("Country", nil, Options{ReadyAt: readyAt}); err == nil {
+		t.Error("Schedule() without steps expected an error")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	start := time.Date(2026, 10, 19, 15, 20, 0, 0, time.FixedZone("CEST", 2*60*60))
+	cal := Calendar{
+		Name:  "Country",
+		Stamp: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+		Events: []Event{{
+			UID:         "country-1@sourdough",
+			Summary:     "Country: Cold retard; covered, in the fridge",
+			Description: "Cold retard for 12–16h at 4.0°C.\nStart between Mon 19 Oct 15:20 and Mon 19 Oct 19:20.",
+			Start:       start,
+			End:         start.Add(16 * time.Hour),
+			Alarms: []Alarm{
+				{Trigger: -10 * time.Minute, Description: "Cold retard"},
+				{Trigger: 12 * time.Hour, Description: "Cold retard may be done"},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, cal); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//sourdough//sourdough//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Country",
+		"BEGIN:VEVENT",
+		"UID:country-1@sourdough",
+		"DTSTAMP:20261018T120000Z",
+		"DTSTART:20261019T132000Z",
+		"DTEND:20261020T052000Z",
+		`SUMMARY:Country: Cold retard\; covered\, in the fridge`,
+		`DESCRIPTION:Cold retard for 12–16h at 4.0°C.\nStart between Mon 19 Oct 1`,
+		` 5:20 and Mon 19 Oct 19:20.`,
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:Cold retard",
+		"TRIGGER:-PT10M",
+		"END:VALARM",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:Cold retard may be done",
+		"TRIGGER:PT12H",
+		"END:VALARM",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if got := buf.String(); got != want {
+		t.Errorf("Write() got\n%s\nwant\n%s", got, want)
+	}
+
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		if len(line) > maxLineLength {
+			t.Errorf("line longer than %d octets: %q", maxLineLength, line)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "PT0S"},
+		{-10 * time.Minute, "-PT10M"},
+		{12 * time.Hour, "PT12H"},
+		{90 * time.Minute, "PT1H30M"},
+		{48 * time.Hour, "P2D"},
+		{26*time.Hour + 30*time.Second, "P1DT2H30S"},
+	}
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%v) got = %s, want %s", tt.d, got, tt.want)
+		}
+	}
+}
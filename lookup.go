@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/simonklee/sourdough/query"
+	"github.com/simonklee/sourdough/recipe"
+)
+
+// AmbiguousRecipeError is returned when a recipe name matches more than one
+// recipe.
+type AmbiguousRecipeError struct {
+	Query   string
+	Recipes []query.Recipe
+}
+
+func (e *AmbiguousRecipeError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "recipe %q is ambiguous, did you mean:", e.Query)
+	for _, r := range e.Recipes {
+		fmt.Fprintf(&b, "\n  %d %s", r.ID, r.Name)
+	}
+	return b.String()
+}
+
+// findRecipe returns the recipe with the given ID or name. Names are matched
+// exactly, by prefix or fuzzily, see recipe.MatchNames. It returns an
+// AmbiguousRecipeError if the name matches several recipes. A numeric value
+// is always an ID.
+func findRecipe(ctx context.Context, db *query.Queries, value string) (query.Recipe, error) {
+	if id, err := strconv.ParseInt(value, 10, 64); err == nil {
+		r, err := db.GetRecipe(ctx, id)
+		if errors.Is(err, query.ErrNotFound) {
+			return r, fmt.Errorf("recipe %d not found", id)
+		} else if err != nil {
+			return r, fmt.Errorf("failed to get recipe %d: %w", id, err)
+		}
+		return r, nil
+	}
+
+	recipes, err := db.ListRecipes(ctx)
+	if err != nil {
+		return query.Recipe{}, fmt.Errorf("failed to list recipes: %w", err)
+	}
+
+	names := make([]string, len(recipes))
+	for i, r := range recipes {
+		names[i] = r.Name
+	}
+
+	matches := recipe.MatchNames(value, names)
+	switch len(matches) {
+	case 0:
+		return query.Recipe{}, fmt.Errorf("recipe %s not found", value)
+	case 1:
+		return recipes[matches[0]], nil
+	default:
+		ambiguous := &AmbiguousRecipeError{Query: value}
+		for _, i := range matches {
+			ambiguous.Recipes = append(ambiguous.Recipes, recipes[i])
+		}
+		return query.Recipe{}, ambiguous
+	}
+}
+
+// findRecipeIngredient returns the recipe ingredient with the given ID, as
+// shown in the # column of view.
+func findRecipeIngredient(ctx context.Context, db *query.Queries, value string) (query.RecipeIngredient, error) {
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return query.RecipeIngredient{}, fmt.Errorf("invalid recipe ingredient ID: %w", err)
+	}
+
+	ri, err := db.GetRecipeIngredient(ctx, id)
+	if errors.Is(err, query.ErrNotFound) {
+		return ri, fmt.Errorf("recipe ingredient %d not found", id)
+	} else if err != nil {
+		return ri, fmt.Errorf("failed to get recipe ingredient %d: %w", id, err)
+	}
+
+	return ri, nil
+}
+
+// findIngredient returns the catalog ingredient with the given ID or name.
+func findIngredient(ctx context.Context, db *query.Queries, value string) (query.Ingredient, error) {
+	var (
+		ingredient query.Ingredient
+		err        error
+	)
+	if id, perr := strconv.ParseInt(value, 10, 64); perr == nil {
+		ingredient, err = db.GetIngredient(ctx, id)
+	} else {
+		ingredient, err = db.GetIngredientByName(ctx, value)
+	}
+
+	if errors.Is(err, query.ErrNotFound) {
+		return ingredient, fmt.Errorf("ingredient %s not found", value)
+	} else if err != nil {
+		return ingredient, fmt.Errorf("failed to get ingredient %s: %w", value, err)
+	}
+
+	return ingredient, nil
+}
+
+// findStep returns the step with the given ID, as shown in the # column of
+// the method of view.
+func findStep(ctx context.Context, db *query.Queries, value string) (query.Step, error) {
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return query.Step{}, fmt.Errorf("invalid step ID: %w", err)
+	}
+
+	step, err := db.GetStep(ctx, id)
+	if errors.Is(err, query.ErrNotFound) {
+		return step, fmt.Errorf("step %d not found", id)
+	} else if err != nil {
+		return step, fmt.Errorf("failed to get step %d: %w", id, err)
+	}
+
+	return step, nil
+}
+
+// findBake returns the bake with the given ID, as shown by bake list, or the
+// latest unfinished bake if value is empty.
+func findBake(ctx context.Context, db *query.Queries, value string) (query.Bake, error) {
+	if value == "" {
+		bake, err := db.GetCurrentBake(ctx)
+		if errors.Is(err, query.ErrNotFound) {
+			return bake, fmt.Errorf("no bake in progress, start one with %s bake start", CmdLabel)
+		} else if err != nil {
+			return bake, fmt.Errorf("failed to get the current bake: %w", err)
+		}
+		return bake, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return query.Bake{}, fmt.Errorf("invalid bake ID: %w", err)
+	}
+
+	bake, err := db.GetBake(ctx, id)
+	if errors.Is(err, query.ErrNotFound) {
+		return bake, fmt.Errorf("bake %d not found", id)
+	} else if err != nil {
+		return bake, fmt.Errorf("failed to get bake %d: %w", id, err)
+	}
+
+	return bake, nil
+}
+
+// findStepIngredients returns the IDs of the recipe ingredients added in a
+// step, each given by its ID or name. A name used by several ingredients of
+// the recipe, e.g. the water of a levain and of the final dough, must be
+// given by ID instead.
+func findStepIngredients(ctx context.Context, db *query.Queries, recipeID int64, values []string) ([]int64, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	rows, err := db.ListRecipeIngredients(ctx, recipeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recipe ingredients: %w", err)
+	}
+
+	ids := make([]int64, 0, len(values))
+	for _, value := range values {
+		var matches []query.ListRecipeIngredientsRow
+		id, perr := strconv.ParseInt(value, 10, 64)
+		for _, row := range rows {
+			if (perr == nil && row.ID == id) || strings.EqualFold(row.Name, value) {
+				matches = append(matches, row)
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("recipe has no ingredient %s", value)
+		case 1:
+			ids = append(ids, matches[0].ID)
+		default:
+			var b strings.Builder
+			fmt.Fprintf(&b, "ingredient %s is ambiguous, use the ID of one of:", value)
+			for _, row := range matches {
+				stage := "final dough"
+				if row.Stage != nil {
+					stage = *row.Stage
+				}
+				fmt.Fprintf(&b, "\n  %d %s (%s)", row.ID, row.Name, stage)
+			}
+			return nil, errors.New(b.String())
+		}
+	}
+	return ids, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 
 	"github.com/peterbourgon/ff/v4"
 	"github.com/peterbourgon/ff/v4/ffhelp"
@@ -37,8 +38,23 @@ type CmdExec func(context.Context, []string) error
 func cli(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) (err error) {
 	root := NewRootCmd(stdin, stdout, stderr)
 	_ = NewListCmd(root)
+	_ = NewSearchCmd(root)
 	_ = NewAddCmd(root)
 	_ = NewViewCmd(root)
+	_ = NewScheduleCmd(root)
+	_ = NewEditCmd(root)
+	_ = NewRmCmd(root)
+	_ = NewRenameCmd(root)
+	_ = NewMoveCmd(root)
+	_ = NewExportCmd(root)
+	_ = NewImportCmd(root)
+	_ = NewLintCmd(root)
+	_ = NewDDTCmd(root)
+	_ = NewFermentCmd(root)
+	_ = NewBakeCmd(root)
+	_ = NewIngredientCmd(root)
+	_ = NewDBCmd(root)
+	_ = NewSchemaCmd(root)
 
 	defer func() {
 		if errors.Is(err, ff.ErrHelp) {
@@ -46,13 +62,61 @@ func cli(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.
 		}
 	}()
 
-	if err = root.Command.Parse(args); err != nil {
+	if err = root.Command.Parse(args, ff.WithEnvVarPrefix(EnvVarPrefix)); err != nil {
 		return fmt.Errorf("parse: %w", err)
 	}
 
-	if err = root.Command.Run(ctx); err != nil {
+	selected := root.Command.GetSelected()
+	cmdArgs, err := parseTrailingFlags(selected)
+	if err != nil {
+		return fmt.Errorf("parse: %w", err)
+	}
+
+	if err = root.LoadConfig(); err != nil {
+		return fmt.Errorf("parse: %w", err)
+	}
+
+	if selected.Exec == nil {
+		return fmt.Errorf("exec: %s: %w", selected.Name, ff.ErrNoExec)
+	}
+
+	if err = selected.Exec(ctx, cmdArgs); err != nil {
 		return fmt.Errorf("exec: %w", err)
 	}
 
 	return nil
 }
+
+// parseTrailingFlags returns the arguments of the selected command. Parsing
+// stops at the first argument, so flags following it, as in "view 1 --json",
+// are parsed here into the flags of the command and its parents. Everything
+// after "--" is an argument.
+func parseTrailingFlags(cmd *ff.Command) ([]string, error) {
+	args := cmd.Flags.GetArgs()
+	fs, ok := cmd.Flags.(*ff.FlagSet)
+	if !ok {
+		return args, nil
+	}
+
+	var rest []string
+	if i := slices.Index(args, "--"); i >= 0 {
+		args, rest = args[:i], args[i+1:]
+	}
+
+	var out []string
+	for len(args) > 0 {
+		if arg := args[0]; len(arg) < 2 || arg[0] != '-' {
+			out = append(out, arg)
+			args = args[1:]
+			continue
+		}
+
+		trailing := ff.NewFlagSet(cmd.Name).SetParent(fs)
+		if err := trailing.Parse(args); err != nil {
+			return nil, fmt.Errorf("%s: %w", cmd.Name, err)
+		}
+		args = trailing.GetArgs()
+	}
+
+	return append(out, rest...), nil
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// Migration is a single schema change. Migrations are embedded from
+// migrations/NNNN_name.sql and applied in version order. The version of the
+// last applied migration is stored in PRAGMA user_version.
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// loadMigrations returns the embedded migrations sorted by version.
+func loadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".sql")
+		prefix, label, ok := strings.Cut(name, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version: %s: %w", entry.Name(), err)
+		}
+
+		data, err := migrationFS.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    label,
+			SQL:     string(data),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration %04d_%s is out of sequence, expected version %d", m.Version, m.Name, i+1)
+		}
+	}
+
+	return migrations, nil
+}
+
+// SchemaVersion returns the version of the last migration applied to db.
+func SchemaVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var version int
+	err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version)
+	return version, err
+}
+
+// MigrationStatus is a migration and whether it has been applied.
+type MigrationStatus struct {
+	Migration
+	Applied bool
+}
+
+// Migrations returns all known migrations and whether they have been applied
+// to db.
+func Migrations(ctx context.Context, db *sql.DB) ([]MigrationStatus, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := SchemaVersion(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status = append(status, MigrationStatus{
+			Migration: m,
+			Applied:   m.Version <= version,
+		})
+	}
+
+	return status, nil
+}
+
+// Migrate applies all pending migrations to db and returns them. Each
+// migration runs in its own transaction together with the version bump, so a
+// failing migration leaves the database at the previous version. If applied
+// is non-nil it's called after each migration is committed.
+func Migrate(ctx context.Context, db *sql.DB, applied func(Migration)) ([]Migration, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := SchemaVersion(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	if version > len(migrations) {
+		return nil, fmt.Errorf("database schema version %d is newer than this binary supports (%d)", version, len(migrations))
+	}
+
+	// The search index triggers reference the tables being migrated and
+	// would break table rebuilds. They are dropped here and recreated,
+	// along with the index, by ensureSearchIndex.
+	if version < len(migrations) {
+		if err := dropSearchTriggers(ctx, db); err != nil {
+			return nil, err
+		}
+	}
+
+	var done []Migration
+	for _, m := range migrations[version:] {
+		if err := applyMigration(ctx, db, m); err != nil {
+			return done, fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+
+		done = append(done, m)
+		if applied != nil {
+			applied(m)
+		}
+	}
+
+	return done, nil
+}
+
+// applyMigration runs a single migration. Foreign key enforcement is turned
+// off while the migration runs so tables can be rebuilt, and the constraints
+// are verified before the transaction is committed.
+func applyMigration(ctx context.Context, db *sql.DB, m Migration) (err error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return err
+	}
+	defer func() {
+		_, fkErr := conn.ExecContext(context.WithoutCancel(ctx), "PRAGMA foreign_keys = ON")
+		err = errors.Join(err, fkErr)
+	}()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return err
+	}
+	violation := rows.Next()
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if violation {
+		return errors.New("foreign key constraint violated")
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", m.Version)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
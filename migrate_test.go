@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/simonklee/sourdough/query"
+)
+
+func TestMigrateBaseline(t *testing.T) {
+	ctx := context.Background()
+	db, err := OpenStore(ctx, filepath.Join(t.TempDir(), "sourdough.sqlite"))
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	defer db.Close()
+
+	// A database created before migrations, with the schema of the first
+	// release and no user_version.
+	schema, err := os.ReadFile("testdata/baseline_schema.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, string(schema)); err != nil {
+		t.Fatalf("baseline schema error = %v", err)
+	}
+	_, err = db.ExecContext(ctx, `
+INSERT INTO recipes (id, name) VALUES (1, 'Country');
+INSERT INTO ingredients (id, name, kind) VALUES (1, 'Bread Flour', 'flour'), (2, 'Water', 'water'), (3, 'Salt', NULL);
+INSERT INTO recipe_ingredients (id, recipe_id, ingredient_id, prefer_unit_category, percentage, dependency) VALUES
+  (1, 1, 1, 'weight', 1, 'total_flour'),
+  (2, 1, 2, 'weight', 0.7, 'total_flour'),
+  (3, 1, 3, 'teaspoon', 0.02, 'total_flour');
+`)
+	if err != nil {
+		t.Fatalf("baseline rows error = %v", err)
+	}
+
+	applied, err := Migrate(ctx, db, nil)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(migrations) {
+		t.Errorf("Migrate() applied %d migrations, want %d", len(applied), len(migrations))
+	}
+
+	version, err := SchemaVersion(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != len(migrations) {
+		t.Errorf("SchemaVersion() got = %d, want %d", version, len(migrations))
+	}
+
+	rows, err := db.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows.Next() {
+		t.Error("PRAGMA foreign_key_check found violations")
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	q := query.New(db)
+	r, err := q.GetRecipe(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetRecipe() error = %v", err)
+	}
+	if r.Name != "Country" {
+		t.Errorf("GetRecipe() got name = %s, want Country", r.Name)
+	}
+
+	ingredients, err := q.ListRecipeIngredients(ctx, 1)
+	if err != nil {
+		t.Fatalf("ListRecipeIngredients() error = %v", err)
+	}
+	want := []struct {
+		name       string
+		unit       string
+		percentage float64
+	}{
+		{"Bread Flour", "weight", 1},
+		{"Water", "weight", 0.7},
+		{"Salt", "teaspoon", 0.02},
+	}
+	if len(ingredients) != len(want) {
+		t.Fatalf("ListRecipeIngredients() got %d ingredients, want %d", len(ingredients), len(want))
+	}
+	for i, w := range want {
+		got := ingredients[i]
+		if got.Name != w.name || string(got.PreferUnitCategory) != w.unit || got.Percentage != w.percentage || got.Dependency != "total_flour" {
+			t.Errorf("ListRecipeIngredients()[%d] got = %+v, want %+v", i, got, w)
+		}
+	}
+
+	// Migrating again is a no-op.
+	applied, err = Migrate(ctx, db, nil)
+	if err != nil {
+		t.Fatalf("Migrate() again error = %v", err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("Migrate() again applied %d migrations, want 0", len(applied))
+	}
+}
@@ -0,0 +1,49 @@
+/* Relax the percentage CHECK and add fixed-amount ingredients. SQLite cannot */
+/* alter CHECK constraints in place, so the table is rebuilt. */
+CREATE TABLE recipe_ingredients_new (
+  id INTEGER NOT NULL PRIMARY KEY,
+  recipe_id INTEGER NOT NULL,
+  ingredient_id INTEGER NOT NULL,
+  prefer_unit_category TEXT CHECK (prefer_unit_category IN ('weight', 'volume', 'count', 'teaspoon')) NOT NULL,
+  percentage REAL NOT NULL CHECK (percentage >= 0),
+  dependency TEXT NOT NULL,
+  mode TEXT NOT NULL DEFAULT 'percentage' CHECK (mode IN ('percentage', 'amount')),
+  amount REAL NULL CHECK (amount > 0),
+  unit TEXT NULL,
+  CHECK ((
+    mode = 'percentage' AND percentage > 0 AND dependency IN ('total_flour')
+  ) OR (
+    mode = 'amount' AND NOT amount IS NULL AND NOT unit IS NULL
+  )),
+  FOREIGN KEY (recipe_id) REFERENCES recipes (
+    id
+  ) ON DELETE CASCADE,
+  FOREIGN KEY (ingredient_id) REFERENCES ingredients (
+    id
+  ) ON DELETE CASCADE
+);
+
+INSERT INTO recipe_ingredients_new (
+  id,
+  recipe_id,
+  ingredient_id,
+  prefer_unit_category,
+  percentage,
+  dependency
+)
+SELECT
+  id,
+  recipe_id,
+  ingredient_id,
+  prefer_unit_category,
+  percentage,
+  dependency
+FROM recipe_ingredients;
+
+DROP TABLE recipe_ingredients;
+
+ALTER TABLE recipe_ingredients_new RENAME TO recipe_ingredients;
+
+CREATE INDEX idx_recipe_ingredients_recipe_id ON recipe_ingredients (recipe_id);
+
+CREATE INDEX idx_recipe_ingredients_ingredient_id ON recipe_ingredients (ingredient_id);
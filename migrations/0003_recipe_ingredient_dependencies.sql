@@ -0,0 +1,45 @@
+/* Allow dependencies on total_dough, total_water, per_piece and other */
+/* ingredients of the recipe, not only total_flour. */
+CREATE TABLE recipe_ingredients_new (
+  id INTEGER NOT NULL PRIMARY KEY,
+  recipe_id INTEGER NOT NULL,
+  ingredient_id INTEGER NOT NULL,
+  prefer_unit_category TEXT CHECK (prefer_unit_category IN ('weight', 'volume', 'count', 'teaspoon')) NOT NULL,
+  percentage REAL NOT NULL CHECK (percentage >= 0),
+  dependency TEXT NOT NULL,
+  mode TEXT NOT NULL DEFAULT 'percentage' CHECK (mode IN ('percentage', 'amount')),
+  amount REAL NULL CHECK (amount > 0),
+  unit TEXT NULL,
+  CHECK ((
+    mode = 'percentage' AND percentage > 0 AND LENGTH(dependency) > 0
+  ) OR (
+    mode = 'amount' AND NOT amount IS NULL AND NOT unit IS NULL
+  )),
+  FOREIGN KEY (recipe_id) REFERENCES recipes (
+    id
+  ) ON DELETE CASCADE,
+  FOREIGN KEY (ingredient_id) REFERENCES ingredients (
+    id
+  ) ON DELETE CASCADE
+);
+
+INSERT INTO recipe_ingredients_new
+SELECT
+  id,
+  recipe_id,
+  ingredient_id,
+  prefer_unit_category,
+  percentage,
+  dependency,
+  mode,
+  amount,
+  unit
+FROM recipe_ingredients;
+
+DROP TABLE recipe_ingredients;
+
+ALTER TABLE recipe_ingredients_new RENAME TO recipe_ingredients;
+
+CREATE INDEX idx_recipe_ingredients_recipe_id ON recipe_ingredients (recipe_id);
+
+CREATE INDEX idx_recipe_ingredients_ingredient_id ON recipe_ingredients (ingredient_id);
@@ -0,0 +1,18 @@
+/* Stages of a multi-stage formula, e.g. a levain build or a soaker. Recipe */
+/* ingredients without a stage belong to the final dough. */
+CREATE TABLE stages (
+  id INTEGER NOT NULL PRIMARY KEY,
+  recipe_id INTEGER NOT NULL,
+  name TEXT CHECK (LENGTH(name) > 0) NOT NULL,
+  position INTEGER NOT NULL,
+  UNIQUE (recipe_id, name),
+  FOREIGN KEY (recipe_id) REFERENCES recipes (
+    id
+  ) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_stages_recipe_id ON stages (recipe_id);
+
+ALTER TABLE recipe_ingredients ADD COLUMN stage_id INTEGER NULL REFERENCES stages (id) ON DELETE CASCADE;
+
+CREATE INDEX idx_recipe_ingredients_stage_id ON recipe_ingredients (stage_id);
@@ -0,0 +1,3 @@
+/* Hydration of sourdough starters and other preferments as a ratio of water */
+/* to flour, e.g. 1 for a 100% hydration starter. */
+ALTER TABLE ingredients ADD COLUMN hydration REAL NULL CHECK (hydration >= 0);
@@ -0,0 +1,5 @@
+/* Density in g/ml and the weight of a single piece in grams, e.g. 50 for an */
+/* egg. Both override the defaults of the ingredient's kind. */
+ALTER TABLE ingredients ADD COLUMN density REAL NULL CHECK (density > 0);
+
+ALTER TABLE ingredients ADD COLUMN piece_weight REAL NULL CHECK (piece_weight > 0);
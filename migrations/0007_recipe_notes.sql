@@ -0,0 +1,3 @@
+/* Free-form notes of a recipe, e.g. where it came from or what to try next */
+/* time. Notes are included in the search index. */
+ALTER TABLE recipes ADD COLUMN notes TEXT NOT NULL DEFAULT '';
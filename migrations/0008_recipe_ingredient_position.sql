@@ -0,0 +1,15 @@
+/* Position of a recipe ingredient within its recipe, so the order of the */
+/* ingredients can be changed, and an optional group such as "Topping" or */
+/* "Filling" shown with a subtotal. Existing ingredients keep the order of */
+/* their IDs. */
+ALTER TABLE recipe_ingredients ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE recipe_ingredients ADD COLUMN group_name TEXT NULL CHECK (LENGTH(group_name) > 0);
+
+UPDATE recipe_ingredients SET position = (
+  SELECT
+    COUNT(*)
+  FROM recipe_ingredients AS o
+  WHERE
+    o.recipe_id = recipe_ingredients.recipe_id AND o.id <= recipe_ingredients.id
+);
@@ -0,0 +1,33 @@
+/* Steps of the method of a recipe, e.g. "Bulk ferment" for 4-5h at 24C. */
+/* Durations are in minutes, duration_min equal to duration_max for a fixed */
+/* duration and both null for a step without a duration. */
+CREATE TABLE steps (
+  id INTEGER NOT NULL PRIMARY KEY,
+  recipe_id INTEGER NOT NULL,
+  position INTEGER NOT NULL,
+  text TEXT CHECK (LENGTH(text) > 0) NOT NULL,
+  duration_min INTEGER NULL CHECK (duration_min >= 0),
+  duration_max INTEGER NULL CHECK (duration_max >= duration_min),
+  temperature REAL NULL,
+  temperature_scale TEXT NOT NULL DEFAULT 'C' CHECK (temperature_scale IN ('C', 'F')),
+  FOREIGN KEY (recipe_id) REFERENCES recipes (
+    id
+  ) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_steps_recipe_id ON steps (recipe_id);
+
+/* The ingredients of a recipe added in a step. */
+CREATE TABLE step_ingredients (
+  step_id INTEGER NOT NULL,
+  recipe_ingredient_id INTEGER NOT NULL,
+  PRIMARY KEY (step_id, recipe_ingredient_id),
+  FOREIGN KEY (step_id) REFERENCES steps (
+    id
+  ) ON DELETE CASCADE,
+  FOREIGN KEY (recipe_ingredient_id) REFERENCES recipe_ingredients (
+    id
+  ) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_step_ingredients_recipe_ingredient_id ON step_ingredients (recipe_ingredient_id);
@@ -0,0 +1,17 @@
+/* Observed fermentation times, calibrating the estimates of */
+/* ferment-estimate. The starter is a ratio of the flour and the recipe is */
+/* null if the observation isn't of a recipe or the recipe was removed. */
+CREATE TABLE ferment_logs (
+  id INTEGER NOT NULL PRIMARY KEY,
+  recipe_id INTEGER NULL,
+  stage TEXT NOT NULL CHECK (stage IN ('bulk', 'proof')),
+  temperature REAL NOT NULL,
+  temperature_scale TEXT NOT NULL DEFAULT 'C' CHECK (temperature_scale IN ('C', 'F')),
+  starter REAL NOT NULL CHECK (starter > 0),
+  flour TEXT NOT NULL DEFAULT 'white' CHECK (flour IN ('white', 'whole-wheat', 'rye')),
+  minutes INTEGER NOT NULL CHECK (minutes > 0),
+  logged_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (recipe_id) REFERENCES recipes (
+    id
+  ) ON DELETE SET NULL
+);
@@ -0,0 +1,53 @@
+/* The bake journal. A bake keeps the name and version of its recipe, a */
+/* hash of the ingredients and steps, so the journal survives changes to */
+/* the recipe and its removal. The dependency is as given to view, e.g. */
+/* "total_flour 1000g", and null for a bake of the template. */
+CREATE TABLE bakes (
+  id INTEGER NOT NULL PRIMARY KEY,
+  recipe_id INTEGER NULL,
+  recipe_name TEXT NOT NULL,
+  recipe_version TEXT NOT NULL,
+  dependency TEXT NULL,
+  scale REAL NOT NULL DEFAULT 1 CHECK (scale > 0),
+  notes TEXT NOT NULL DEFAULT '',
+  rating INTEGER NULL CHECK (rating BETWEEN 1 AND 5),
+  started_at DATETIME NOT NULL,
+  finished_at DATETIME NULL,
+  FOREIGN KEY (recipe_id) REFERENCES recipes (
+    id
+  ) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_bakes_recipe_id ON bakes (recipe_id);
+
+/* What happened during a bake, e.g. the bulk fermentation taking 330 */
+/* minutes at 24C. Durations are in minutes. */
+CREATE TABLE bake_logs (
+  id INTEGER NOT NULL PRIMARY KEY,
+  bake_id INTEGER NOT NULL,
+  text TEXT NOT NULL DEFAULT '',
+  stage TEXT NULL CHECK (stage IN ('bulk', 'proof')),
+  temperature REAL NULL,
+  temperature_scale TEXT NOT NULL DEFAULT 'C' CHECK (temperature_scale IN ('C', 'F')),
+  minutes INTEGER NULL CHECK (minutes > 0),
+  logged_at DATETIME NOT NULL,
+  FOREIGN KEY (bake_id) REFERENCES bakes (
+    id
+  ) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_bake_logs_bake_id ON bake_logs (bake_id);
+
+/* Photos of a bake, copied into the photos directory next to the config */
+/* file. The path is relative to that directory. */
+CREATE TABLE bake_photos (
+  id INTEGER NOT NULL PRIMARY KEY,
+  bake_id INTEGER NOT NULL,
+  path TEXT NOT NULL UNIQUE,
+  added_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (bake_id) REFERENCES bakes (
+    id
+  ) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_bake_photos_bake_id ON bake_photos (bake_id);
@@ -0,0 +1,624 @@
+package main
+
+import (
+	_ "embed"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/simonklee/sourdough/query"
+	"github.com/simonklee/sourdough/recipe"
+)
+
+// The structures below are emitted by --json and --yaml. They are part of
+// the command line interface and described by output.schema.json, so fields
+// may be added but are never renamed or removed. Lists are never null.
+
+//go:embed output.schema.json
+var outputSchema []byte
+
+// AmountOutput is a raw value and its unit, e.g. {"value": 450, "unit": "g"}.
+type AmountOutput struct {
+	Value float64     `json:"value" yaml:"value"`
+	Unit  recipe.Unit `json:"unit" yaml:"unit"`
+}
+
+func amountOutput(t recipe.Tuple) AmountOutput {
+	return AmountOutput{Value: t.Value, Unit: t.Unit}
+}
+
+// gramsOutput returns an amount in grams.
+func gramsOutput(grams float64) *AmountOutput {
+	return &AmountOutput{Value: grams, Unit: recipe.UnitGrams}
+}
+
+type TemperatureOutput struct {
+	Value float64                 `json:"value" yaml:"value"`
+	Scale recipe.TemperatureScale `json:"scale" yaml:"scale"`
+}
+
+func temperatureOutput(t recipe.Temperature) TemperatureOutput {
+	return TemperatureOutput{Value: t.Value, Scale: t.Scale}
+}
+
+type RecipeOutput struct {
+	ID    int64  `json:"id" yaml:"id"`
+	Name  string `json:"name" yaml:"name"`
+	Notes string `json:"notes" yaml:"notes"`
+}
+
+func recipeOutput(r query.Recipe) RecipeOutput {
+	return RecipeOutput{ID: r.ID, Name: r.Name, Notes: r.Notes}
+}
+
+// RecipeListOutput is the output of list.
+type RecipeListOutput struct {
+	Recipes []RecipeOutput `json:"recipes" yaml:"recipes"`
+}
+
+func recipeListOutput(recipes []query.Recipe) RecipeListOutput {
+	out := RecipeListOutput{Recipes: make([]RecipeOutput, 0, len(recipes))}
+	for _, r := range recipes {
+		out.Recipes = append(out.Recipes, recipeOutput(r))
+	}
+	return out
+}
+
+// SearchOutput is the output of search.
+type SearchOutput struct {
+	Query   string               `json:"query" yaml:"query"`
+	Results []SearchResultOutput `json:"results" yaml:"results"`
+}
+
+// SearchResultOutput is a matching recipe. Snippet is the best matching
+// text without highlighting and Matches are the highlighted parts of it.
+type SearchResultOutput struct {
+	ID      int64    `json:"id" yaml:"id"`
+	Name    string   `json:"name" yaml:"name"`
+	Snippet string   `json:"snippet" yaml:"snippet"`
+	Matches []string `json:"matches" yaml:"matches"`
+}
+
+func searchOutput(q string, results []SearchResult) SearchOutput {
+	out := SearchOutput{Query: q, Results: make([]SearchResultOutput, 0, len(results))}
+	for _, r := range results {
+		matches := []string{}
+		for _, part := range strings.Split(r.Snippet, searchMatchStart)[1:] {
+			match, _, _ := strings.Cut(part, searchMatchEnd)
+			matches = append(matches, match)
+		}
+
+		out.Results = append(out.Results, SearchResultOutput{
+			ID:      r.ID,
+			Name:    r.Name,
+			Snippet: strings.NewReplacer(searchMatchStart, "", searchMatchEnd, "").Replace(r.Snippet),
+			Matches: matches,
+		})
+	}
+	return out
+}
+
+// TemplateIngredientOutput is an ingredient of a recipe template. Either
+// Percentage or Amount is set depending on Mode, and a null Stage is the
+// final dough. Position orders the ingredients of a stage and Group is null
+// for an ingredient without a group.
+type TemplateIngredientOutput struct {
+	ID                 int64               `json:"id" yaml:"id"`
+	Name               string              `json:"name" yaml:"name"`
+	Kind               recipe.Kind         `json:"kind" yaml:"kind"`
+	PreferUnitCategory recipe.UnitCategory `json:"prefer_unit_category" yaml:"prefer_unit_category"`
+	Mode               recipe.Mode         `json:"mode" yaml:"mode"`
+	Percentage         *float64            `json:"percentage" yaml:"percentage"`
+	Amount             *AmountOutput       `json:"amount" yaml:"amount"`
+	Dependency         string              `json:"dependency" yaml:"dependency"`
+	Stage              *string             `json:"stage" yaml:"stage"`
+	Position           int64               `json:"position" yaml:"position"`
+	Group              *string             `json:"group" yaml:"group"`
+}
+
+func templateIngredientOutput(row query.ListRecipeIngredientsRow) TemplateIngredientOutput {
+	out := TemplateIngredientOutput{
+		ID:                 row.ID,
+		Name:               row.Name,
+		Kind:               row.Kind,
+		PreferUnitCategory: row.PreferUnitCategory,
+		Mode:               row.Mode,
+		Dependency:         row.Dependency,
+		Stage:              row.Stage,
+		Position:           row.Position,
+		Group:              row.GroupName,
+	}
+	if row.Mode == recipe.ModeAmount && row.Amount != nil {
+		amount := amountOutput(row.Unit.Tuple(*row.Amount))
+		out.Amount = &amount
+	} else {
+		percentage := row.Percentage
+		out.Percentage = &percentage
+	}
+	return out
+}
+
+// PortionOutput is a calculated amount of an ingredient. Amount is the raw
+// result of the calculation and Display the amount as shown in tables, in
+// the preferred unit category and the unit system of --units. Grams and
+// Percentage, the baker's percentage of the flour in the same list, are null
+// if the amount can't be converted to a weight. Group is null for an
+// ingredient without a group.
+type PortionOutput struct {
+	Name               string              `json:"name" yaml:"name"`
+	Kind               recipe.Kind         `json:"kind" yaml:"kind"`
+	PreferUnitCategory recipe.UnitCategory `json:"prefer_unit_category" yaml:"prefer_unit_category"`
+	Amount             AmountOutput        `json:"amount" yaml:"amount"`
+	Display            AmountOutput        `json:"display" yaml:"display"`
+	Grams              *float64            `json:"grams" yaml:"grams"`
+	Percentage         *float64            `json:"percentage" yaml:"percentage"`
+	Group              *string             `json:"group" yaml:"group"`
+}
+
+func portionsOutput(portions []recipe.PortionIngredient, units recipe.UnitSystem) []PortionOutput {
+	totalFlour := recipe.TotalFlour(portions)
+
+	out := make([]PortionOutput, 0, len(portions))
+	for _, portion := range portions {
+		p := PortionOutput{
+			Name:               portion.Name,
+			Kind:               portion.Kind,
+			PreferUnitCategory: portion.PreferUnitCategory,
+			Amount:             amountOutput(portion.Value),
+			Display:            amountOutput(portion.PreferredIn(units)),
+		}
+		if portion.Group != "" {
+			group := portion.Group
+			p.Group = &group
+		}
+		if v, err := portion.Value.ConvertWith(recipe.UnitGrams, portion.Properties()); err == nil {
+			grams := v.Value
+			p.Grams = &grams
+			if totalFlour > 0 {
+				percentage := grams / totalFlour
+				p.Percentage = &percentage
+			}
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+type StageOutput struct {
+	Name        string          `json:"name" yaml:"name"`
+	Ingredients []PortionOutput `json:"ingredients" yaml:"ingredients"`
+}
+
+// SummaryOutput is the baker's math of a recipe. The totals are null if
+// Nominal is true, when the recipe is summarized without dependencies and
+// only the ratios are meaningful.
+type SummaryOutput struct {
+	Nominal           bool          `json:"nominal" yaml:"nominal"`
+	TotalFlour        *AmountOutput `json:"total_flour" yaml:"total_flour"`
+	TotalWater        *AmountOutput `json:"total_water" yaml:"total_water"`
+	TotalDough        *AmountOutput `json:"total_dough" yaml:"total_dough"`
+	Hydration         float64       `json:"hydration" yaml:"hydration"`
+	PrefermentedFlour float64       `json:"prefermented_flour" yaml:"prefermented_flour"`
+	Salt              float64       `json:"salt" yaml:"salt"`
+}
+
+func summaryOutput(summary recipe.Summary, nominal bool) *SummaryOutput {
+	out := &SummaryOutput{
+		Nominal:           nominal,
+		Hydration:         summary.Hydration(),
+		PrefermentedFlour: summary.PrefermentedFlourRatio(),
+		Salt:              summary.SaltRatio(),
+	}
+	if !nominal {
+		out.TotalFlour = gramsOutput(summary.Flour)
+		out.TotalWater = gramsOutput(summary.Water)
+		out.TotalDough = gramsOutput(summary.Dough)
+	}
+	return out
+}
+
+// DoughTemperatureOutput is the desired dough temperature and the water
+// temperature needed to reach it.
+type DoughTemperatureOutput struct {
+	Dough TemperatureOutput `json:"dough" yaml:"dough"`
+	Water TemperatureOutput `json:"water" yaml:"water"`
+}
+
+type LimitOutput struct {
+	Label      string       `json:"label" yaml:"label"`
+	Available  AmountOutput `json:"available" yaml:"available"`
+	TotalFlour AmountOutput `json:"total_flour" yaml:"total_flour"`
+	Limiting   bool         `json:"limiting" yaml:"limiting"`
+}
+
+// ViewOutput is the output of view. Portions are set for a recipe without
+// stages and Stages and Overall for a recipe with stages, when dependencies
+// or limits are given.
+type ViewOutput struct {
+	Recipe      RecipeOutput               `json:"recipe" yaml:"recipe"`
+	Ingredients []TemplateIngredientOutput `json:"ingredients" yaml:"ingredients"`
+	Portions    []PortionOutput            `json:"portions" yaml:"portions"`
+	Stages      []StageOutput              `json:"stages" yaml:"stages"`
+	Overall     []PortionOutput            `json:"overall" yaml:"overall"`
+	Summary     *SummaryOutput             `json:"summary" yaml:"summary"`
+	Temperature *DoughTemperatureOutput    `json:"temperature" yaml:"temperature"`
+	Limits      []LimitOutput              `json:"limits" yaml:"limits"`
+	Steps       []StepOutput               `json:"steps" yaml:"steps"`
+	Ferment     *FermentEstimateOutput     `json:"ferment" yaml:"ferment"`
+	Bakes       []BakeOutput               `json:"bakes" yaml:"bakes"`
+}
+
+// Output returns the structured output of the view.
+func (r RecipeView) Output() ViewOutput {
+	out := ViewOutput{
+		Recipe:      recipeOutput(r.Recipe),
+		Ingredients: make([]TemplateIngredientOutput, 0, len(r.Ingredients)),
+		Portions:    portionsOutput(r.Portions, r.Units),
+		Stages:      make([]StageOutput, 0, len(r.Stages)),
+		Overall:     []PortionOutput{},
+		Limits:      make([]LimitOutput, 0, len(r.Bounds)),
+		Steps:       make([]StepOutput, 0, len(r.Steps)),
+		Bakes:       bakesOutput(r.Bakes),
+	}
+	for _, row := range r.Ingredients {
+		out.Ingredients = append(out.Ingredients, templateIngredientOutput(row))
+	}
+	for _, stage := range r.Stages {
+		out.Stages = append(out.Stages, StageOutput{
+			Name:        stage.Name,
+			Ingredients: portionsOutput(stage.Ingredients, r.Units),
+		})
+	}
+	if len(r.Stages) > 0 {
+		out.Overall = portionsOutput(recipe.Overall(r.Stages), r.Units)
+	}
+	if r.Summary != nil {
+		out.Summary = summaryOutput(*r.Summary, r.Nominal)
+	}
+	if r.Ferment != nil {
+		ferment := r.Ferment.Output()
+		out.Ferment = &ferment
+	}
+	if r.Water != nil {
+		out.Temperature = &DoughTemperatureOutput{
+			Dough: temperatureOutput(*r.Target),
+			Water: temperatureOutput(*r.Water),
+		}
+	}
+	for i, bound := range r.Bounds {
+		out.Limits = append(out.Limits, LimitOutput{
+			Label:      bound.Limit.Label,
+			Available:  amountOutput(bound.Limit.Value),
+			TotalFlour: amountOutput(bound.TotalFlour),
+			Limiting:   i == r.Binding,
+		})
+	}
+	for i, step := range r.Steps {
+		out.Steps = append(out.Steps, stepOutput(step, i+1))
+	}
+	return out
+}
+
+// DurationOutput is the duration of a step in minutes. Min equals Max for a
+// fixed duration.
+type DurationOutput struct {
+	Min int64 `json:"min" yaml:"min"`
+	Max int64 `json:"max" yaml:"max"`
+}
+
+// StepOutput is a step of the method of a recipe. Duration and Temperature
+// are null if the step has none.
+type StepOutput struct {
+	ID          int64              `json:"id" yaml:"id"`
+	Position    int                `json:"position" yaml:"position"`
+	Text        string             `json:"text" yaml:"text"`
+	Duration    *DurationOutput    `json:"duration" yaml:"duration"`
+	Temperature *TemperatureOutput `json:"temperature" yaml:"temperature"`
+	Ingredients []string           `json:"ingredients" yaml:"ingredients"`
+}
+
+func stepOutput(step recipeStep, position int) StepOutput {
+	out := StepOutput{
+		ID:          step.ID,
+		Position:    position,
+		Text:        step.Text,
+		Ingredients: step.IngredientNames(),
+	}
+	if !step.Duration.IsZero() {
+		out.Duration = &DurationOutput{
+			Min: int64(step.Duration.Min / time.Minute),
+			Max: int64(step.Duration.Max / time.Minute),
+		}
+	}
+	if step.Temperature != nil {
+		t := temperatureOutput(*step.Temperature)
+		out.Temperature = &t
+	}
+	return out
+}
+
+// ScheduleOutput is the output of schedule.
+type ScheduleOutput struct {
+	Recipe RecipeOutput          `json:"recipe" yaml:"recipe"`
+	Steps  []ScheduledStepOutput `json:"steps" yaml:"steps"`
+}
+
+// ScheduledStepOutput is a step with the windows of times it starts and
+// ends. Earliest equals Latest if the window is a single time.
+type ScheduledStepOutput struct {
+	StepOutput `yaml:",inline"`
+	Start      TimeRangeOutput `json:"start" yaml:"start"`
+	End        TimeRangeOutput `json:"end" yaml:"end"`
+}
+
+type TimeRangeOutput struct {
+	Earliest time.Time `json:"earliest" yaml:"earliest"`
+	Latest   time.Time `json:"latest" yaml:"latest"`
+}
+
+// Output returns the structured output of the schedule.
+func (v ScheduleView) Output() ScheduleOutput {
+	out := ScheduleOutput{
+		Recipe: recipeOutput(v.Recipe),
+		Steps:  make([]ScheduledStepOutput, 0, len(v.Times)),
+	}
+	for i, step := range v.Times {
+		out.Steps = append(out.Steps, ScheduledStepOutput{
+			StepOutput: stepOutput(v.Steps[i], i+1),
+			Start:      TimeRangeOutput{Earliest: step.Start.Earliest, Latest: step.Start.Latest},
+			End:        TimeRangeOutput{Earliest: step.End.Earliest, Latest: step.End.Latest},
+		})
+	}
+	return out
+}
+
+// IngredientListOutput is the output of ingredient list.
+type IngredientListOutput struct {
+	Ingredients []IngredientOutput `json:"ingredients" yaml:"ingredients"`
+}
+
+// IngredientOutput is an ingredient of the catalog. Density is in g/ml and
+// PieceWeight in grams.
+type IngredientOutput struct {
+	ID          int64       `json:"id" yaml:"id"`
+	Name        string      `json:"name" yaml:"name"`
+	Kind        recipe.Kind `json:"kind" yaml:"kind"`
+	Hydration   *float64    `json:"hydration" yaml:"hydration"`
+	Density     *float64    `json:"density" yaml:"density"`
+	PieceWeight *float64    `json:"piece_weight" yaml:"piece_weight"`
+}
+
+func ingredientListOutput(ingredients []query.Ingredient) IngredientListOutput {
+	out := IngredientListOutput{Ingredients: make([]IngredientOutput, 0, len(ingredients))}
+	for _, ingredient := range ingredients {
+		out.Ingredients = append(out.Ingredients, IngredientOutput{
+			ID:          ingredient.ID,
+			Name:        ingredient.Name,
+			Kind:        ingredient.Kind,
+			Hydration:   ingredient.Hydration,
+			Density:     ingredient.Density,
+			PieceWeight: ingredient.PieceWeight,
+		})
+	}
+	return out
+}
+
+// DDTOutput is the output of ddt. Starter is null if it isn't included.
+type DDTOutput struct {
+	Target   TemperatureOutput  `json:"target" yaml:"target"`
+	Room     TemperatureOutput  `json:"room" yaml:"room"`
+	Flour    TemperatureOutput  `json:"flour" yaml:"flour"`
+	Starter  *TemperatureOutput `json:"starter" yaml:"starter"`
+	Friction TemperatureOutput  `json:"friction" yaml:"friction"`
+	Water    TemperatureOutput  `json:"water" yaml:"water"`
+}
+
+func ddtOutput(ddt recipe.DDT, water recipe.Temperature) DDTOutput {
+	out := DDTOutput{
+		Target:   temperatureOutput(ddt.Target),
+		Room:     temperatureOutput(ddt.Room),
+		Flour:    temperatureOutput(ddt.Flour),
+		Friction: temperatureOutput(ddt.Friction),
+		Water:    temperatureOutput(water),
+	}
+	if ddt.Starter != nil {
+		starter := temperatureOutput(*ddt.Starter)
+		out.Starter = &starter
+	}
+	return out
+}
+
+// FermentEstimateOutput is the output of ferment-estimate. Times are in
+// minutes and Recipe is null if no recipe is given.
+type FermentEstimateOutput struct {
+	Recipe           *RecipeOutput     `json:"recipe" yaml:"recipe"`
+	Temperature      TemperatureOutput `json:"temperature" yaml:"temperature"`
+	ProofTemperature TemperatureOutput `json:"proof_temperature" yaml:"proof_temperature"`
+	Starter          float64           `json:"starter" yaml:"starter"`
+	Flour            recipe.FlourType  `json:"flour" yaml:"flour"`
+	Bulk             int64             `json:"bulk" yaml:"bulk"`
+	Proof            int64             `json:"proof" yaml:"proof"`
+	Q10              float64           `json:"q10" yaml:"q10"`
+	Observations     int               `json:"observations" yaml:"observations"`
+}
+
+// Output returns the structured output of the estimate.
+func (e FermentEstimate) Output() FermentEstimateOutput {
+	out := FermentEstimateOutput{
+		Temperature:      temperatureOutput(e.Bulk.Temperature),
+		ProofTemperature: temperatureOutput(e.Proof.Temperature),
+		Starter:          e.Bulk.Starter,
+		Flour:            e.Bulk.Flour,
+		Bulk:             int64(e.BulkTime / time.Minute),
+		Proof:            int64(e.ProofTime / time.Minute),
+		Q10:              e.Model.Q10,
+		Observations:     e.Model.Observations,
+	}
+	if e.Recipe != nil {
+		r := recipeOutput(*e.Recipe)
+		out.Recipe = &r
+	}
+	return out
+}
+
+// BakeOutput is a bake of the journal. RecipeName is the name of the recipe
+// when it was baked and RecipeID is null if the recipe was removed.
+type BakeOutput struct {
+	ID            int64      `json:"id" yaml:"id"`
+	RecipeID      *int64     `json:"recipe_id" yaml:"recipe_id"`
+	RecipeName    string     `json:"recipe_name" yaml:"recipe_name"`
+	RecipeVersion string     `json:"recipe_version" yaml:"recipe_version"`
+	Dependency    *string    `json:"dependency" yaml:"dependency"`
+	Scale         float64    `json:"scale" yaml:"scale"`
+	Notes         string     `json:"notes" yaml:"notes"`
+	Rating        *int64     `json:"rating" yaml:"rating"`
+	StartedAt     time.Time  `json:"started_at" yaml:"started_at"`
+	FinishedAt    *time.Time `json:"finished_at" yaml:"finished_at"`
+}
+
+func bakeOutput(bake query.Bake) BakeOutput {
+	out := BakeOutput{
+		ID:            bake.ID,
+		RecipeID:      bake.RecipeID,
+		RecipeName:    bake.RecipeName,
+		RecipeVersion: bake.RecipeVersion,
+		Dependency:    bake.Dependency,
+		Scale:         bake.Scale,
+		Notes:         bake.Notes,
+		Rating:        bake.Rating,
+		StartedAt:     bake.StartedAt.Local(),
+	}
+	if bake.FinishedAt != nil {
+		finishedAt := bake.FinishedAt.Local()
+		out.FinishedAt = &finishedAt
+	}
+	return out
+}
+
+func bakesOutput(bakes []query.Bake) []BakeOutput {
+	out := make([]BakeOutput, 0, len(bakes))
+	for _, bake := range bakes {
+		out = append(out, bakeOutput(bake))
+	}
+	return out
+}
+
+// BakeListOutput is the output of bake list.
+type BakeListOutput struct {
+	Bakes []BakeOutput `json:"bakes" yaml:"bakes"`
+}
+
+func bakeListOutput(bakes []query.Bake) BakeListOutput {
+	return BakeListOutput{Bakes: bakesOutput(bakes)}
+}
+
+// BakeShowOutput is the output of bake show. Photos are absolute paths.
+type BakeShowOutput struct {
+	BakeOutput    `yaml:",inline"`
+	RecipeChanged bool            `json:"recipe_changed" yaml:"recipe_changed"`
+	Logs          []BakeLogOutput `json:"logs" yaml:"logs"`
+	Photos        []string        `json:"photos" yaml:"photos"`
+}
+
+// BakeLogOutput is an entry of the log of a bake. Stage, Temperature and
+// Minutes are null if they weren't logged.
+type BakeLogOutput struct {
+	Text        string               `json:"text" yaml:"text"`
+	Stage       *recipe.FermentStage `json:"stage" yaml:"stage"`
+	Temperature *TemperatureOutput   `json:"temperature" yaml:"temperature"`
+	Minutes     *int64               `json:"minutes" yaml:"minutes"`
+	LoggedAt    time.Time            `json:"logged_at" yaml:"logged_at"`
+}
+
+// Output returns the structured output of the bake.
+func (v BakeView) Output() BakeShowOutput {
+	out := BakeShowOutput{
+		BakeOutput:    bakeOutput(v.Bake),
+		RecipeChanged: v.Changed,
+		Logs:          make([]BakeLogOutput, 0, len(v.Logs)),
+		Photos:        make([]string, 0, len(v.Photos)),
+	}
+	for _, entry := range v.Logs {
+		log := BakeLogOutput{
+			Text:     entry.Text,
+			Minutes:  entry.Minutes,
+			LoggedAt: entry.LoggedAt.Local(),
+		}
+		if entry.Stage != "" {
+			stage := entry.Stage
+			log.Stage = &stage
+		}
+		if entry.Temperature != nil {
+			t := temperatureOutput(recipe.Temperature{Value: *entry.Temperature, Scale: entry.TemperatureScale})
+			log.Temperature = &t
+		}
+		out.Logs = append(out.Logs, log)
+	}
+	for _, photo := range v.Photos {
+		out.Photos = append(out.Photos, filepath.Join(v.PhotoDir, photo.Path))
+	}
+	return out
+}
+
+// MigrationStatusOutput is the output of db status.
+type MigrationStatusOutput struct {
+	Database   string            `json:"database" yaml:"database"`
+	Migrations []MigrationOutput `json:"migrations" yaml:"migrations"`
+}
+
+type MigrationOutput struct {
+	Version int    `json:"version" yaml:"version"`
+	Name    string `json:"name" yaml:"name"`
+	Applied bool   `json:"applied" yaml:"applied"`
+}
+
+func migrationStatusOutput(database string, migrations []MigrationStatus) MigrationStatusOutput {
+	out := MigrationStatusOutput{Database: database, Migrations: make([]MigrationOutput, 0, len(migrations))}
+	for _, m := range migrations {
+		out.Migrations = append(out.Migrations, MigrationOutput{Version: m.Version, Name: m.Name, Applied: m.Applied})
+	}
+	return out
+}
+
+// LintOutput is the output of lint.
+type LintOutput struct {
+	Recipes []LintRecipeOutput `json:"recipes" yaml:"recipes"`
+}
+
+type LintRecipeOutput struct {
+	ID     int64         `json:"id" yaml:"id"`
+	Name   string        `json:"name" yaml:"name"`
+	Issues []IssueOutput `json:"issues" yaml:"issues"`
+}
+
+// IssueOutput is an issue found by lint. Stage is empty for the final dough
+// and Ingredient for issues of the recipe or a stage.
+type IssueOutput struct {
+	Severity   recipe.Severity `json:"severity" yaml:"severity"`
+	Check      string          `json:"check" yaml:"check"`
+	Stage      string          `json:"stage" yaml:"stage"`
+	Ingredient string          `json:"ingredient" yaml:"ingredient"`
+	Message    string          `json:"message" yaml:"message"`
+}
+
+func lintOutput(results []lintResult) LintOutput {
+	out := LintOutput{Recipes: make([]LintRecipeOutput, 0, len(results))}
+	for _, result := range results {
+		r := LintRecipeOutput{
+			ID:     result.Recipe.ID,
+			Name:   result.Recipe.Name,
+			Issues: make([]IssueOutput, 0, len(result.Issues)),
+		}
+		for _, issue := range result.Issues {
+			r.Issues = append(r.Issues, IssueOutput{
+				Severity:   issue.Severity,
+				Check:      issue.Check,
+				Stage:      issue.Stage,
+				Ingredient: issue.Ingredient,
+				Message:    issue.Message,
+			})
+		}
+		out.Recipes = append(out.Recipes, r)
+	}
+	return out
+}
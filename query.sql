@@ -1,7 +1,8 @@
 /* name: GetRecipe :one */
 SELECT
   r.id,
-  r.name
+  r.name,
+  r.notes
 FROM recipes AS r
 WHERE
   r.id = ?
@@ -10,21 +11,23 @@ LIMIT 1;
 /* name: ListRecipes :many */
 SELECT
   r.id,
-  r.name
+  r.name,
+  r.notes
 FROM recipes AS r
 ORDER BY
   r.id;
 
 /* name: CreateRecipe :one */
 INSERT INTO recipes (
-  name
+  name,
+  notes
 )
 VALUES
-  (?)
+  (?, ?)
 RETURNING *;
 
 /* name: UpdateRecipe :one */
-UPDATE recipes SET name = ?
+UPDATE recipes SET name = ?, notes = ?
 WHERE
   id = ?
 RETURNING *;
@@ -35,9 +38,10 @@ WHERE
   id = ?;
 
 /* name: ListRecipesByIngredient :many */
-SELECT
+SELECT DISTINCT
   r.id,
-  r.name
+  r.name,
+  r.notes
 FROM recipes AS r
 JOIN recipe_ingredients AS ri
   ON ri.recipe_id = r.id
@@ -56,10 +60,52 @@ SELECT
   ri.prefer_unit_category,
   ri.percentage,
   ri.dependency,
-  i.kind
+  ri.mode,
+  ri.amount,
+  ri.unit,
+  i.kind,
+  i.hydration,
+  i.density,
+  i.piece_weight,
+  s.name AS stage,
+  ri.position,
+  ri.group_name
 FROM recipe_ingredients AS ri
 JOIN ingredients AS i
   ON i.id = ri.ingredient_id
+LEFT JOIN stages AS s
+  ON s.id = ri.stage_id
+WHERE
+  ri.recipe_id = ?
+ORDER BY
+  s.position IS NULL,
+  s.position,
+  ri.position,
+  ri.id;
+
+/* name: GetRecipeIngredient :one */
+SELECT
+  ri.id,
+  ri.recipe_id,
+  ri.ingredient_id,
+  ri.prefer_unit_category,
+  ri.percentage,
+  ri.dependency,
+  ri.mode,
+  ri.amount,
+  ri.unit,
+  ri.stage_id,
+  ri.position,
+  ri.group_name
+FROM recipe_ingredients AS ri
+WHERE
+  ri.id = ?
+LIMIT 1;
+
+/* name: NextRecipeIngredientPosition :one */
+SELECT
+  CAST(COALESCE(MAX(ri.position), 0) + 1 AS INTEGER) AS position
+FROM recipe_ingredients AS ri
 WHERE
   ri.recipe_id = ?;
 
@@ -69,28 +115,174 @@ INSERT INTO recipe_ingredients (
   ingredient_id,
   prefer_unit_category,
   percentage,
-  dependency
+  dependency,
+  mode,
+  amount,
+  unit,
+  stage_id,
+  position,
+  group_name
 )
 VALUES
-  (?, ?, ?, ?, ?)
+  (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 /* name: UpdateRecipeIngredient :one */
-UPDATE recipe_ingredients SET prefer_unit_category = ?, percentage = ?, dependency = ?, ingredient_id = ?
+UPDATE recipe_ingredients SET prefer_unit_category = ?, percentage = ?, dependency = ?, mode = ?, amount = ?, unit = ?, ingredient_id = ?, group_name = ?
 WHERE
   id = ?
 RETURNING *;
 
+/* name: SetRecipeIngredientPosition :exec */
+UPDATE recipe_ingredients SET position = ?
+WHERE
+  id = ?;
+
 /* name: DeleteRecipeIngredient :exec */
 DELETE FROM recipe_ingredients
 WHERE
   id = ?;
 
+/* name: ListStages :many */
+SELECT
+  s.id,
+  s.recipe_id,
+  s.name,
+  s.position
+FROM stages AS s
+WHERE
+  s.recipe_id = ?
+ORDER BY
+  s.position;
+
+/* name: GetStageByName :one */
+SELECT
+  s.id,
+  s.recipe_id,
+  s.name,
+  s.position
+FROM stages AS s
+WHERE
+  s.recipe_id = ? AND s.name LIKE ?
+LIMIT 1;
+
+/* name: CreateStage :one */
+INSERT INTO stages (
+  recipe_id,
+  name,
+  position
+)
+VALUES
+  (?, ?, ?)
+RETURNING *;
+
+/* name: ListSteps :many */
+SELECT
+  s.id,
+  s.recipe_id,
+  s.position,
+  s.text,
+  s.duration_min,
+  s.duration_max,
+  s.temperature,
+  s.temperature_scale
+FROM steps AS s
+WHERE
+  s.recipe_id = ?
+ORDER BY
+  s.position,
+  s.id;
+
+/* name: GetStep :one */
+SELECT
+  s.id,
+  s.recipe_id,
+  s.position,
+  s.text,
+  s.duration_min,
+  s.duration_max,
+  s.temperature,
+  s.temperature_scale
+FROM steps AS s
+WHERE
+  s.id = ?
+LIMIT 1;
+
+/* name: NextStepPosition :one */
+SELECT
+  CAST(COALESCE(MAX(s.position), 0) + 1 AS INTEGER) AS position
+FROM steps AS s
+WHERE
+  s.recipe_id = ?;
+
+/* name: CreateStep :one */
+INSERT INTO steps (
+  recipe_id,
+  position,
+  text,
+  duration_min,
+  duration_max,
+  temperature,
+  temperature_scale
+)
+VALUES
+  (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+/* name: UpdateStep :one */
+UPDATE steps SET text = ?, duration_min = ?, duration_max = ?, temperature = ?, temperature_scale = ?
+WHERE
+  id = ?
+RETURNING *;
+
+/* name: DeleteStep :exec */
+DELETE FROM steps
+WHERE
+  id = ?;
+
+/* name: ListStepIngredients :many */
+SELECT
+  si.step_id,
+  si.recipe_ingredient_id,
+  i.name,
+  st.name AS stage
+FROM step_ingredients AS si
+JOIN steps AS s
+  ON s.id = si.step_id
+JOIN recipe_ingredients AS ri
+  ON ri.id = si.recipe_ingredient_id
+JOIN ingredients AS i
+  ON i.id = ri.ingredient_id
+LEFT JOIN stages AS st
+  ON st.id = ri.stage_id
+WHERE
+  s.recipe_id = ?
+ORDER BY
+  si.step_id,
+  ri.position,
+  ri.id;
+
+/* name: CreateStepIngredient :exec */
+INSERT INTO step_ingredients (
+  step_id,
+  recipe_ingredient_id
+)
+VALUES
+  (?, ?);
+
+/* name: DeleteStepIngredients :exec */
+DELETE FROM step_ingredients
+WHERE
+  step_id = ?;
+
 /* name: GetIngredients :many */
 SELECT
   i.id,
   i.name,
-  i.kind
+  i.kind,
+  i.hydration,
+  i.density,
+  i.piece_weight
 FROM ingredients AS i
 ORDER BY
   i.id;
@@ -99,7 +291,10 @@ ORDER BY
 SELECT
   i.id,
   i.name,
-  i.kind
+  i.kind,
+  i.hydration,
+  i.density,
+  i.piece_weight
 FROM ingredients AS i
 WHERE
   i.id = ?;
@@ -108,7 +303,10 @@ WHERE
 SELECT
   i.id,
   i.name,
-  i.kind
+  i.kind,
+  i.hydration,
+  i.density,
+  i.piece_weight
 FROM ingredients AS i
 WHERE
   i.name LIKE ?
@@ -117,10 +315,11 @@ LIMIT 1;
 /* name: CreateIngredient :one */
 INSERT INTO ingredients (
   name,
-  kind
+  kind,
+  hydration
 )
 VALUES
-  (?, ?)
+  (?, ?, ?)
 RETURNING *;
 
 /* name: UpdateIngredient :one */
@@ -129,7 +328,148 @@ WHERE
   id = ?
 RETURNING *;
 
+/* name: SetIngredientHydration :one */
+UPDATE ingredients SET hydration = ?
+WHERE
+  id = ?
+RETURNING *;
+
+/* name: SetIngredientDensity :one */
+UPDATE ingredients SET density = ?
+WHERE
+  id = ?
+RETURNING *;
+
+/* name: SetIngredientPieceWeight :one */
+UPDATE ingredients SET piece_weight = ?
+WHERE
+  id = ?
+RETURNING *;
+
 /* name: DeleteIngredient :exec */
 DELETE FROM ingredients
 WHERE
-  id = ?;
\ No newline at end of file
+  id = ?;
+
+/* name: ListFermentLogs :many */
+SELECT
+  *
+FROM ferment_logs
+ORDER BY
+  logged_at,
+  id;
+
+/* name: CreateFermentLog :one */
+INSERT INTO ferment_logs (
+  recipe_id,
+  stage,
+  temperature,
+  temperature_scale,
+  starter,
+  flour,
+  minutes
+)
+VALUES
+  (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+/* name: GetBake :one */
+SELECT
+  *
+FROM bakes
+WHERE
+  id = ?
+LIMIT 1;
+
+/* name: GetCurrentBake :one */
+SELECT
+  *
+FROM bakes
+WHERE
+  finished_at IS NULL
+ORDER BY
+  started_at DESC,
+  id DESC
+LIMIT 1;
+
+/* name: ListBakes :many */
+SELECT
+  *
+FROM bakes
+ORDER BY
+  started_at DESC,
+  id DESC;
+
+/* name: ListRecipeBakes :many */
+SELECT
+  *
+FROM bakes
+WHERE
+  recipe_id = ?
+ORDER BY
+  started_at DESC,
+  id DESC
+LIMIT ?;
+
+/* name: CreateBake :one */
+INSERT INTO bakes (
+  recipe_id,
+  recipe_name,
+  recipe_version,
+  dependency,
+  scale,
+  notes,
+  started_at
+)
+VALUES
+  (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+/* name: FinishBake :one */
+UPDATE bakes SET notes = ?, rating = ?, finished_at = ?
+WHERE
+  id = ?
+RETURNING *;
+
+/* name: ListBakeLogs :many */
+SELECT
+  *
+FROM bake_logs
+WHERE
+  bake_id = ?
+ORDER BY
+  logged_at,
+  id;
+
+/* name: CreateBakeLog :one */
+INSERT INTO bake_logs (
+  bake_id,
+  text,
+  stage,
+  temperature,
+  temperature_scale,
+  minutes,
+  logged_at
+)
+VALUES
+  (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+/* name: ListBakePhotos :many */
+SELECT
+  *
+FROM bake_photos
+WHERE
+  bake_id = ?
+ORDER BY
+  added_at,
+  id;
+
+/* name: CreateBakePhoto :one */
+INSERT INTO bake_photos (
+  bake_id,
+  path
+)
+VALUES
+  (?, ?)
+RETURNING *;
@@ -5,18 +5,67 @@
 package query
 
 import (
+	"time"
+
 	"github.com/simonklee/sourdough/recipe"
 )
 
+type Bake struct {
+	ID            int64
+	RecipeID      *int64
+	RecipeName    string
+	RecipeVersion string
+	Dependency    *string
+	Scale         float64
+	Notes         string
+	Rating        *int64
+	StartedAt     time.Time
+	FinishedAt    *time.Time
+}
+
+type BakeLog struct {
+	ID               int64
+	BakeID           int64
+	Text             string
+	Stage            recipe.FermentStage
+	Temperature      *float64
+	TemperatureScale recipe.TemperatureScale
+	Minutes          *int64
+	LoggedAt         time.Time
+}
+
+type BakePhoto struct {
+	ID      int64
+	BakeID  int64
+	Path    string
+	AddedAt time.Time
+}
+
+type FermentLog struct {
+	ID               int64
+	RecipeID         *int64
+	Stage            recipe.FermentStage
+	Temperature      float64
+	TemperatureScale recipe.TemperatureScale
+	Starter          float64
+	Flour            recipe.FlourType
+	Minutes          int64
+	LoggedAt         time.Time
+}
+
 type Ingredient struct {
-	ID   int64
-	Name string
-	Kind recipe.Kind
+	ID          int64
+	Name        string
+	Kind        recipe.Kind
+	Hydration   *float64
+	Density     *float64
+	PieceWeight *float64
 }
 
 type Recipe struct {
-	ID   int64
-	Name string
+	ID    int64
+	Name  string
+	Notes string
 }
 
 type RecipeIngredient struct {
@@ -26,4 +75,33 @@ type RecipeIngredient struct {
 	PreferUnitCategory recipe.UnitCategory
 	Percentage         float64
 	Dependency         string
+	Mode               recipe.Mode
+	Amount             *float64
+	Unit               recipe.Unit
+	StageID            *int64
+	Position           int64
+	GroupName          *string
+}
+
+type Stage struct {
+	ID       int64
+	RecipeID int64
+	Name     string
+	Position int64
+}
+
+type Step struct {
+	ID               int64
+	RecipeID         int64
+	Position         int64
+	Text             string
+	DurationMin      *int64
+	DurationMax      *int64
+	Temperature      *float64
+	TemperatureScale recipe.TemperatureScale
+}
+
+type StepIngredient struct {
+	StepID             int64
+	RecipeIngredientID int64
 }
@@ -9,22 +9,52 @@ import (
 )
 
 type Querier interface {
+	CreateBake(ctx context.Context, arg CreateBakeParams) (Bake, error)
+	CreateBakeLog(ctx context.Context, arg CreateBakeLogParams) (BakeLog, error)
+	CreateBakePhoto(ctx context.Context, arg CreateBakePhotoParams) (BakePhoto, error)
+	CreateFermentLog(ctx context.Context, arg CreateFermentLogParams) (FermentLog, error)
 	CreateIngredient(ctx context.Context, arg CreateIngredientParams) (Ingredient, error)
-	CreateRecipe(ctx context.Context, name string) (Recipe, error)
+	CreateRecipe(ctx context.Context, arg CreateRecipeParams) (Recipe, error)
 	CreateRecipeIngredient(ctx context.Context, arg CreateRecipeIngredientParams) (RecipeIngredient, error)
+	CreateStage(ctx context.Context, arg CreateStageParams) (Stage, error)
+	CreateStep(ctx context.Context, arg CreateStepParams) (Step, error)
+	CreateStepIngredient(ctx context.Context, arg CreateStepIngredientParams) error
 	DeleteIngredient(ctx context.Context, id int64) error
 	DeleteRecipe(ctx context.Context, id int64) error
 	DeleteRecipeIngredient(ctx context.Context, id int64) error
+	DeleteStep(ctx context.Context, id int64) error
+	DeleteStepIngredients(ctx context.Context, stepID int64) error
+	FinishBake(ctx context.Context, arg FinishBakeParams) (Bake, error)
+	GetBake(ctx context.Context, id int64) (Bake, error)
+	GetCurrentBake(ctx context.Context) (Bake, error)
 	GetIngredient(ctx context.Context, id int64) (Ingredient, error)
 	GetIngredientByName(ctx context.Context, name string) (Ingredient, error)
 	GetIngredients(ctx context.Context) ([]Ingredient, error)
 	GetRecipe(ctx context.Context, id int64) (Recipe, error)
+	GetRecipeIngredient(ctx context.Context, id int64) (RecipeIngredient, error)
+	GetStageByName(ctx context.Context, arg GetStageByNameParams) (Stage, error)
+	GetStep(ctx context.Context, id int64) (Step, error)
+	ListBakeLogs(ctx context.Context, bakeID int64) ([]BakeLog, error)
+	ListBakePhotos(ctx context.Context, bakeID int64) ([]BakePhoto, error)
+	ListBakes(ctx context.Context) ([]Bake, error)
+	ListFermentLogs(ctx context.Context) ([]FermentLog, error)
+	ListRecipeBakes(ctx context.Context, arg ListRecipeBakesParams) ([]Bake, error)
 	ListRecipeIngredients(ctx context.Context, recipeID int64) ([]ListRecipeIngredientsRow, error)
 	ListRecipes(ctx context.Context) ([]Recipe, error)
 	ListRecipesByIngredient(ctx context.Context, id int64) ([]Recipe, error)
+	ListStages(ctx context.Context, recipeID int64) ([]Stage, error)
+	ListStepIngredients(ctx context.Context, recipeID int64) ([]ListStepIngredientsRow, error)
+	ListSteps(ctx context.Context, recipeID int64) ([]Step, error)
+	NextRecipeIngredientPosition(ctx context.Context, recipeID int64) (int64, error)
+	NextStepPosition(ctx context.Context, recipeID int64) (int64, error)
+	SetIngredientDensity(ctx context.Context, arg SetIngredientDensityParams) (Ingredient, error)
+	SetIngredientHydration(ctx context.Context, arg SetIngredientHydrationParams) (Ingredient, error)
+	SetIngredientPieceWeight(ctx context.Context, arg SetIngredientPieceWeightParams) (Ingredient, error)
+	SetRecipeIngredientPosition(ctx context.Context, arg SetRecipeIngredientPositionParams) error
 	UpdateIngredient(ctx context.Context, arg UpdateIngredientParams) (Ingredient, error)
 	UpdateRecipe(ctx context.Context, arg UpdateRecipeParams) (Recipe, error)
 	UpdateRecipeIngredient(ctx context.Context, arg UpdateRecipeIngredientParams) (RecipeIngredient, error)
+	UpdateStep(ctx context.Context, arg UpdateStepParams) (Step, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -7,45 +7,238 @@ package query
 
 import (
 	"context"
+	"time"
 
 	"github.com/simonklee/sourdough/recipe"
 )
 
+const createBake = `-- name: CreateBake :one
+INSERT INTO bakes (
+  recipe_id,
+  recipe_name,
+  recipe_version,
+  dependency,
+  scale,
+  notes,
+  started_at
+)
+VALUES
+  (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, recipe_id, recipe_name, recipe_version, dependency, scale, notes, rating, started_at, finished_at
+`
+
+type CreateBakeParams struct {
+	RecipeID      *int64
+	RecipeName    string
+	RecipeVersion string
+	Dependency    *string
+	Scale         float64
+	Notes         string
+	StartedAt     time.Time
+}
+
+func (q *Queries) CreateBake(ctx context.Context, arg CreateBakeParams) (Bake, error) {
+	row := q.db.QueryRowContext(ctx, createBake,
+		arg.RecipeID,
+		arg.RecipeName,
+		arg.RecipeVersion,
+		arg.Dependency,
+		arg.Scale,
+		arg.Notes,
+		arg.StartedAt,
+	)
+	var i Bake
+	err := row.Scan(
+		&i.ID,
+		&i.RecipeID,
+		&i.RecipeName,
+		&i.RecipeVersion,
+		&i.Dependency,
+		&i.Scale,
+		&i.Notes,
+		&i.Rating,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const createBakeLog = `-- name: CreateBakeLog :one
+INSERT INTO bake_logs (
+  bake_id,
+  text,
+  stage,
+  temperature,
+  temperature_scale,
+  minutes,
+  logged_at
+)
+VALUES
+  (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, bake_id, text, stage, temperature, temperature_scale, minutes, logged_at
+`
+
+type CreateBakeLogParams struct {
+	BakeID           int64
+	Text             string
+	Stage            recipe.FermentStage
+	Temperature      *float64
+	TemperatureScale recipe.TemperatureScale
+	Minutes          *int64
+	LoggedAt         time.Time
+}
+
+func (q *Queries) CreateBakeLog(ctx context.Context, arg CreateBakeLogParams) (BakeLog, error) {
+	row := q.db.QueryRowContext(ctx, createBakeLog,
+		arg.BakeID,
+		arg.Text,
+		arg.Stage,
+		arg.Temperature,
+		arg.TemperatureScale,
+		arg.Minutes,
+		arg.LoggedAt,
+	)
+	var i BakeLog
+	err := row.Scan(
+		&i.ID,
+		&i.BakeID,
+		&i.Text,
+		&i.Stage,
+		&i.Temperature,
+		&i.TemperatureScale,
+		&i.Minutes,
+		&i.LoggedAt,
+	)
+	return i, err
+}
+
+const createBakePhoto = `-- name: CreateBakePhoto :one
+INSERT INTO bake_photos (
+  bake_id,
+  path
+)
+VALUES
+  (?, ?)
+RETURNING id, bake_id, path, added_at
+`
+
+type CreateBakePhotoParams struct {
+	BakeID int64
+	Path   string
+}
+
+func (q *Queries) CreateBakePhoto(ctx context.Context, arg CreateBakePhotoParams) (BakePhoto, error) {
+	row := q.db.QueryRowContext(ctx, createBakePhoto, arg.BakeID, arg.Path)
+	var i BakePhoto
+	err := row.Scan(
+		&i.ID,
+		&i.BakeID,
+		&i.Path,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const createFermentLog = `-- name: CreateFermentLog :one
+INSERT INTO ferment_logs (
+  recipe_id,
+  stage,
+  temperature,
+  temperature_scale,
+  starter,
+  flour,
+  minutes
+)
+VALUES
+  (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, recipe_id, stage, temperature, temperature_scale, starter, flour, minutes, logged_at
+`
+
+type CreateFermentLogParams struct {
+	RecipeID         *int64
+	Stage            recipe.FermentStage
+	Temperature      float64
+	TemperatureScale recipe.TemperatureScale
+	Starter          float64
+	Flour            recipe.FlourType
+	Minutes          int64
+}
+
+func (q *Queries) CreateFermentLog(ctx context.Context, arg CreateFermentLogParams) (FermentLog, error) {
+	row := q.db.QueryRowContext(ctx, createFermentLog,
+		arg.RecipeID,
+		arg.Stage,
+		arg.Temperature,
+		arg.TemperatureScale,
+		arg.Starter,
+		arg.Flour,
+		arg.Minutes,
+	)
+	var i FermentLog
+	err := row.Scan(
+		&i.ID,
+		&i.RecipeID,
+		&i.Stage,
+		&i.Temperature,
+		&i.TemperatureScale,
+		&i.Starter,
+		&i.Flour,
+		&i.Minutes,
+		&i.LoggedAt,
+	)
+	return i, err
+}
+
 const createIngredient = `-- name: CreateIngredient :one
 INSERT INTO ingredients (
   name,
-  kind
+  kind,
+  hydration
 )
 VALUES
-  (?, ?)
-RETURNING id, name, kind
+  (?, ?, ?)
+RETURNING id, name, kind, hydration, density, piece_weight
 `
 
 type CreateIngredientParams struct {
-	Name string
-	Kind recipe.Kind
+	Name      string
+	Kind      recipe.Kind
+	Hydration *float64
 }
 
 func (q *Queries) CreateIngredient(ctx context.Context, arg CreateIngredientParams) (Ingredient, error) {
-	row := q.db.QueryRowContext(ctx, createIngredient, arg.Name, arg.Kind)
+	row := q.db.QueryRowContext(ctx, createIngredient, arg.Name, arg.Kind, arg.Hydration)
 	var i Ingredient
-	err := row.Scan(&i.ID, &i.Name, &i.Kind)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Kind,
+		&i.Hydration,
+		&i.Density,
+		&i.PieceWeight,
+	)
 	return i, err
 }
 
 const createRecipe = `-- name: CreateRecipe :one
 INSERT INTO recipes (
-  name
+  name,
+  notes
 )
 VALUES
-  (?)
-RETURNING id, name
+  (?, ?)
+RETURNING id, name, notes
 `
 
-func (q *Queries) CreateRecipe(ctx context.Context, name string) (Recipe, error) {
-	row := q.db.QueryRowContext(ctx, createRecipe, name)
+type CreateRecipeParams struct {
+	Name  string
+	Notes string
+}
+
+func (q *Queries) CreateRecipe(ctx context.Context, arg CreateRecipeParams) (Recipe, error) {
+	row := q.db.QueryRowContext(ctx, createRecipe, arg.Name, arg.Notes)
 	var i Recipe
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.Notes)
 	return i, err
 }
 
@@ -55,11 +248,17 @@ INSERT INTO recipe_ingredients (
   ingredient_id,
   prefer_unit_category,
   percentage,
-  dependency
+  dependency,
+  mode,
+  amount,
+  unit,
+  stage_id,
+  position,
+  group_name
 )
 VALUES
-  (?, ?, ?, ?, ?)
-RETURNING id, recipe_id, ingredient_id, prefer_unit_category, percentage, dependency
+  (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, recipe_id, ingredient_id, prefer_unit_category, percentage, dependency, mode, amount, unit, stage_id, position, group_name
 `
 
 type CreateRecipeIngredientParams struct {
@@ -68,6 +267,12 @@ type CreateRecipeIngredientParams struct {
 	PreferUnitCategory recipe.UnitCategory
 	Percentage         float64
 	Dependency         string
+	Mode               recipe.Mode
+	Amount             *float64
+	Unit               recipe.Unit
+	StageID            *int64
+	Position           int64
+	GroupName          *string
 }
 
 func (q *Queries) CreateRecipeIngredient(ctx context.Context, arg CreateRecipeIngredientParams) (RecipeIngredient, error) {
@@ -77,6 +282,12 @@ func (q *Queries) CreateRecipeIngredient(ctx context.Context, arg CreateRecipeIn
 		arg.PreferUnitCategory,
 		arg.Percentage,
 		arg.Dependency,
+		arg.Mode,
+		arg.Amount,
+		arg.Unit,
+		arg.StageID,
+		arg.Position,
+		arg.GroupName,
 	)
 	var i RecipeIngredient
 	err := row.Scan(
@@ -86,10 +297,113 @@ func (q *Queries) CreateRecipeIngredient(ctx context.Context, arg CreateRecipeIn
 		&i.PreferUnitCategory,
 		&i.Percentage,
 		&i.Dependency,
+		&i.Mode,
+		&i.Amount,
+		&i.Unit,
+		&i.StageID,
+		&i.Position,
+		&i.GroupName,
+	)
+	return i, err
+}
+
+const createStage = `-- name: CreateStage :one
+INSERT INTO stages (
+  recipe_id,
+  name,
+  position
+)
+VALUES
+  (?, ?, ?)
+RETURNING id, recipe_id, name, position
+`
+
+type CreateStageParams struct {
+	RecipeID int64
+	Name     string
+	Position int64
+}
+
+func (q *Queries) CreateStage(ctx context.Context, arg CreateStageParams) (Stage, error) {
+	row := q.db.QueryRowContext(ctx, createStage, arg.RecipeID, arg.Name, arg.Position)
+	var i Stage
+	err := row.Scan(
+		&i.ID,
+		&i.RecipeID,
+		&i.Name,
+		&i.Position,
+	)
+	return i, err
+}
+
+const createStep = `-- name: CreateStep :one
+INSERT INTO steps (
+  recipe_id,
+  position,
+  text,
+  duration_min,
+  duration_max,
+  temperature,
+  temperature_scale
+)
+VALUES
+  (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, recipe_id, position, text, duration_min, duration_max, temperature, temperature_scale
+`
+
+type CreateStepParams struct {
+	RecipeID         int64
+	Position         int64
+	Text             string
+	DurationMin      *int64
+	DurationMax      *int64
+	Temperature      *float64
+	TemperatureScale recipe.TemperatureScale
+}
+
+func (q *Queries) CreateStep(ctx context.Context, arg CreateStepParams) (Step, error) {
+	row := q.db.QueryRowContext(ctx, createStep,
+		arg.RecipeID,
+		arg.Position,
+		arg.Text,
+		arg.DurationMin,
+		arg.DurationMax,
+		arg.Temperature,
+		arg.TemperatureScale,
+	)
+	var i Step
+	err := row.Scan(
+		&i.ID,
+		&i.RecipeID,
+		&i.Position,
+		&i.Text,
+		&i.DurationMin,
+		&i.DurationMax,
+		&i.Temperature,
+		&i.TemperatureScale,
 	)
 	return i, err
 }
 
+const createStepIngredient = `-- name: CreateStepIngredient :exec
+INSERT INTO step_ingredients (
+  step_id,
+  recipe_ingredient_id
+)
+VALUES
+  (?, ?)
+`
+
+type CreateStepIngredientParams struct {
+	StepID             int64
+	RecipeIngredientID int64
+}
+
+func (q *Queries) CreateStepIngredient(ctx context.Context, arg CreateStepIngredientParams) error {
+	_, err := q.db.ExecContext(ctx, createStepIngredient, arg.StepID, arg.RecipeIngredientID)
+	return err
+}
+
 const deleteIngredient = `-- name: DeleteIngredient :exec
 DELETE FROM ingredients
 WHERE
@@ -123,11 +437,130 @@ func (q *Queries) DeleteRecipeIngredient(ctx context.Context, id int64) error {
 	return err
 }
 
+const deleteStep = `-- name: DeleteStep :exec
+DELETE FROM steps
+WHERE
+  id = ?
+`
+
+func (q *Queries) DeleteStep(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteStep, id)
+	return err
+}
+
+const deleteStepIngredients = `-- name: DeleteStepIngredients :exec
+DELETE FROM step_ingredients
+WHERE
+  step_id = ?
+`
+
+func (q *Queries) DeleteStepIngredients(ctx context.Context, stepID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteStepIngredients, stepID)
+	return err
+}
+
+const finishBake = `-- name: FinishBake :one
+UPDATE bakes SET notes = ?, rating = ?, finished_at = ?
+WHERE
+  id = ?
+RETURNING id, recipe_id, recipe_name, recipe_version, dependency, scale, notes, rating, started_at, finished_at
+`
+
+type FinishBakeParams struct {
+	Notes      string
+	Rating     *int64
+	FinishedAt *time.Time
+	ID         int64
+}
+
+func (q *Queries) FinishBake(ctx context.Context, arg FinishBakeParams) (Bake, error) {
+	row := q.db.QueryRowContext(ctx, finishBake,
+		arg.Notes,
+		arg.Rating,
+		arg.FinishedAt,
+		arg.ID,
+	)
+	var i Bake
+	err := row.Scan(
+		&i.ID,
+		&i.RecipeID,
+		&i.RecipeName,
+		&i.RecipeVersion,
+		&i.Dependency,
+		&i.Scale,
+		&i.Notes,
+		&i.Rating,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const getBake = `-- name: GetBake :one
+SELECT
+  id, recipe_id, recipe_name, recipe_version, dependency, scale, notes, rating, started_at, finished_at
+FROM bakes
+WHERE
+  id = ?
+LIMIT 1
+`
+
+func (q *Queries) GetBake(ctx context.Context, id int64) (Bake, error) {
+	row := q.db.QueryRowContext(ctx, getBake, id)
+	var i Bake
+	err := row.Scan(
+		&i.ID,
+		&i.RecipeID,
+		&i.RecipeName,
+		&i.RecipeVersion,
+		&i.Dependency,
+		&i.Scale,
+		&i.Notes,
+		&i.Rating,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const getCurrentBake = `-- name: GetCurrentBake :one
+SELECT
+  id, recipe_id, recipe_name, recipe_version, dependency, scale, notes, rating, started_at, finished_at
+FROM bakes
+WHERE
+  finished_at IS NULL
+ORDER BY
+  started_at DESC,
+  id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetCurrentBake(ctx context.Context) (Bake, error) {
+	row := q.db.QueryRowContext(ctx, getCurrentBake)
+	var i Bake
+	err := row.Scan(
+		&i.ID,
+		&i.RecipeID,
+		&i.RecipeName,
+		&i.RecipeVersion,
+		&i.Dependency,
+		&i.Scale,
+		&i.Notes,
+		&i.Rating,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
 const getIngredient = `-- name: GetIngredient :one
 SELECT
   i.id,
   i.name,
-  i.kind
+  i.kind,
+  i.hydration,
+  i.density,
+  i.piece_weight
 FROM ingredients AS i
 WHERE
   i.id = ?
@@ -136,7 +569,14 @@ WHERE
 func (q *Queries) GetIngredient(ctx context.Context, id int64) (Ingredient, error) {
 	row := q.db.QueryRowContext(ctx, getIngredient, id)
 	var i Ingredient
-	err := row.Scan(&i.ID, &i.Name, &i.Kind)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Kind,
+		&i.Hydration,
+		&i.Density,
+		&i.PieceWeight,
+	)
 	return i, err
 }
 
@@ -144,7 +584,10 @@ const getIngredientByName = `-- name: GetIngredientByName :one
 SELECT
   i.id,
   i.name,
-  i.kind
+  i.kind,
+  i.hydration,
+  i.density,
+  i.piece_weight
 FROM ingredients AS i
 WHERE
   i.name LIKE ?
@@ -154,7 +597,14 @@ LIMIT 1
 func (q *Queries) GetIngredientByName(ctx context.Context, name string) (Ingredient, error) {
 	row := q.db.QueryRowContext(ctx, getIngredientByName, name)
 	var i Ingredient
-	err := row.Scan(&i.ID, &i.Name, &i.Kind)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Kind,
+		&i.Hydration,
+		&i.Density,
+		&i.PieceWeight,
+	)
 	return i, err
 }
 
@@ -162,7 +612,10 @@ const getIngredients = `-- name: GetIngredients :many
 SELECT
   i.id,
   i.name,
-  i.kind
+  i.kind,
+  i.hydration,
+  i.density,
+  i.piece_weight
 FROM ingredients AS i
 ORDER BY
   i.id
@@ -177,7 +630,14 @@ func (q *Queries) GetIngredients(ctx context.Context) ([]Ingredient, error) {
 	var items []Ingredient
 	for rows.Next() {
 		var i Ingredient
-		if err := rows.Scan(&i.ID, &i.Name, &i.Kind); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Kind,
+			&i.Hydration,
+			&i.Density,
+			&i.PieceWeight,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -194,7 +654,8 @@ func (q *Queries) GetIngredients(ctx context.Context) ([]Ingredient, error) {
 const getRecipe = `-- name: GetRecipe :one
 SELECT
   r.id,
-  r.name
+  r.name,
+  r.notes
 FROM recipes AS r
 WHERE
   r.id = ?
@@ -204,53 +665,140 @@ LIMIT 1
 func (q *Queries) GetRecipe(ctx context.Context, id int64) (Recipe, error) {
 	row := q.db.QueryRowContext(ctx, getRecipe, id)
 	var i Recipe
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.Notes)
 	return i, err
 }
 
-const listRecipeIngredients = `-- name: ListRecipeIngredients :many
+const getRecipeIngredient = `-- name: GetRecipeIngredient :one
 SELECT
   ri.id,
   ri.recipe_id,
-  i.name,
+  ri.ingredient_id,
   ri.prefer_unit_category,
   ri.percentage,
   ri.dependency,
-  i.kind
+  ri.mode,
+  ri.amount,
+  ri.unit,
+  ri.stage_id,
+  ri.position,
+  ri.group_name
 FROM recipe_ingredients AS ri
-JOIN ingredients AS i
-  ON i.id = ri.ingredient_id
 WHERE
-  ri.recipe_id = ?
+  ri.id = ?
+LIMIT 1
 `
 
-type ListRecipeIngredientsRow struct {
-	ID                 int64
-	RecipeID           int64
-	Name               string
-	PreferUnitCategory recipe.UnitCategory
-	Percentage         float64
-	Dependency         string
-	Kind               recipe.Kind
+func (q *Queries) GetRecipeIngredient(ctx context.Context, id int64) (RecipeIngredient, error) {
+	row := q.db.QueryRowContext(ctx, getRecipeIngredient, id)
+	var i RecipeIngredient
+	err := row.Scan(
+		&i.ID,
+		&i.RecipeID,
+		&i.IngredientID,
+		&i.PreferUnitCategory,
+		&i.Percentage,
+		&i.Dependency,
+		&i.Mode,
+		&i.Amount,
+		&i.Unit,
+		&i.StageID,
+		&i.Position,
+		&i.GroupName,
+	)
+	return i, err
 }
 
-func (q *Queries) ListRecipeIngredients(ctx context.Context, recipeID int64) ([]ListRecipeIngredientsRow, error) {
-	rows, err := q.db.QueryContext(ctx, listRecipeIngredients, recipeID)
+const getStageByName = `-- name: GetStageByName :one
+SELECT
+  s.id,
+  s.recipe_id,
+  s.name,
+  s.position
+FROM stages AS s
+WHERE
+  s.recipe_id = ? AND s.name LIKE ?
+LIMIT 1
+`
+
+type GetStageByNameParams struct {
+	RecipeID int64
+	Name     string
+}
+
+func (q *Queries) GetStageByName(ctx context.Context, arg GetStageByNameParams) (Stage, error) {
+	row := q.db.QueryRowContext(ctx, getStageByName, arg.RecipeID, arg.Name)
+	var i Stage
+	err := row.Scan(
+		&i.ID,
+		&i.RecipeID,
+		&i.Name,
+		&i.Position,
+	)
+	return i, err
+}
+
+const getStep = `-- name: GetStep :one
+SELECT
+  s.id,
+  s.recipe_id,
+  s.position,
+  s.text,
+  s.duration_min,
+  s.duration_max,
+  s.temperature,
+  s.temperature_scale
+FROM steps AS s
+WHERE
+  s.id = ?
+LIMIT 1
+`
+
+func (q *Queries) GetStep(ctx context.Context, id int64) (Step, error) {
+	row := q.db.QueryRowContext(ctx, getStep, id)
+	var i Step
+	err := row.Scan(
+		&i.ID,
+		&i.RecipeID,
+		&i.Position,
+		&i.Text,
+		&i.DurationMin,
+		&i.DurationMax,
+		&i.Temperature,
+		&i.TemperatureScale,
+	)
+	return i, err
+}
+
+const listBakeLogs = `-- name: ListBakeLogs :many
+SELECT
+  id, bake_id, text, stage, temperature, temperature_scale, minutes, logged_at
+FROM bake_logs
+WHERE
+  bake_id = ?
+ORDER BY
+  logged_at,
+  id
+`
+
+func (q *Queries) ListBakeLogs(ctx context.Context, bakeID int64) ([]BakeLog, error) {
+	rows, err := q.db.QueryContext(ctx, listBakeLogs, bakeID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListRecipeIngredientsRow
+	var items []BakeLog
 	for rows.Next() {
-		var i ListRecipeIngredientsRow
+		var i BakeLog
 		if err := rows.Scan(
 			&i.ID,
-			&i.RecipeID,
-			&i.Name,
-			&i.PreferUnitCategory,
-			&i.Percentage,
-			&i.Dependency,
-			&i.Kind,
+			&i.BakeID,
+			&i.Text,
+			&i.Stage,
+			&i.Temperature,
+			&i.TemperatureScale,
+			&i.Minutes,
+			&i.LoggedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -265,25 +813,32 @@ func (q *Queries) ListRecipeIngredients(ctx context.Context, recipeID int64) ([]
 	return items, nil
 }
 
-const listRecipes = `-- name: ListRecipes :many
+const listBakePhotos = `-- name: ListBakePhotos :many
 SELECT
-  r.id,
-  r.name
-FROM recipes AS r
+  id, bake_id, path, added_at
+FROM bake_photos
+WHERE
+  bake_id = ?
 ORDER BY
-  r.id
+  added_at,
+  id
 `
 
-func (q *Queries) ListRecipes(ctx context.Context) ([]Recipe, error) {
-	rows, err := q.db.QueryContext(ctx, listRecipes)
+func (q *Queries) ListBakePhotos(ctx context.Context, bakeID int64) ([]BakePhoto, error) {
+	rows, err := q.db.QueryContext(ctx, listBakePhotos, bakeID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Recipe
+	var items []BakePhoto
 	for rows.Next() {
-		var i Recipe
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		var i BakePhoto
+		if err := rows.Scan(
+			&i.ID,
+			&i.BakeID,
+			&i.Path,
+			&i.AddedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -297,10 +852,271 @@ func (q *Queries) ListRecipes(ctx context.Context) ([]Recipe, error) {
 	return items, nil
 }
 
-const listRecipesByIngredient = `-- name: ListRecipesByIngredient :many
+const listBakes = `-- name: ListBakes :many
+SELECT
+  id, recipe_id, recipe_name, recipe_version, dependency, scale, notes, rating, started_at, finished_at
+FROM bakes
+ORDER BY
+  started_at DESC,
+  id DESC
+`
+
+func (q *Queries) ListBakes(ctx context.Context) ([]Bake, error) {
+	rows, err := q.db.QueryContext(ctx, listBakes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Bake
+	for rows.Next() {
+		var i Bake
+		if err := rows.Scan(
+			&i.ID,
+			&i.RecipeID,
+			&i.RecipeName,
+			&i.RecipeVersion,
+			&i.Dependency,
+			&i.Scale,
+			&i.Notes,
+			&i.Rating,
+			&i.StartedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFermentLogs = `-- name: ListFermentLogs :many
+SELECT
+  id, recipe_id, stage, temperature, temperature_scale, starter, flour, minutes, logged_at
+FROM ferment_logs
+ORDER BY
+  logged_at,
+  id
+`
+
+func (q *Queries) ListFermentLogs(ctx context.Context) ([]FermentLog, error) {
+	rows, err := q.db.QueryContext(ctx, listFermentLogs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FermentLog
+	for rows.Next() {
+		var i FermentLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.RecipeID,
+			&i.Stage,
+			&i.Temperature,
+			&i.TemperatureScale,
+			&i.Starter,
+			&i.Flour,
+			&i.Minutes,
+			&i.LoggedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecipeBakes = `-- name: ListRecipeBakes :many
 SELECT
+  id, recipe_id, recipe_name, recipe_version, dependency, scale, notes, rating, started_at, finished_at
+FROM bakes
+WHERE
+  recipe_id = ?
+ORDER BY
+  started_at DESC,
+  id DESC
+LIMIT ?
+`
+
+type ListRecipeBakesParams struct {
+	RecipeID *int64
+	Limit    int64
+}
+
+func (q *Queries) ListRecipeBakes(ctx context.Context, arg ListRecipeBakesParams) ([]Bake, error) {
+	rows, err := q.db.QueryContext(ctx, listRecipeBakes, arg.RecipeID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Bake
+	for rows.Next() {
+		var i Bake
+		if err := rows.Scan(
+			&i.ID,
+			&i.RecipeID,
+			&i.RecipeName,
+			&i.RecipeVersion,
+			&i.Dependency,
+			&i.Scale,
+			&i.Notes,
+			&i.Rating,
+			&i.StartedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecipeIngredients = `-- name: ListRecipeIngredients :many
+SELECT
+  ri.id,
+  ri.recipe_id,
+  i.name,
+  ri.prefer_unit_category,
+  ri.percentage,
+  ri.dependency,
+  ri.mode,
+  ri.amount,
+  ri.unit,
+  i.kind,
+  i.hydration,
+  i.density,
+  i.piece_weight,
+  s.name AS stage,
+  ri.position,
+  ri.group_name
+FROM recipe_ingredients AS ri
+JOIN ingredients AS i
+  ON i.id = ri.ingredient_id
+LEFT JOIN stages AS s
+  ON s.id = ri.stage_id
+WHERE
+  ri.recipe_id = ?
+ORDER BY
+  s.position IS NULL,
+  s.position,
+  ri.position,
+  ri.id
+`
+
+type ListRecipeIngredientsRow struct {
+	ID                 int64
+	RecipeID           int64
+	Name               string
+	PreferUnitCategory recipe.UnitCategory
+	Percentage         float64
+	Dependency         string
+	Mode               recipe.Mode
+	Amount             *float64
+	Unit               recipe.Unit
+	Kind               recipe.Kind
+	Hydration          *float64
+	Density            *float64
+	PieceWeight        *float64
+	Stage              *string
+	Position           int64
+	GroupName          *string
+}
+
+func (q *Queries) ListRecipeIngredients(ctx context.Context, recipeID int64) ([]ListRecipeIngredientsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRecipeIngredients, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRecipeIngredientsRow
+	for rows.Next() {
+		var i ListRecipeIngredientsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.RecipeID,
+			&i.Name,
+			&i.PreferUnitCategory,
+			&i.Percentage,
+			&i.Dependency,
+			&i.Mode,
+			&i.Amount,
+			&i.Unit,
+			&i.Kind,
+			&i.Hydration,
+			&i.Density,
+			&i.PieceWeight,
+			&i.Stage,
+			&i.Position,
+			&i.GroupName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecipes = `-- name: ListRecipes :many
+SELECT
+  r.id,
+  r.name,
+  r.notes
+FROM recipes AS r
+ORDER BY
+  r.id
+`
+
+func (q *Queries) ListRecipes(ctx context.Context) ([]Recipe, error) {
+	rows, err := q.db.QueryContext(ctx, listRecipes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Recipe
+	for rows.Next() {
+		var i Recipe
+		if err := rows.Scan(&i.ID, &i.Name, &i.Notes); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecipesByIngredient = `-- name: ListRecipesByIngredient :many
+SELECT DISTINCT
   r.id,
-  r.name
+  r.name,
+  r.notes
 FROM recipes AS r
 JOIN recipe_ingredients AS ri
   ON ri.recipe_id = r.id
@@ -321,7 +1137,7 @@ func (q *Queries) ListRecipesByIngredient(ctx context.Context, id int64) ([]Reci
 	var items []Recipe
 	for rows.Next() {
 		var i Recipe
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		if err := rows.Scan(&i.ID, &i.Name, &i.Notes); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -335,11 +1151,284 @@ func (q *Queries) ListRecipesByIngredient(ctx context.Context, id int64) ([]Reci
 	return items, nil
 }
 
+const listStages = `-- name: ListStages :many
+SELECT
+  s.id,
+  s.recipe_id,
+  s.name,
+  s.position
+FROM stages AS s
+WHERE
+  s.recipe_id = ?
+ORDER BY
+  s.position
+`
+
+func (q *Queries) ListStages(ctx context.Context, recipeID int64) ([]Stage, error) {
+	rows, err := q.db.QueryContext(ctx, listStages, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Stage
+	for rows.Next() {
+		var i Stage
+		if err := rows.Scan(
+			&i.ID,
+			&i.RecipeID,
+			&i.Name,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStepIngredients = `-- name: ListStepIngredients :many
+SELECT
+  si.step_id,
+  si.recipe_ingredient_id,
+  i.name,
+  st.name AS stage
+FROM step_ingredients AS si
+JOIN steps AS s
+  ON s.id = si.step_id
+JOIN recipe_ingredients AS ri
+  ON ri.id = si.recipe_ingredient_id
+JOIN ingredients AS i
+  ON i.id = ri.ingredient_id
+LEFT JOIN stages AS st
+  ON st.id = ri.stage_id
+WHERE
+  s.recipe_id = ?
+ORDER BY
+  si.step_id,
+  ri.position,
+  ri.id
+`
+
+type ListStepIngredientsRow struct {
+	StepID             int64
+	RecipeIngredientID int64
+	Name               string
+	Stage              *string
+}
+
+func (q *Queries) ListStepIngredients(ctx context.Context, recipeID int64) ([]ListStepIngredientsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStepIngredients, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListStepIngredientsRow
+	for rows.Next() {
+		var i ListStepIngredientsRow
+		if err := rows.Scan(
+			&i.StepID,
+			&i.RecipeIngredientID,
+			&i.Name,
+			&i.Stage,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSteps = `-- name: ListSteps :many
+SELECT
+  s.id,
+  s.recipe_id,
+  s.position,
+  s.text,
+  s.duration_min,
+  s.duration_max,
+  s.temperature,
+  s.temperature_scale
+FROM steps AS s
+WHERE
+  s.recipe_id = ?
+ORDER BY
+  s.position,
+  s.id
+`
+
+func (q *Queries) ListSteps(ctx context.Context, recipeID int64) ([]Step, error) {
+	rows, err := q.db.QueryContext(ctx, listSteps, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Step
+	for rows.Next() {
+		var i Step
+		if err := rows.Scan(
+			&i.ID,
+			&i.RecipeID,
+			&i.Position,
+			&i.Text,
+			&i.DurationMin,
+			&i.DurationMax,
+			&i.Temperature,
+			&i.TemperatureScale,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const nextRecipeIngredientPosition = `-- name: NextRecipeIngredientPosition :one
+SELECT
+  CAST(COALESCE(MAX(ri.position), 0) + 1 AS INTEGER) AS position
+FROM recipe_ingredients AS ri
+WHERE
+  ri.recipe_id = ?
+`
+
+func (q *Queries) NextRecipeIngredientPosition(ctx context.Context, recipeID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, nextRecipeIngredientPosition, recipeID)
+	var position int64
+	err := row.Scan(&position)
+	return position, err
+}
+
+const nextStepPosition = `-- name: NextStepPosition :one
+SELECT
+  CAST(COALESCE(MAX(s.position), 0) + 1 AS INTEGER) AS position
+FROM steps AS s
+WHERE
+  s.recipe_id = ?
+`
+
+func (q *Queries) NextStepPosition(ctx context.Context, recipeID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, nextStepPosition, recipeID)
+	var position int64
+	err := row.Scan(&position)
+	return position, err
+}
+
+const setIngredientDensity = `-- name: SetIngredientDensity :one
+UPDATE ingredients SET density = ?
+WHERE
+  id = ?
+RETURNING id, name, kind, hydration, density, piece_weight
+`
+
+type SetIngredientDensityParams struct {
+	Density *float64
+	ID      int64
+}
+
+func (q *Queries) SetIngredientDensity(ctx context.Context, arg SetIngredientDensityParams) (Ingredient, error) {
+	row := q.db.QueryRowContext(ctx, setIngredientDensity, arg.Density, arg.ID)
+	var i Ingredient
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Kind,
+		&i.Hydration,
+		&i.Density,
+		&i.PieceWeight,
+	)
+	return i, err
+}
+
+const setIngredientHydration = `-- name: SetIngredientHydration :one
+UPDATE ingredients SET hydration = ?
+WHERE
+  id = ?
+RETURNING id, name, kind, hydration, density, piece_weight
+`
+
+type SetIngredientHydrationParams struct {
+	Hydration *float64
+	ID        int64
+}
+
+func (q *Queries) SetIngredientHydration(ctx context.Context, arg SetIngredientHydrationParams) (Ingredient, error) {
+	row := q.db.QueryRowContext(ctx, setIngredientHydration, arg.Hydration, arg.ID)
+	var i Ingredient
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Kind,
+		&i.Hydration,
+		&i.Density,
+		&i.PieceWeight,
+	)
+	return i, err
+}
+
+const setIngredientPieceWeight = `-- name: SetIngredientPieceWeight :one
+UPDATE ingredients SET piece_weight = ?
+WHERE
+  id = ?
+RETURNING id, name, kind, hydration, density, piece_weight
+`
+
+type SetIngredientPieceWeightParams struct {
+	PieceWeight *float64
+	ID          int64
+}
+
+func (q *Queries) SetIngredientPieceWeight(ctx context.Context, arg SetIngredientPieceWeightParams) (Ingredient, error) {
+	row := q.db.QueryRowContext(ctx, setIngredientPieceWeight, arg.PieceWeight, arg.ID)
+	var i Ingredient
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Kind,
+		&i.Hydration,
+		&i.Density,
+		&i.PieceWeight,
+	)
+	return i, err
+}
+
+const setRecipeIngredientPosition = `-- name: SetRecipeIngredientPosition :exec
+UPDATE recipe_ingredients SET position = ?
+WHERE
+  id = ?
+`
+
+type SetRecipeIngredientPositionParams struct {
+	Position int64
+	ID       int64
+}
+
+func (q *Queries) SetRecipeIngredientPosition(ctx context.Context, arg SetRecipeIngredientPositionParams) error {
+	_, err := q.db.ExecContext(ctx, setRecipeIngredientPosition, arg.Position, arg.ID)
+	return err
+}
+
 const updateIngredient = `-- name: UpdateIngredient :one
 UPDATE ingredients SET name = ?, kind = ?
 WHERE
   id = ?
-RETURNING id, name, kind
+RETURNING id, name, kind, hydration, density, piece_weight
 `
 
 type UpdateIngredientParams struct {
@@ -351,41 +1440,53 @@ type UpdateIngredientParams struct {
 func (q *Queries) UpdateIngredient(ctx context.Context, arg UpdateIngredientParams) (Ingredient, error) {
 	row := q.db.QueryRowContext(ctx, updateIngredient, arg.Name, arg.Kind, arg.ID)
 	var i Ingredient
-	err := row.Scan(&i.ID, &i.Name, &i.Kind)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Kind,
+		&i.Hydration,
+		&i.Density,
+		&i.PieceWeight,
+	)
 	return i, err
 }
 
 const updateRecipe = `-- name: UpdateRecipe :one
-UPDATE recipes SET name = ?
+UPDATE recipes SET name = ?, notes = ?
 WHERE
   id = ?
-RETURNING id, name
+RETURNING id, name, notes
 `
 
 type UpdateRecipeParams struct {
-	Name string
-	ID   int64
+	Name  string
+	Notes string
+	ID    int64
 }
 
 func (q *Queries) UpdateRecipe(ctx context.Context, arg UpdateRecipeParams) (Recipe, error) {
-	row := q.db.QueryRowContext(ctx, updateRecipe, arg.Name, arg.ID)
+	row := q.db.QueryRowContext(ctx, updateRecipe, arg.Name, arg.Notes, arg.ID)
 	var i Recipe
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.Notes)
 	return i, err
 }
 
 const updateRecipeIngredient = `-- name: UpdateRecipeIngredient :one
-UPDATE recipe_ingredients SET prefer_unit_category = ?, percentage = ?, dependency = ?, ingredient_id = ?
+UPDATE recipe_ingredients SET prefer_unit_category = ?, percentage = ?, dependency = ?, mode = ?, amount = ?, unit = ?, ingredient_id = ?, group_name = ?
 WHERE
   id = ?
-RETURNING id, recipe_id, ingredient_id, prefer_unit_category, percentage, dependency
+RETURNING id, recipe_id, ingredient_id, prefer_unit_category, percentage, dependency, mode, amount, unit, stage_id, position, group_name
 `
 
 type UpdateRecipeIngredientParams struct {
 	PreferUnitCategory recipe.UnitCategory
 	Percentage         float64
 	Dependency         string
+	Mode               recipe.Mode
+	Amount             *float64
+	Unit               recipe.Unit
 	IngredientID       int64
+	GroupName          *string
 	ID                 int64
 }
 
@@ -394,7 +1495,11 @@ func (q *Queries) UpdateRecipeIngredient(ctx context.Context, arg UpdateRecipeIn
 		arg.PreferUnitCategory,
 		arg.Percentage,
 		arg.Dependency,
+		arg.Mode,
+		arg.Amount,
+		arg.Unit,
 		arg.IngredientID,
+		arg.GroupName,
 		arg.ID,
 	)
 	var i RecipeIngredient
@@ -405,6 +1510,51 @@ func (q *Queries) UpdateRecipeIngredient(ctx context.Context, arg UpdateRecipeIn
 		&i.PreferUnitCategory,
 		&i.Percentage,
 		&i.Dependency,
+		&i.Mode,
+		&i.Amount,
+		&i.Unit,
+		&i.StageID,
+		&i.Position,
+		&i.GroupName,
+	)
+	return i, err
+}
+
+const updateStep = `-- name: UpdateStep :one
+UPDATE steps SET text = ?, duration_min = ?, duration_max = ?, temperature = ?, temperature_scale = ?
+WHERE
+  id = ?
+RETURNING id, recipe_id, position, text, duration_min, duration_max, temperature, temperature_scale
+`
+
+type UpdateStepParams struct {
+	Text             string
+	DurationMin      *int64
+	DurationMax      *int64
+	Temperature      *float64
+	TemperatureScale recipe.TemperatureScale
+	ID               int64
+}
+
+func (q *Queries) UpdateStep(ctx context.Context, arg UpdateStepParams) (Step, error) {
+	row := q.db.QueryRowContext(ctx, updateStep,
+		arg.Text,
+		arg.DurationMin,
+		arg.DurationMax,
+		arg.Temperature,
+		arg.TemperatureScale,
+		arg.ID,
+	)
+	var i Step
+	err := row.Scan(
+		&i.ID,
+		&i.RecipeID,
+		&i.Position,
+		&i.Text,
+		&i.DurationMin,
+		&i.DurationMax,
+		&i.Temperature,
+		&i.TemperatureScale,
 	)
 	return i, err
 }
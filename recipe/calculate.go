@@ -0,0 +1,449 @@
+package recipe
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Dependency labels with a special meaning. Any other label refers to an
+// ingredient of the recipe or to a dependency supplied by the user.
+const (
+	// DependencyTotalFlour is the total amount of flour in the recipe. It's
+	// the base every baker's percentage is ultimately relative to.
+	DependencyTotalFlour = "total_flour"
+
+	// DependencyTotalWater is the sum of all water ingredients.
+	DependencyTotalWater = "total_water"
+
+	// DependencyTotalDough is the sum of all ingredients.
+	DependencyTotalDough = "total_dough"
+
+	// DependencyPerPiece is the amount of a single piece, e.g. one bun. It
+	// has to be supplied by the user.
+	DependencyPerPiece = "per_piece"
+)
+
+// ErrDependencyCycle is returned when ingredients depend on each other in a
+// cycle.
+var ErrDependencyCycle = errors.New("dependency cycle")
+
+// Calculate calculates the portion ingredients based on the given
+// dependencies.
+func (r Recipe) Calculate(dependencies []Dependency) ([]PortionIngredient, error) {
+	return Calculate(r.Ingredients, dependencies)
+}
+
+// Calculate calculates the portion ingredients based on the given templates
+// and dependencies.
+//
+// The dependency of a template is resolved in the following order:
+//
+//   - total_flour is the base of the recipe.
+//   - the name of another ingredient (case-insensitive) is that ingredient's
+//     amount, e.g. levain salt 2% of levain flour.
+//   - total_water and total_dough are the sum of the water ingredients and of
+//     all ingredients.
+//   - any other label, e.g. per_piece, is looked up in the dependencies.
+//
+// Ingredients form a graph which must not contain cycles. Every amount is a
+// linear function of total flour, so instead of total_flour the dependencies
+// may fix any derived amount, e.g. "total_dough 1800g" or "Water 700g", and
+// total flour is solved from it.
+func Calculate(templates []RecipeIngredient, dependencies []Dependency) ([]PortionIngredient, error) {
+	return CalculateScaled(templates, dependencies, 1)
+}
+
+// CalculateScaled calculates the portion ingredients based on the given
+// templates and dependencies. Fixed-amount ingredients are multiplied by scale
+// instead of being resolved against a dependency.
+func CalculateScaled(templates []RecipeIngredient, dependencies []Dependency, scale float64) ([]PortionIngredient, error) {
+	s := newSolver(templates, dependencies, scale)
+
+	totalFlour, found, err := s.totalFlour()
+	if err != nil {
+		return nil, err
+	}
+
+	var ingredients []PortionIngredient
+	for i, template := range templates {
+		t, err := s.ingredient(i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve dependency for %s: %w", template.Name, err)
+		}
+
+		if t.Coef != 0 && !found {
+			return nil, fmt.Errorf("failed to find dependency for %s: dependency not found: %s", template.Name, DependencyTotalFlour)
+		}
+
+		ingredients = append(ingredients, PortionIngredient{
+			Name:               template.Name,
+			Kind:               template.Kind,
+			Value:              t.value(totalFlour),
+			PreferUnitCategory: template.PreferUnitCategory,
+			Group:              template.Group,
+			Hydration:          template.Hydration,
+			Density:            template.Density,
+			PieceWeight:        template.PieceWeight,
+		})
+	}
+
+	return ingredients, nil
+}
+
+// term is an amount expressed as a linear function of total flour:
+//
+//	Coef * total_flour + Const
+//
+// Total flour is always in grams. Const may be in any unit as long as Coef is
+// zero, which is the case for fixed amounts and user-supplied dependencies.
+type term struct {
+	Coef  float64
+	Const Tuple
+}
+
+func (t term) scale(factor float64) term {
+	return term{
+		Coef:  t.Coef * factor,
+		Const: Tuple{Value: t.Const.Value * factor, Unit: t.Const.Unit},
+	}
+}
+
+func (t term) add(other term) (term, error) {
+	sum := term{Coef: t.Coef + other.Coef, Const: t.Const}
+	switch {
+	case other.Const.Value == 0:
+	case t.Const.Value == 0:
+		sum.Const = other.Const
+	case t.Const.Unit == other.Const.Unit:
+		sum.Const.Value += other.Const.Value
+	default:
+		return term{}, fmt.Errorf("cannot add %s and %s", t.Const.Format(), other.Const.Format())
+	}
+
+	if sum.Coef != 0 && sum.Const.Value != 0 && sum.Const.Unit != UnitGrams {
+		return term{}, fmt.Errorf("cannot add %s to total flour", sum.Const.Format())
+	}
+
+	return sum, nil
+}
+
+// of returns t, a term of the total flour of a stage, as a term of the total
+// flour the stage's flour is a term of. Const must be in grams unless Coef is
+// zero.
+func (t term) of(flour term) term {
+	if t.Coef == 0 {
+		return t
+	}
+	return term{
+		Coef:  t.Coef * flour.Coef,
+		Const: UnitGrams.Tuple(t.Coef*flour.Const.Value + t.Const.Value),
+	}
+}
+
+func (t term) value(totalFlour float64) Tuple {
+	if t.Coef == 0 {
+		return t.Const
+	}
+	return Tuple{Value: t.Coef*totalFlour + t.Const.Value, Unit: UnitGrams}
+}
+
+var zeroTerm = term{Const: Tuple{Unit: UnitGrams}}
+
+// solver resolves the dependency graph of a list of templates.
+type solver struct {
+	templates    []RecipeIngredient
+	dependencies []Dependency
+	scale        float64
+
+	memo     map[string]term
+	visiting map[string]bool
+	path     []string
+}
+
+func newSolver(templates []RecipeIngredient, dependencies []Dependency, scale float64) *solver {
+	return &solver{
+		templates:    templates,
+		dependencies: dependencies,
+		scale:        scale,
+		memo:         make(map[string]term),
+		visiting:     make(map[string]bool),
+	}
+}
+
+// visit memoizes fn under key and detects cycles. The name is used to report
+// the cycle.
+func (s *solver) visit(key, name string, fn func() (term, error)) (term, error) {
+	if t, ok := s.memo[key]; ok {
+		return t, nil
+	}
+
+	if s.visiting[key] {
+		return term{}, fmt.Errorf("%w: %s -> %s", ErrDependencyCycle, strings.Join(s.path, " -> "), name)
+	}
+
+	s.visiting[key] = true
+	s.path = append(s.path, name)
+	defer func() {
+		delete(s.visiting, key)
+		s.path = s.path[:len(s.path)-1]
+	}()
+
+	t, err := fn()
+	if err != nil {
+		return term{}, err
+	}
+
+	s.memo[key] = t
+	return t, nil
+}
+
+// index returns the index of the template with the given name or -1.
+func (s *solver) index(name string) int {
+	for i, template := range s.templates {
+		if strings.EqualFold(template.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// dependency returns the user-supplied dependency with the given label.
+func (s *solver) dependency(label string) (Dependency, bool) {
+	for _, dep := range s.dependencies {
+		if dep.Label == label {
+			return dep, true
+		}
+	}
+	return Dependency{}, false
+}
+
+// resolve returns the amount referred to by label.
+func (s *solver) resolve(label string) (term, error) {
+	if label == DependencyTotalFlour {
+		return term{Coef: 1, Const: zeroTerm.Const}, nil
+	}
+
+	if i := s.index(label); i >= 0 {
+		return s.ingredient(i)
+	}
+
+	return s.visit("label:"+label, label, func() (term, error) {
+		switch label {
+		case DependencyTotalWater:
+			return s.sum(func(t RecipeIngredient) bool { return t.Kind == KindWater })
+		case DependencyTotalDough:
+			return s.sum(func(RecipeIngredient) bool { return true })
+		}
+
+		if dep, ok := s.dependency(label); ok {
+			return term{Const: dep.Value}, nil
+		}
+
+		return term{}, fmt.Errorf("dependency not found: %s", label)
+	})
+}
+
+// ingredient returns the amount of the i-th template.
+func (s *solver) ingredient(i int) (term, error) {
+	template := s.templates[i]
+	return s.visit(fmt.Sprintf("ingredient:%d", i), template.Name, func() (term, error) {
+		if template.Mode == ModeAmount {
+			return term{Const: template.Amount}.scale(s.scale), nil
+		}
+
+		dep, err := s.resolve(template.Dependency)
+		if err != nil {
+			return term{}, err
+		}
+
+		return dep.scale(template.Percentage), nil
+	})
+}
+
+// weigh returns the amount of the i-th template in grams. It returns false if
+// the amount can't be expressed as a weight, e.g. 2pc without a known piece
+// weight.
+func (s *solver) weigh(i int) (term, bool, error) {
+	t, err := s.ingredient(i)
+	if err != nil {
+		return term{}, false, err
+	}
+
+	if t.Const.Value == 0 || t.Const.Unit == UnitGrams {
+		return t, true, nil
+	}
+
+	v, err := t.Const.ConvertWith(UnitGrams, s.templates[i].Properties())
+	if err != nil {
+		return term{}, false, nil
+	}
+
+	return term{Coef: t.Coef, Const: v}, true, nil
+}
+
+// sum adds up the weight of all templates matching fn.
+func (s *solver) sum(fn func(RecipeIngredient) bool) (term, error) {
+	total := zeroTerm
+	for i, template := range s.templates {
+		if !fn(template) {
+			continue
+		}
+
+		t, ok, err := s.weigh(i)
+		if err != nil {
+			return term{}, err
+		}
+		if !ok {
+			continue
+		}
+
+		if total, err = total.add(t); err != nil {
+			return term{}, err
+		}
+	}
+	return total, nil
+}
+
+// isDerived returns true if label refers to an amount derived from the
+// templates rather than one supplied by the user.
+func (s *solver) isDerived(label string) bool {
+	switch label {
+	case DependencyTotalWater, DependencyTotalDough:
+		return true
+	default:
+		return s.index(label) >= 0
+	}
+}
+
+// solve returns the total flour in grams for which the amount referred to by
+// dep equals dep's value.
+func (s *solver) solve(dep Dependency) (float64, error) {
+	t, props, err := s.amount(dep.Label)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve dependency %s: %w", dep.Label, err)
+	}
+
+	return solveTerm(dep, t, props)
+}
+
+// amount returns the amount referred to by label, in grams if it's an
+// ingredient, and the properties used to convert a value of it to grams.
+func (s *solver) amount(label string) (term, Properties, error) {
+	i := s.index(label)
+	if i < 0 {
+		t, err := s.resolve(label)
+		return t, Properties{Kind: KindOther}, err
+	}
+
+	t, ok, err := s.weigh(i)
+	if err == nil && !ok {
+		err = fmt.Errorf("%s has no weight", s.templates[i].Name)
+	}
+	return t, s.templates[i].Properties(), err
+}
+
+// solveTerm returns the total flour in grams for which t, the amount referred
+// to by dep, equals dep's value.
+func solveTerm(dep Dependency, t term, props Properties) (float64, error) {
+	if t.Coef == 0 {
+		return 0, fmt.Errorf("dependency %s does not depend on %s", dep.Label, DependencyTotalFlour)
+	}
+
+	value, err := dep.Value.ConvertWith(UnitGrams, props)
+	if err != nil {
+		return 0, fmt.Errorf("dependency %s must be a weight: %w", dep.Label, err)
+	}
+
+	totalFlour := (value.Value - t.Const.Value) / t.Coef
+	if totalFlour <= 0 {
+		return 0, fmt.Errorf("dependency %s %s is too small", dep.Label, dep.Value.Format())
+	}
+
+	return totalFlour, nil
+}
+
+// totalFlour returns the total flour in grams fixed by the dependencies.
+// Either total_flour is given directly or it's solved from a single derived
+// amount. It returns false if no dependency fixes total flour.
+func (s *solver) totalFlour() (float64, bool, error) {
+	var anchor *Dependency
+	for i, dep := range s.dependencies {
+		if dep.Label != DependencyTotalFlour && !s.isDerived(dep.Label) {
+			continue
+		}
+		if anchor != nil {
+			return 0, false, fmt.Errorf("conflicting dependencies: %s and %s", anchor.Label, dep.Label)
+		}
+		anchor = &s.dependencies[i]
+	}
+
+	if anchor == nil {
+		return 0, false, nil
+	}
+
+	if anchor.Label == DependencyTotalFlour {
+		value, err := anchor.Value.ConvertIngredient(UnitGrams, KindFlour)
+		if err != nil {
+			return 0, false, fmt.Errorf("dependency %s must be a weight: %w", anchor.Label, err)
+		}
+		return value.Value, true, nil
+	}
+
+	totalFlour, err := s.solve(*anchor)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return totalFlour, true, nil
+}
+
+// Bound is the total flour allowed by a single limit.
+type Bound struct {
+	Limit      Dependency
+	TotalFlour Tuple
+}
+
+// Limit scales a recipe to the available amount of one or more of its
+// ingredients. Each limit is resolved like a dependency of Calculate, e.g.
+// "Sourdough Starter 230g" or "total_flour 1kg", and the total flour of the
+// final dough it allows is solved. A limit on an ingredient of an earlier
+// stage, e.g. the starter of a levain, is solved through the stage's use in
+// the final dough, see CalculateStages. It returns the bound of every limit
+// and the index of the binding one, the limit allowing the least total flour.
+func Limit(templates []RecipeIngredient, limits []Dependency) ([]Bound, int, error) {
+	if len(limits) == 0 {
+		return nil, -1, errors.New("no limits")
+	}
+
+	s := newStageSolver(templates)
+
+	bounds := make([]Bound, 0, len(limits))
+	binding := -1
+	for _, limit := range limits {
+		var (
+			totalFlour float64
+			err        error
+		)
+		if limit.Label == DependencyTotalFlour {
+			var v Tuple
+			v, err = limit.Value.ConvertIngredient(UnitGrams, KindFlour)
+			totalFlour = v.Value
+		} else {
+			totalFlour, err = s.solve(limit)
+		}
+		if err != nil {
+			return nil, -1, fmt.Errorf("invalid limit %s: %w", limit.Label, err)
+		}
+
+		bounds = append(bounds, Bound{
+			Limit:      limit,
+			TotalFlour: UnitGrams.Tuple(totalFlour),
+		})
+		if binding < 0 || totalFlour < bounds[binding].TotalFlour.Value {
+			binding = len(bounds) - 1
+		}
+	}
+
+	return bounds, binding, nil
+}
@@ -0,0 +1,143 @@
+package recipe
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestCalculateScaled(t *testing.T) {
+	templates := []RecipeIngredient{
+		{Name: "Flour", Kind: KindFlour, Mode: ModePercentage, Percentage: 1, Dependency: "total_flour"},
+		{Name: "Water", Kind: KindWater, Mode: ModePercentage, Percentage: 1.05, Dependency: "total_flour"},
+		{Name: "Egg", Kind: KindEgg, Mode: ModeAmount, Amount: Tuple{Value: 2, Unit: UnitPieces}},
+	}
+	deps := []Dependency{{Label: "total_flour", Value: Tuple{Value: 500, Unit: UnitGrams}}}
+
+	got, err := CalculateScaled(templates, deps, 1.5)
+	if err != nil {
+		t.Fatalf("CalculateScaled() error = %v", err)
+	}
+
+	want := []Tuple{
+		{Value: 500, Unit: UnitGrams},
+		{Value: 525, Unit: UnitGrams},
+		{Value: 3, Unit: UnitPieces},
+	}
+	for i, w := range want {
+		if got[i].Value != w {
+			t.Errorf("CalculateScaled() %s got = %v, want %v", got[i].Name, got[i].Value, w)
+		}
+	}
+}
+
+func TestCalculateDependencies(t *testing.T) {
+	levain := []RecipeIngredient{
+		{Name: "Bread Flour", Kind: KindFlour, Percentage: 0.8, Dependency: "total_flour"},
+		{Name: "Levain Flour", Kind: KindFlour, Percentage: 0.2, Dependency: "total_flour"},
+		{Name: "Levain Salt", Kind: KindSalt, Percentage: 0.02, Dependency: "levain flour"},
+		{Name: "Water", Kind: KindWater, Percentage: 0.7, Dependency: "total_flour"},
+		{Name: "Oil", Kind: KindOil, Percentage: 0.1, Dependency: "total_water"},
+		{Name: "Seeds", Percentage: 0.5, Dependency: "per_piece"},
+	}
+
+	tests := []struct {
+		name    string
+		deps    []Dependency
+		want    []float64
+		wantErr error
+	}{
+		{
+			name: "total flour",
+			deps: []Dependency{
+				{Label: "total_flour", Value: Tuple{Value: 1000, Unit: UnitGrams}},
+				{Label: "per_piece", Value: Tuple{Value: 10, Unit: UnitGrams}},
+			},
+			want: []float64{800, 200, 4, 700, 70, 5},
+		},
+		{
+			name: "back-solve from total dough",
+			deps: []Dependency{
+				{Label: "total_dough", Value: Tuple{Value: 1779, Unit: UnitGrams}},
+				{Label: "per_piece", Value: Tuple{Value: 10, Unit: UnitGrams}},
+			},
+			want: []float64{800, 200, 4, 700, 70, 5},
+		},
+		{
+			name: "back-solve from ingredient",
+			deps: []Dependency{
+				{Label: "water", Value: Tuple{Value: 350, Unit: UnitGrams}},
+				{Label: "per_piece", Value: Tuple{Value: 10, Unit: UnitGrams}},
+			},
+			want: []float64{400, 100, 2, 350, 35, 5},
+		},
+		{
+			name: "conflicting dependencies",
+			deps: []Dependency{
+				{Label: "total_flour", Value: Tuple{Value: 1000, Unit: UnitGrams}},
+				{Label: "total_dough", Value: Tuple{Value: 1000, Unit: UnitGrams}},
+			},
+			wantErr: errors.New("conflicting"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Calculate(levain, tt.deps)
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("Calculate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for i, w := range tt.want {
+				if math.Abs(got[i].Value.Value-w) > 1e-9 {
+					t.Errorf("Calculate() %s got = %v, want %v", got[i].Name, got[i].Value.Value, w)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateCycle(t *testing.T) {
+	templates := []RecipeIngredient{
+		{Name: "A", Percentage: 0.5, Dependency: "B"},
+		{Name: "B", Percentage: 0.5, Dependency: "A"},
+	}
+	deps := []Dependency{{Label: "total_flour", Value: Tuple{Value: 1000, Unit: UnitGrams}}}
+
+	_, err := Calculate(templates, deps)
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("Calculate() error = %v, want %v", err, ErrDependencyCycle)
+	}
+}
+
+func TestLimit(t *testing.T) {
+	templates := []RecipeIngredient{
+		{Name: "White Flour", Kind: KindFlour, Percentage: 0.875, Dependency: "total_flour"},
+		{Name: "Whole Grain Flour", Kind: KindFlour, Percentage: 0.125, Dependency: "total_flour"},
+		{Name: "Sourdough Starter", Kind: KindSourdough, Percentage: 0.15, Dependency: "total_flour"},
+		{Name: "Water", Kind: KindWater, Percentage: 0.77, Dependency: "total_flour"},
+	}
+	limits := []Dependency{
+		{Label: "Sourdough Starter", Value: Tuple{Value: 230, Unit: UnitGrams}},
+		{Label: "whole grain flour", Value: Tuple{Value: 1000, Unit: UnitGrams}},
+	}
+
+	bounds, binding, err := Limit(templates, limits)
+	if err != nil {
+		t.Fatalf("Limit() error = %v", err)
+	}
+
+	if binding != 0 {
+		t.Errorf("Limit() binding = %d, want 0", binding)
+	}
+
+	want := []float64{230 / 0.15, 8000}
+	for i, w := range want {
+		if math.Abs(bounds[i].TotalFlour.Value-w) > 1e-9 {
+			t.Errorf("Limit() %s got = %v, want %v", bounds[i].Limit.Label, bounds[i].TotalFlour.Value, w)
+		}
+	}
+
+	if _, _, err := Limit(templates, []Dependency{{Label: "Salt", Value: Tuple{Value: 10, Unit: UnitGrams}}}); err == nil {
+		t.Errorf("Limit() expected error for unknown ingredient")
+	}
+}
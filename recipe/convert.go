@@ -0,0 +1,120 @@
+package recipe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Conversion is a single step converting one unit to another.
+type Conversion struct {
+	From   Unit
+	To     Unit
+	Factor float64
+}
+
+func (c Conversion) String() string {
+	return fmt.Sprintf("1%s = %g%s", c.From, c.Factor, c.To)
+}
+
+// Imperial and US customary units in grams and millilitres.
+const (
+	gramsPerOunce            = 28.349523125
+	millilitresPerFluidOunce = 29.5735295625
+	millilitresPerTeaspoon   = millilitresPerFluidOunce / 6
+)
+
+// conversions are the edges of the unit conversion graph. Each conversion
+// can also be used in reverse, so every unit only needs a single path to
+// another unit of the same dimension. Factors are exact by definition of the
+// units, e.g. a US cup is 8 fl oz and a teaspoon is 1/6 fl oz.
+var conversions = []Conversion{
+	// Weight
+	{UnitKilos, UnitGrams, 1000},
+	{UnitOunces, UnitGrams, gramsPerOunce},
+	{UnitPounds, UnitOunces, 16},
+
+	// Volume
+	{UnitLitres, UnitMillilitres, 1000},
+	{UnitFluidOunces, UnitMillilitres, millilitresPerFluidOunce},
+	{UnitQuarts, UnitFluidOunces, 32},
+	{UnitCups, UnitFluidOunces, 8},
+	{UnitTablespoons, UnitTeaspoons, 3},
+	{UnitTeaspoons, UnitMillilitres, millilitresPerTeaspoon},
+	{UnitTeaspoons, UnitPinches, 3},
+	{UnitHandfuls, UnitCups, 0.5},
+}
+
+// conversionGraph maps each unit to the conversions leaving it, including
+// the reverse of every conversion.
+var conversionGraph = func() map[Unit][]Conversion {
+	graph := map[Unit][]Conversion{}
+	for _, c := range conversions {
+		graph[c.From] = append(graph[c.From], c)
+		graph[c.To] = append(graph[c.To], Conversion{From: c.To, To: c.From, Factor: 1 / c.Factor})
+	}
+	return graph
+}()
+
+// ConversionPath returns the shortest sequence of conversions from one unit
+// to another. It's empty if the units are the same.
+func ConversionPath(from Unit, to Unit) ([]Conversion, error) {
+	if from == to {
+		return nil, nil
+	}
+
+	// Breadth-first search, remembering the conversion used to reach each
+	// unit.
+	via := map[Unit]Conversion{from: {}}
+	queue := []Unit{from}
+	for len(queue) > 0 {
+		unit := queue[0]
+		queue = queue[1:]
+
+		for _, c := range conversionGraph[unit] {
+			if _, seen := via[c.To]; seen {
+				continue
+			}
+			via[c.To] = c
+
+			if c.To != to {
+				queue = append(queue, c.To)
+				continue
+			}
+
+			var path []Conversion
+			for u := to; u != from; u = via[u].From {
+				path = append([]Conversion{via[u]}, path...)
+			}
+			return path, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported conversion from %s to %s", from, to)
+}
+
+// FormatConversionPath formats a conversion path for debugging, e.g.
+// "cup -> fl oz -> ml".
+func FormatConversionPath(path []Conversion) string {
+	if len(path) == 0 {
+		return ""
+	}
+
+	units := []string{path[0].From.String()}
+	for _, c := range path {
+		units = append(units, c.To.String())
+	}
+	return strings.Join(units, " -> ")
+}
+
+// Convert converts the given value from one unit to another.
+func Convert(value float64, from Unit, to Unit) (float64, error) {
+	path, err := ConversionPath(from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, c := range path {
+		value *= c.Factor
+	}
+	return value, nil
+}
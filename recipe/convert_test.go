@@ -0,0 +1,78 @@
+package recipe
+
+import (
+	"math"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		value   float64
+		from    Unit
+		to      Unit
+		want    float64
+		wantErr bool
+	}{
+		{value: 1, from: UnitCups, to: UnitMillilitres, want: 236.5882365},
+		{value: 1, from: UnitTablespoons, to: UnitMillilitres, want: 14.78676478125},
+		{value: 2, from: UnitHandfuls, to: UnitLitres, want: 0.2365882365},
+		{value: 3, from: UnitPinches, to: UnitTeaspoons, want: 1},
+		{value: 1, from: UnitPounds, to: UnitKilos, want: 0.45359237},
+		{value: 1, from: UnitQuarts, to: UnitCups, want: 4},
+		{value: 1, from: UnitKilos, to: UnitMillilitres, wantErr: true},
+		{value: 1, from: UnitPieces, to: UnitGrams, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			got, err := Convert(tt.value, tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Convert() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConversionPath(t *testing.T) {
+	path, err := ConversionPath(UnitCups, UnitLitres)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := FormatConversionPath(path), "cup -> fl oz -> ml -> l"; got != want {
+		t.Errorf("ConversionPath() got = %q, want %q", got, want)
+	}
+
+	path, err = ConversionPath(UnitGrams, UnitGrams)
+	if err != nil || len(path) != 0 {
+		t.Errorf("ConversionPath() got = %v, %v, want empty path", path, err)
+	}
+}
+
+func TestConvertIngredientSpoons(t *testing.T) {
+	tests := []struct {
+		name  string
+		value float64
+		from  Unit
+		to    Unit
+		kind  Kind
+		want  float64
+	}{
+		{name: "cup of flour to grams", value: 1, from: UnitCups, to: UnitGrams, kind: KindFlour, want: 156.14823609},
+		{name: "grams of salt to tsp", value: 10.647, from: UnitGrams, to: UnitTeaspoons, kind: KindSalt, want: 1},
+		{name: "kg of water to ml", value: 1, from: UnitKilos, to: UnitMillilitres, kind: KindWater, want: 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertIngredient(tt.value, tt.from, tt.to, tt.kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-tt.want) > 1e-3 {
+				t.Errorf("ConvertIngredient() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
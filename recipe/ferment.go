@@ -0,0 +1,292 @@
+package recipe
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// FlourType is the type of flour of a dough. Whole grain flours carry more
+// yeast, bacteria and nutrients than white flour and ferment faster.
+type FlourType string
+
+const (
+	FlourWhite      FlourType = "white"
+	FlourWholeWheat FlourType = "whole-wheat"
+	FlourRye        FlourType = "rye"
+)
+
+// FlourTypes are the known flour types.
+var FlourTypes = []FlourType{FlourWhite, FlourWholeWheat, FlourRye}
+
+// flourTypeFactor is the fermentation time of a flour type relative to
+// white flour.
+var flourTypeFactor = map[FlourType]float64{
+	FlourWhite:      1,
+	FlourWholeWheat: 0.85,
+	FlourRye:        0.7,
+}
+
+// ParseFlourType parses a flour type, e.g. "whole-wheat" or "rye".
+func ParseFlourType(value string) (FlourType, error) {
+	t := FlourType(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(value)), " ", "-"))
+	if _, ok := flourTypeFactor[t]; !ok {
+		return "", fmt.Errorf("invalid flour type %s, want white, whole-wheat or rye", value)
+	}
+	return t, nil
+}
+
+// GuessFlourType guesses the type of a flour from its name, e.g. rye for
+// "Dark Rye Flour" and whole-wheat for "Whole Grain Flour". Other flours are
+// white.
+func GuessFlourType(name string) FlourType {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == ' ' || r == '-'
+	})
+	for _, word := range words {
+		if word == "rye" || word == "pumpernickel" {
+			return FlourRye
+		}
+	}
+	for _, word := range words {
+		switch word {
+		case "whole", "wholemeal", "wholewheat", "wholegrain", "graham", "spelt", "einkorn", "emmer":
+			return FlourWholeWheat
+		}
+	}
+	return FlourWhite
+}
+
+// Fermentation are the conditions of a dough which determine how fast it
+// ferments.
+type Fermentation struct {
+	// Temperature is the temperature of the dough.
+	Temperature Temperature
+
+	// Starter is the weight of the starter as a ratio of the flour, the
+	// baker's percentage of starter, e.g. 0.2.
+	Starter float64
+
+	Flour FlourType
+}
+
+// FermentationOf returns the starter ratio and the main flour type of a
+// dough. The starter is the sourdough ingredients and the flour type is
+// that of most of the flour, guessed from the names.
+func FermentationOf(ingredients []PortionIngredient, t Temperature) Fermentation {
+	var starter, flour float64
+	flourTypes := map[FlourType]float64{}
+	for _, ingredient := range ingredients {
+		v, err := ingredient.Value.ConvertWith(UnitGrams, ingredient.Properties())
+		if err != nil {
+			continue
+		}
+
+		switch ingredient.Kind {
+		case KindSourdough:
+			starter += v.Value
+		case KindFlour:
+			flour += v.Value
+			flourTypes[GuessFlourType(ingredient.Name)] += v.Value
+		}
+	}
+
+	f := Fermentation{Temperature: t, Starter: ratio(starter, flour), Flour: FlourWhite}
+	for _, flourType := range FlourTypes {
+		if flourTypes[flourType] > flourTypes[f.Flour] {
+			f.Flour = flourType
+		}
+	}
+	return f
+}
+
+// FermentStage is a stage of fermentation.
+type FermentStage string
+
+const (
+	FermentBulk  FermentStage = "bulk"
+	FermentProof FermentStage = "proof"
+)
+
+// Implement from SQL Driver Valuer interface
+func (s FermentStage) Value() (driver.Value, error) {
+	if s == "" {
+		return nil, nil
+	}
+	return string(s), nil
+}
+
+// Implement from SQL Scanner interface
+func (s *FermentStage) Scan(src any) error {
+	if src == nil {
+		*s = ""
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		*s = FermentStage(src)
+	default:
+		return fmt.Errorf("invalid fermentation stage: %v, %T", src, src)
+	}
+
+	return nil
+}
+
+// FermentModel predicts fermentation times. The time of a stage at the
+// reference conditions is scaled by Q10 for every 10°C the dough is colder
+// than the reference, and by the ratio of the reference starter to the
+// starter to the power of StarterExponent. Whole grain flours shorten the
+// time by a fixed factor.
+//
+// A model is calibrated to the bakes of a baker by Calibrate.
+type FermentModel struct {
+	Reference Fermentation
+	Bulk      time.Duration
+	Proof     time.Duration
+
+	// Q10 is how many times slower fermentation is at 10°C colder.
+	Q10 float64
+
+	// StarterExponent is how strongly the starter ratio affects the
+	// time, 0.5 means four times as much starter halves the time.
+	StarterExponent float64
+
+	// Observations is the number of observations the model is
+	// calibrated by.
+	Observations int
+}
+
+// DefaultFermentModel is a white flour dough with 20% starter, which bulk
+// ferments in 5 hours and proofs in 2 hours at 24°C.
+var DefaultFermentModel = FermentModel{
+	Reference: Fermentation{
+		Temperature: Temperature{Value: 24, Scale: Celsius},
+		Starter:     0.2,
+		Flour:       FlourWhite,
+	},
+	Bulk:            5 * time.Hour,
+	Proof:           2 * time.Hour,
+	Q10:             2.5,
+	StarterExponent: 0.5,
+}
+
+// factor returns the fermentation time of f relative to the reference.
+func (m FermentModel) factor(f Fermentation) float64 {
+	factor := math.Pow(m.Q10, (m.Reference.Temperature.Celsius()-f.Temperature.Celsius())/10)
+	if f.Starter > 0 && m.Reference.Starter > 0 {
+		factor *= math.Pow(m.Reference.Starter/f.Starter, m.StarterExponent)
+	}
+	return factor * flourFactor(f.Flour) / flourFactor(m.Reference.Flour)
+}
+
+func flourFactor(t FlourType) float64 {
+	if factor, ok := flourTypeFactor[t]; ok {
+		return factor
+	}
+	return 1
+}
+
+// Estimate returns the predicted time of a fermentation stage, rounded to
+// 5 minutes. The starter ratio must be positive.
+func (m FermentModel) Estimate(stage FermentStage, f Fermentation) (time.Duration, error) {
+	if f.Starter <= 0 {
+		return 0, fmt.Errorf("invalid starter %.1f%%, must be more than 0%%", f.Starter*100)
+	}
+
+	reference := m.Bulk
+	if stage == FermentProof {
+		reference = m.Proof
+	}
+	d := time.Duration(float64(reference) * m.factor(f))
+	return d.Round(5 * time.Minute), nil
+}
+
+// FermentObservation is the observed time of a fermentation stage, e.g. from
+// a bake log.
+type FermentObservation struct {
+	Stage        FermentStage
+	Fermentation Fermentation
+	Duration     time.Duration
+}
+
+// Calibrate returns the model fitted to observations. The reference time of
+// each stage is the geometric mean of the observed times scaled to the
+// reference conditions, and stays unchanged without observations of the
+// stage. With bulk observations at least 4°C apart Q10 is fitted as well,
+// within 1.5 to 4.
+func (m FermentModel) Calibrate(observations []FermentObservation) FermentModel {
+	var valid []FermentObservation
+	for _, o := range observations {
+		if o.Duration > 0 && o.Fermentation.Starter > 0 {
+			valid = append(valid, o)
+		}
+	}
+	m.Observations = len(valid)
+
+	if q10, ok := fitQ10(m, valid); ok {
+		m.Q10 = q10
+	}
+
+	var (
+		sums   = map[FermentStage]float64{}
+		counts = map[FermentStage]int{}
+	)
+	for _, o := range valid {
+		sums[o.Stage] += math.Log(float64(o.Duration) / m.factor(o.Fermentation))
+		counts[o.Stage]++
+	}
+	if n := counts[FermentBulk]; n > 0 {
+		m.Bulk = time.Duration(math.Exp(sums[FermentBulk] / float64(n)))
+	}
+	if n := counts[FermentProof]; n > 0 {
+		m.Proof = time.Duration(math.Exp(sums[FermentProof] / float64(n)))
+	}
+	return m
+}
+
+// fitQ10 fits Q10 to the bulk observations by a least squares regression of
+// the logarithm of the time, with the starter and flour type scaled to the
+// reference, on the temperature.
+func fitQ10(m FermentModel, observations []FermentObservation) (float64, bool) {
+	var xs, ys []float64
+	for _, o := range observations {
+		if o.Stage != FermentBulk {
+			continue
+		}
+
+		// Remove the effect of the starter and flour type, leaving
+		// that of the temperature.
+		f := o.Fermentation
+		f.Temperature = m.Reference.Temperature
+		xs = append(xs, o.Fermentation.Temperature.Celsius())
+		ys = append(ys, math.Log(float64(o.Duration)/m.factor(f)))
+	}
+	if len(xs) < 2 {
+		return 0, false
+	}
+
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(len(xs))
+	meanY /= float64(len(ys))
+
+	var sxx, sxy float64
+	minX, maxX := xs[0], xs[0]
+	for i := range xs {
+		sxx += (xs[i] - meanX) * (xs[i] - meanX)
+		sxy += (xs[i] - meanX) * (ys[i] - meanY)
+		minX, maxX = math.Min(minX, xs[i]), math.Max(maxX, xs[i])
+	}
+	if maxX-minX < 4 {
+		return 0, false
+	}
+
+	q10 := math.Exp(-10 * sxy / sxx)
+	return math.Max(1.5, math.Min(4, q10)), true
+}
@@ -0,0 +1,102 @@
+package recipe
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFermentModelEstimate(t *testing.T) {
+	celsius := func(v float64) Temperature { return Temperature{Value: v, Scale: Celsius} }
+
+	tests := []struct {
+		name  string
+		stage FermentStage
+		f     Fermentation
+		want  time.Duration
+	}{
+		{"reference", FermentBulk, Fermentation{celsius(24), 0.2, FlourWhite}, 5 * time.Hour},
+		{"reference proof", FermentProof, Fermentation{celsius(24), 0.2, FlourWhite}, 2 * time.Hour},
+		{"10C warmer", FermentBulk, Fermentation{celsius(34), 0.2, FlourWhite}, 2 * time.Hour},
+		{"colder", FermentBulk, Fermentation{celsius(21), 0.2, FlourWhite}, 6*time.Hour + 35*time.Minute},
+		{"fahrenheit", FermentBulk, Fermentation{Temperature{Value: 75.2, Scale: Fahrenheit}, 0.2, FlourWhite}, 5 * time.Hour},
+		{"less starter", FermentBulk, Fermentation{celsius(24), 0.05, FlourWhite}, 10 * time.Hour},
+		{"rye", FermentBulk, Fermentation{celsius(24), 0.2, FlourRye}, 3*time.Hour + 30*time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DefaultFermentModel.Estimate(tt.stage, tt.f)
+			if err != nil {
+				t.Fatalf("Estimate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Estimate() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := DefaultFermentModel.Estimate(FermentBulk, Fermentation{Temperature: celsius(24)}); err == nil {
+		t.Error("Estimate() without starter expected an error")
+	}
+}
+
+func TestFermentModelCalibrate(t *testing.T) {
+	celsius := func(v float64) Temperature { return Temperature{Value: v, Scale: Celsius} }
+
+	// A baker whose bulk takes 6h at 24°C, and 4h at 28°C: Q10 is
+	// (6/4)^(10/4).
+	observations := []FermentObservation{
+		{FermentBulk, Fermentation{celsius(24), 0.2, FlourWhite}, 6 * time.Hour},
+		{FermentBulk, Fermentation{celsius(28), 0.2, FlourWhite}, 4 * time.Hour},
+		{FermentProof, Fermentation{celsius(24), 0.2, FlourWhite}, 3 * time.Hour},
+		{FermentProof, Fermentation{celsius(24), 0.2, FlourWhite}, 0},
+	}
+
+	m := DefaultFermentModel.Calibrate(observations)
+	if m.Observations != 3 {
+		t.Errorf("Calibrate() observations got = %d, want 3", m.Observations)
+	}
+	if want := math.Pow(1.5, 2.5); math.Abs(m.Q10-want) > 1e-9 {
+		t.Errorf("Calibrate() Q10 got = %v, want %v", m.Q10, want)
+	}
+	if got, _ := m.Estimate(FermentBulk, Fermentation{celsius(24), 0.2, FlourWhite}); got != 6*time.Hour {
+		t.Errorf("calibrated bulk got = %v, want 6h", got)
+	}
+	if got, _ := m.Estimate(FermentProof, Fermentation{celsius(24), 0.2, FlourWhite}); got != 3*time.Hour {
+		t.Errorf("calibrated proof got = %v, want 3h", got)
+	}
+
+	// A single observation only shifts the reference time.
+	m = DefaultFermentModel.Calibrate(observations[1:2])
+	if m.Q10 != DefaultFermentModel.Q10 || m.Proof != DefaultFermentModel.Proof {
+		t.Errorf("Calibrate() changed Q10 or proof: %+v", m)
+	}
+	if got, _ := m.Estimate(FermentBulk, Fermentation{celsius(28), 0.2, FlourWhite}); got != 4*time.Hour {
+		t.Errorf("calibrated bulk got = %v, want 4h", got)
+	}
+}
+
+func TestFermentationOf(t *testing.T) {
+	ingredients := []PortionIngredient{
+		{Name: "Bread Flour", Kind: KindFlour, Value: UnitGrams.Tuple(700)},
+		{Name: "Whole Wheat Flour", Kind: KindFlour, Value: UnitGrams.Tuple(300)},
+		{Name: "Water", Kind: KindWater, Value: UnitGrams.Tuple(750)},
+		{Name: "Levain", Kind: KindSourdough, Value: UnitGrams.Tuple(200)},
+	}
+	f := FermentationOf(ingredients, Temperature{Value: 25, Scale: Celsius})
+	if f.Starter != 0.2 || f.Flour != FlourWhite {
+		t.Errorf("FermentationOf() got = %+v", f)
+	}
+
+	for name, want := range map[string]FlourType{
+		"Bread Flour":       FlourWhite,
+		"Whole Grain Flour": FlourWholeWheat,
+		"Dark Rye Flour":    FlourRye,
+		"Whole-Rye Flour":   FlourRye,
+		"Spelt":             FlourWholeWheat,
+	} {
+		if got := GuessFlourType(name); got != want {
+			t.Errorf("GuessFlourType(%s) got = %s, want %s", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package recipe
+
+import "strings"
+
+// PortionGroup is the calculated ingredients of a group, e.g. the topping of
+// a focaccia. Ingredients without a group are in groups with an empty name.
+type PortionGroup struct {
+	Name        string
+	Ingredients []PortionIngredient
+}
+
+// Weight returns the sum of the ingredients of the group in grams. Amounts
+// which can't be converted to a weight are left out.
+func (g PortionGroup) Weight() float64 {
+	return Summarize(g.Ingredients).Dough
+}
+
+// GroupPortions groups ingredients in the order they are given. The
+// ingredients of a named group are gathered where the group first appears,
+// so every group is listed once, while consecutive ingredients without a
+// group stay where they are.
+func GroupPortions(ingredients []PortionIngredient) []PortionGroup {
+	var (
+		groups []PortionGroup
+		index  = map[string]int{}
+	)
+	for _, ingredient := range ingredients {
+		if ingredient.Group != "" {
+			key := strings.ToLower(ingredient.Group)
+			if i, ok := index[key]; ok {
+				groups[i].Ingredients = append(groups[i].Ingredients, ingredient)
+				continue
+			}
+			index[key] = len(groups)
+		} else if n := len(groups); n > 0 && groups[n-1].Name == "" {
+			groups[n-1].Ingredients = append(groups[n-1].Ingredients, ingredient)
+			continue
+		}
+
+		groups = append(groups, PortionGroup{
+			Name:        ingredient.Group,
+			Ingredients: []PortionIngredient{ingredient},
+		})
+	}
+	return groups
+}
+
+// IsGrouped returns true if any ingredient belongs to a group.
+func IsGrouped(ingredients []PortionIngredient) bool {
+	for _, ingredient := range ingredients {
+		if ingredient.Group != "" {
+			return true
+		}
+	}
+	return false
+}
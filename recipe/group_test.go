@@ -0,0 +1,49 @@
+package recipe
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestGroupPortions(t *testing.T) {
+	portion := func(name, group string, grams float64) PortionIngredient {
+		return PortionIngredient{Name: name, Group: group, Value: UnitGrams.Tuple(grams)}
+	}
+	ingredients := []PortionIngredient{
+		portion("Flour", "", 500),
+		portion("Water", "", 400),
+		portion("Olive Oil", "Topping", 30),
+		portion("Salt", "", 10),
+		portion("Rosemary", "topping", 5),
+		portion("Cheese", "Filling", 100),
+	}
+
+	groups := GroupPortions(ingredients)
+
+	var got [][]string
+	for _, group := range groups {
+		names := []string{group.Name}
+		for _, ingredient := range group.Ingredients {
+			names = append(names, ingredient.Name)
+		}
+		got = append(got, names)
+	}
+	want := [][]string{
+		{"", "Flour", "Water"},
+		{"Topping", "Olive Oil", "Rosemary"},
+		{"", "Salt"},
+		{"Filling", "Cheese"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupPortions() got = %v, want %v", got, want)
+	}
+
+	if w := groups[1].Weight(); math.Abs(w-35) > 1e-9 {
+		t.Errorf("Weight() got = %v, want 35", w)
+	}
+
+	if !IsGrouped(ingredients) || IsGrouped(ingredients[:2]) {
+		t.Error("IsGrouped() got the wrong result")
+	}
+}
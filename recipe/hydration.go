@@ -0,0 +1,102 @@
+package recipe
+
+// DefaultStarterHydration is the hydration assumed for a sourdough starter
+// without a known hydration.
+const DefaultStarterHydration = 1.0
+
+// Summary is the baker's math of a dough where the flour and water held by
+// sourdough starters and other preferments are counted. All amounts are in
+// grams.
+type Summary struct {
+	Flour             float64
+	Water             float64
+	Salt              float64
+	Dough             float64
+	PrefermentedFlour float64
+}
+
+// Hydration returns the ratio of water to flour.
+func (s Summary) Hydration() float64 {
+	return ratio(s.Water, s.Flour)
+}
+
+// PrefermentedFlourRatio returns the ratio of flour which is prefermented.
+func (s Summary) PrefermentedFlourRatio() float64 {
+	return ratio(s.PrefermentedFlour, s.Flour)
+}
+
+// SaltRatio returns the ratio of salt to flour.
+func (s Summary) SaltRatio() float64 {
+	return ratio(s.Salt, s.Flour)
+}
+
+func ratio(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+
+// preferment returns the flour and water held by a preferment of the given
+// weight and hydration.
+func preferment(weight, hydration float64) (flour, water float64) {
+	flour = weight / (1 + hydration)
+	return flour, weight - flour
+}
+
+// Summarize returns the baker's math of a list of portion ingredients. The
+// contents of sourdough ingredients are split into flour and water by their
+// hydration, or DefaultStarterHydration when it's unknown. Amounts which
+// can't be converted to grams are ignored.
+func Summarize(ingredients []PortionIngredient) Summary {
+	var s Summary
+	for _, ingredient := range ingredients {
+		v, err := ingredient.Value.ConvertWith(UnitGrams, ingredient.Properties())
+		if err != nil {
+			continue
+		}
+
+		s.Dough += v.Value
+		switch ingredient.Kind {
+		case KindFlour:
+			s.Flour += v.Value
+		case KindWater:
+			s.Water += v.Value
+		case KindSalt:
+			s.Salt += v.Value
+		case KindSourdough:
+			hydration := ingredient.Hydration
+			if hydration == 0 {
+				hydration = DefaultStarterHydration
+			}
+
+			flour, water := preferment(v.Value, hydration)
+			s.Flour += flour
+			s.Water += water
+			s.PrefermentedFlour += flour
+		}
+	}
+	return s
+}
+
+// SummarizeStages returns the baker's math of a multi-stage formula. The
+// flour of every stage but the final dough counts as prefermented.
+func SummarizeStages(stages []StagePortion) Summary {
+	s := Summarize(Overall(stages))
+	for _, stage := range stages {
+		if stage.Name == FinalDough {
+			continue
+		}
+
+		for _, ingredient := range stage.Ingredients {
+			if ingredient.Kind != KindFlour || isStageOutput(stages, ingredient) {
+				continue
+			}
+
+			if v, err := ingredient.Value.ConvertWith(UnitGrams, ingredient.Properties()); err == nil {
+				s.PrefermentedFlour += v.Value
+			}
+		}
+	}
+	return s
+}
@@ -0,0 +1,63 @@
+package recipe
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	// Balanced Blend Buns with 450g total flour.
+	ingredients := []PortionIngredient{
+		{Name: "White Flour", Kind: KindFlour, Value: Tuple{Value: 225, Unit: UnitGrams}},
+		{Name: "Whole Grain Flour", Kind: KindFlour, Value: Tuple{Value: 225, Unit: UnitGrams}},
+		{Name: "Sourdough Starter", Kind: KindSourdough, Value: Tuple{Value: 67.5, Unit: UnitGrams}},
+		{Name: "Water", Kind: KindWater, Value: Tuple{Value: 346.5, Unit: UnitGrams}},
+		{Name: "Salt", Kind: KindSalt, Value: Tuple{Value: 8.1, Unit: UnitGrams}},
+	}
+
+	s := Summarize(ingredients)
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"flour", s.Flour, 483.75},
+		{"water", s.Water, 380.25},
+		{"dough", s.Dough, 872.1},
+		{"hydration", s.Hydration(), 380.25 / 483.75},
+		{"prefermented flour", s.PrefermentedFlourRatio(), 33.75 / 483.75},
+		{"salt", s.SaltRatio(), 8.1 / 483.75},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 1e-9 {
+			t.Errorf("Summarize() %s got = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// A stiff starter holds less water.
+	ingredients[2].Hydration = 0.5
+	if got, want := Summarize(ingredients).Water, 346.5+22.5; math.Abs(got-want) > 1e-9 {
+		t.Errorf("Summarize() water got = %v, want %v", got, want)
+	}
+}
+
+func TestSummarizeStages(t *testing.T) {
+	stages := []StagePortion{
+		{Name: "Levain", Ingredients: []PortionIngredient{
+			{Name: "Whole Grain Flour", Kind: KindFlour, Value: Tuple{Value: 100, Unit: UnitGrams}},
+			{Name: "Water", Kind: KindWater, Value: Tuple{Value: 100, Unit: UnitGrams}},
+			{Name: "Sourdough Starter", Kind: KindSourdough, Value: Tuple{Value: 20, Unit: UnitGrams}},
+		}},
+		{Name: FinalDough, Ingredients: []PortionIngredient{
+			{Name: "Bread Flour", Kind: KindFlour, Value: Tuple{Value: 1000, Unit: UnitGrams}},
+			{Name: "Water", Kind: KindWater, Value: Tuple{Value: 700, Unit: UnitGrams}},
+			{Name: "Levain", Kind: KindSourdough, Value: Tuple{Value: 220, Unit: UnitGrams}},
+		}},
+	}
+
+	s := SummarizeStages(stages)
+	if s.Flour != 1110 || s.Water != 810 || s.PrefermentedFlour != 110 {
+		t.Errorf("SummarizeStages() got = %+v", s)
+	}
+}
@@ -0,0 +1,70 @@
+package recipe
+
+import "strings"
+
+// MatchNames returns the indices of the names best matching query, ignoring
+// case. Matches are ranked in tiers and only the best non-empty tier is
+// returned:
+//
+//  1. names equal to query
+//  2. names starting with query
+//  3. names containing query
+//  4. names containing the characters of query in order, e.g. "cntry"
+//     matches "Country"
+//
+// Words are matched the same way, so "loaf" is a prefix match of
+// "Country Loaf".
+func MatchNames(query string, names []string) []int {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+
+	tiers := make([][]int, 4)
+	for i, name := range names {
+		name = strings.ToLower(name)
+		switch {
+		case name == query:
+			tiers[0] = append(tiers[0], i)
+		case hasWordPrefix(name, query):
+			tiers[1] = append(tiers[1], i)
+		case strings.Contains(name, query):
+			tiers[2] = append(tiers[2], i)
+		case isSubsequence(name, query):
+			tiers[3] = append(tiers[3], i)
+		}
+	}
+
+	for _, tier := range tiers {
+		if len(tier) > 0 {
+			return tier
+		}
+	}
+	return nil
+}
+
+// hasWordPrefix returns true if name or any of its words starts with prefix.
+func hasWordPrefix(name, prefix string) bool {
+	if strings.HasPrefix(name, prefix) {
+		return true
+	}
+
+	for _, word := range strings.Fields(name) {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubsequence returns true if the characters of sub appear in s in order.
+func isSubsequence(s, sub string) bool {
+	rs := []rune(sub)
+	i := 0
+	for _, r := range s {
+		if i < len(rs) && r == rs[i] {
+			i++
+		}
+	}
+	return i == len(rs)
+}
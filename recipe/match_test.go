@@ -0,0 +1,31 @@
+package recipe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchNames(t *testing.T) {
+	names := []string{"Country", "Country Loaf", "Cornbread", "Rye Loaf", "Ciabatta"}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{query: "country", want: []int{0}},
+		{query: "Coun", want: []int{0, 1}},
+		{query: "co", want: []int{0, 1, 2}},
+		{query: "loaf", want: []int{1, 3}},
+		{query: "batt", want: []int{4}},
+		{query: "cbrd", want: []int{2}},
+		{query: "focaccia", want: nil},
+		{query: " ", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := MatchNames(tt.query, names); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchNames() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package recipe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToPercentages converts templates with fixed amounts, as most recipes are
+// written, into baker's percentages of the total flour of their stage.
+// Ingredients of the same stage with the same name are added together.
+// Amounts which can't be converted to a weight, e.g. a volume of an
+// ingredient of unknown kind, are kept as fixed amounts. A template without
+// a unit category gets the category of its amount.
+func ToPercentages(templates []RecipeIngredient) ([]RecipeIngredient, error) {
+	merged, err := mergeAmounts(templates)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []RecipeIngredient
+	for _, stage := range StageNames(merged) {
+		stageTemplates := StageTemplates(merged, stage)
+
+		var flour float64
+		for _, template := range stageTemplates {
+			if template.Kind != KindFlour || template.Mode != ModeAmount {
+				continue
+			}
+			if v, err := template.Amount.ConvertWith(UnitGrams, template.Properties()); err == nil {
+				flour += v.Value
+			}
+		}
+		if flour == 0 {
+			if len(stageTemplates) == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("stage %s: no flour found", stageName(stage))
+		}
+
+		for _, template := range stageTemplates {
+			if template.PreferUnitCategory == UnitCategoryUnknown {
+				template.PreferUnitCategory = template.Amount.Unit.Category()
+			}
+			if template.Mode != ModeAmount {
+				out = append(out, template)
+				continue
+			}
+
+			v, err := template.Amount.ConvertWith(UnitGrams, template.Properties())
+			if err != nil {
+				out = append(out, template)
+				continue
+			}
+
+			template.Mode = ModePercentage
+			template.Percentage = v.Value / flour
+			template.Dependency = DependencyTotalFlour
+			template.Amount = Tuple{}
+			out = append(out, template)
+		}
+	}
+
+	return out, nil
+}
+
+// mergeAmounts adds together the fixed amounts of templates of the same
+// stage with the same name.
+func mergeAmounts(templates []RecipeIngredient) ([]RecipeIngredient, error) {
+	var (
+		out   []RecipeIngredient
+		index = map[string]int{}
+	)
+	for _, template := range templates {
+		key := strings.ToLower(template.Stage) + "\x00" + strings.ToLower(template.Name)
+		i, ok := index[key]
+		if !ok || template.Mode != ModeAmount || out[i].Mode != ModeAmount {
+			index[key] = len(out)
+			out = append(out, template)
+			continue
+		}
+
+		if out[i].Amount.Unit == template.Amount.Unit {
+			out[i].Amount.Value += template.Amount.Value
+			continue
+		}
+
+		a, err := out[i].Amount.ConvertWith(UnitGrams, out[i].Properties())
+		if err != nil {
+			return nil, fmt.Errorf("failed to add amounts of %s: %w", template.Name, err)
+		}
+		b, err := template.Amount.ConvertWith(UnitGrams, template.Properties())
+		if err != nil {
+			return nil, fmt.Errorf("failed to add amounts of %s: %w", template.Name, err)
+		}
+		out[i].Amount = UnitGrams.Tuple(a.Value + b.Value)
+	}
+	return out, nil
+}
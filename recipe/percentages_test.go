@@ -0,0 +1,70 @@
+package recipe
+
+import (
+	"math"
+	"testing"
+)
+
+func TestToPercentages(t *testing.T) {
+	amount := func(name string, kind Kind, value Tuple, stage string) RecipeIngredient {
+		return RecipeIngredient{Name: name, Kind: kind, Mode: ModeAmount, Amount: value, Stage: stage}
+	}
+
+	templates := []RecipeIngredient{
+		amount("Whole Wheat Flour", KindFlour, UnitGrams.Tuple(50), "Levain"),
+		amount("Water", KindWater, UnitGrams.Tuple(50), "Levain"),
+		amount("Bread Flour", KindFlour, UnitGrams.Tuple(400), FinalDough),
+		amount("Whole Wheat Flour", KindFlour, UnitGrams.Tuple(100), FinalDough),
+		amount("Water", KindWater, UnitMillilitres.Tuple(300), FinalDough),
+		amount("Water", KindWater, UnitGrams.Tuple(50), FinalDough),
+		amount("Levain", KindSourdough, UnitGrams.Tuple(100), FinalDough),
+		amount("Salt", KindSalt, UnitTeaspoons.Tuple(2), FinalDough),
+		amount("Raisins", KindOther, UnitCups.Tuple(1), FinalDough),
+	}
+
+	got, err := ToPercentages(templates)
+	if err != nil {
+		t.Fatalf("ToPercentages() error = %v", err)
+	}
+
+	want := []struct {
+		name       string
+		stage      string
+		mode       Mode
+		percentage float64
+		category   UnitCategory
+	}{
+		{"Whole Wheat Flour", "Levain", ModePercentage, 1, UnitCategoryWeight},
+		{"Water", "Levain", ModePercentage, 1, UnitCategoryWeight},
+		{"Bread Flour", FinalDough, ModePercentage, 0.8, UnitCategoryWeight},
+		{"Whole Wheat Flour", FinalDough, ModePercentage, 0.2, UnitCategoryWeight},
+		{"Water", FinalDough, ModePercentage, 0.7, UnitCategoryWeight},
+		{"Levain", FinalDough, ModePercentage, 0.2, UnitCategoryWeight},
+		{"Salt", FinalDough, ModePercentage, 2 * 4.928921593749999 * 2.16 / 500, UnitCategoryTeaspoon},
+		{"Raisins", FinalDough, ModeAmount, 0, UnitCategoryVolume},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ToPercentages() got %d templates, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Name != w.name || g.Stage != w.stage || g.Mode != w.mode || g.PreferUnitCategory != w.category {
+			t.Errorf("ToPercentages()[%d] got = %+v, want %+v", i, g, w)
+		}
+		if math.Abs(g.Percentage-w.percentage) > 1e-9 {
+			t.Errorf("ToPercentages()[%d] %s percentage = %v, want %v", i, g.Name, g.Percentage, w.percentage)
+		}
+		if g.Mode == ModePercentage && g.Dependency != DependencyTotalFlour {
+			t.Errorf("ToPercentages()[%d] %s dependency = %q, want %q", i, g.Name, g.Dependency, DependencyTotalFlour)
+		}
+	}
+}
+
+func TestToPercentagesWithoutFlour(t *testing.T) {
+	_, err := ToPercentages([]RecipeIngredient{
+		{Name: "Water", Kind: KindWater, Mode: ModeAmount, Amount: UnitGrams.Tuple(100)},
+	})
+	if err == nil {
+		t.Error("ToPercentages() expected an error without flour")
+	}
+}
@@ -3,7 +3,10 @@ package recipe
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+	"unicode"
 )
 
 // Unit is a unit of measurement.
@@ -12,39 +15,69 @@ type Unit string
 const (
 	UnitGrams       Unit = "g"
 	UnitKilos       Unit = "kg"
+	UnitOunces      Unit = "oz"
+	UnitPounds      Unit = "lb"
 	UnitLitres      Unit = "l"
 	UnitMillilitres Unit = "ml"
+	UnitFluidOunces Unit = "fl oz"
+	UnitQuarts      Unit = "qt"
 	UnitTeaspoons   Unit = "tsp"
 	UnitTablespoons Unit = "tbsp"
 	UnitCups        Unit = "cup"
 	UnitPinches     Unit = "pinch"
 	UnitHandfuls    Unit = "handful"
+	UnitPieces      Unit = "pc"
 )
 
+// unitNames maps the accepted spellings of a unit to the unit.
+var unitNames = map[string]Unit{
+	"g":            UnitGrams,
+	"grams":        UnitGrams,
+	"kg":           UnitKilos,
+	"kilos":        UnitKilos,
+	"kilograms":    UnitKilos,
+	"oz":           UnitOunces,
+	"ounce":        UnitOunces,
+	"ounces":       UnitOunces,
+	"lb":           UnitPounds,
+	"lbs":          UnitPounds,
+	"pound":        UnitPounds,
+	"pounds":       UnitPounds,
+	"l":            UnitLitres,
+	"litres":       UnitLitres,
+	"ml":           UnitMillilitres,
+	"millilitres":  UnitMillilitres,
+	"fl oz":        UnitFluidOunces,
+	"fl. oz":       UnitFluidOunces,
+	"floz":         UnitFluidOunces,
+	"fluid ounce":  UnitFluidOunces,
+	"fluid ounces": UnitFluidOunces,
+	"qt":           UnitQuarts,
+	"quart":        UnitQuarts,
+	"quarts":       UnitQuarts,
+	"tsp":          UnitTeaspoons,
+	"teaspoons":    UnitTeaspoons,
+	"tbsp":         UnitTablespoons,
+	"tablespoons":  UnitTablespoons,
+	"cup":          UnitCups,
+	"cups":         UnitCups,
+	"pinch":        UnitPinches,
+	"pinches":      UnitPinches,
+	"handful":      UnitHandfuls,
+	"handfuls":     UnitHandfuls,
+	"pc":           UnitPieces,
+	"pcs":          UnitPieces,
+	"piece":        UnitPieces,
+	"pieces":       UnitPieces,
+}
+
 // ParseUnit parses a unit string into a Unity type.
 func ParseUnit(value string) (Unit, error) {
-	switch value {
-	case "g", "grams":
-		return UnitGrams, nil
-	case "kg", "kilos", "kilograms":
-		return UnitKilos, nil
-	case "l", "litres":
-		return UnitLitres, nil
-	case "ml", "millilitres":
-		return UnitMillilitres, nil
-	case "tsp", "teaspoons":
-		return UnitTeaspoons, nil
-	case "tbsp", "tablespoons":
-		return UnitTablespoons, nil
-	case "cup", "cups":
-		return UnitCups, nil
-	case "pinch", "pinches":
-		return UnitPinches, nil
-	case "handful", "handfuls":
-		return UnitHandfuls, nil
-	default:
+	unit, ok := unitNames[value]
+	if !ok {
 		return "", fmt.Errorf("invalid unit: %s", value)
 	}
+	return unit, nil
 }
 
 // UnitCategory is a category of units. It's used to determine the most suitable
@@ -52,10 +85,11 @@ func ParseUnit(value string) (Unit, error) {
 type UnitCategory string
 
 const (
-	UnitCategoryWeight  UnitCategory = "weight"
-	UnitCategoryVolume  UnitCategory = "volume"
-	UnitCategoryCount   UnitCategory = "count"
-	UnitCategoryUnknown UnitCategory = ""
+	UnitCategoryWeight   UnitCategory = "weight"
+	UnitCategoryVolume   UnitCategory = "volume"
+	UnitCategoryCount    UnitCategory = "count"
+	UnitCategoryTeaspoon UnitCategory = "teaspoon"
+	UnitCategoryUnknown  UnitCategory = ""
 )
 
 // FromUnitCategory returns the default unit for the given category.
@@ -66,6 +100,8 @@ func FromUnitCategory(cat UnitCategory) (Unit, error) {
 	case UnitCategoryVolume:
 		return UnitLitres, nil
 	case UnitCategoryCount:
+		return UnitPieces, nil
+	case UnitCategoryTeaspoon:
 		return UnitTeaspoons, nil
 	default:
 		return "", fmt.Errorf("invalid unit category: %s", cat)
@@ -81,10 +117,69 @@ func DefaultFromUnitCategory(cat UnitCategory) Unit {
 	return unit
 }
 
+// UnitSystem is the system of units amounts are displayed in.
+type UnitSystem string
+
+const (
+	UnitSystemMetric   UnitSystem = "metric"
+	UnitSystemImperial UnitSystem = "imperial"
+)
+
+// Unit returns the default unit of the category in the system. Spoons and
+// pieces are the same in every system.
+func (s UnitSystem) Unit(cat UnitCategory) Unit {
+	if s == UnitSystemImperial {
+		switch cat {
+		case UnitCategoryWeight:
+			return UnitOunces
+		case UnitCategoryVolume:
+			return UnitFluidOunces
+		}
+	}
+	return DefaultFromUnitCategory(cat)
+}
+
+// Convert converts a weight or volume to the system, in the most appropriate
+// unit. Other amounts are only made appropriate.
+func (s UnitSystem) Convert(t Tuple) Tuple {
+	switch cat := t.Unit.Category(); cat {
+	case UnitCategoryWeight, UnitCategoryVolume:
+		if v, err := t.Convert(s.Unit(cat)); err == nil {
+			t = v
+		}
+	}
+	return t.Appropriate()
+}
+
 func (u Unit) String() string {
 	return string(u)
 }
 
+// Implement from SQL Driver Valuer interface
+func (u Unit) Value() (any, error) {
+	if u == "" {
+		return nil, nil
+	}
+	return string(u), nil
+}
+
+// Implement from SQL Scanner interface
+func (u *Unit) Scan(src any) error {
+	if src == nil {
+		*u = ""
+		return nil
+	}
+
+	switch src := src.(type) {
+	case string:
+		*u = Unit(src)
+	default:
+		return fmt.Errorf("invalid unit: %v, %T", src, src)
+	}
+
+	return nil
+}
+
 // Format formats the given value with the unit.
 func (u Unit) Format(value float64) string {
 	return FormatValue(value, u)
@@ -93,7 +188,7 @@ func (u Unit) Format(value float64) string {
 // IsWeight returns true if the unit is a weight unit.
 func (u Unit) IsWeight() bool {
 	switch u {
-	case UnitGrams, UnitKilos:
+	case UnitGrams, UnitKilos, UnitOunces, UnitPounds:
 		return true
 	default:
 		return false
@@ -103,7 +198,7 @@ func (u Unit) IsWeight() bool {
 // IsVolume returns true if the unit is a volume unit.
 func (u Unit) IsVolume() bool {
 	switch u {
-	case UnitLitres, UnitMillilitres:
+	case UnitLitres, UnitMillilitres, UnitFluidOunces, UnitQuarts:
 		return true
 	default:
 		return false
@@ -113,13 +208,30 @@ func (u Unit) IsVolume() bool {
 // IsCount returns true if the unit is a count unit.
 func (u Unit) IsCount() bool {
 	switch u {
-	case UnitTeaspoons, UnitTablespoons, UnitCups, UnitPinches, UnitHandfuls:
+	case UnitTeaspoons, UnitTablespoons, UnitCups, UnitPinches, UnitHandfuls, UnitPieces:
 		return true
 	default:
 		return false
 	}
 }
 
+// Category returns the unit category of the unit, UnitCategoryUnknown for an
+// unknown unit. Spoons and pinches are of the teaspoon category.
+func (u Unit) Category() UnitCategory {
+	switch {
+	case u.IsWeight():
+		return UnitCategoryWeight
+	case u == UnitTeaspoons || u == UnitTablespoons || u == UnitPinches:
+		return UnitCategoryTeaspoon
+	case u == UnitPieces:
+		return UnitCategoryCount
+	case u.IsVolume() || u.IsCount():
+		return UnitCategoryVolume
+	default:
+		return UnitCategoryUnknown
+	}
+}
+
 // IsUnknown returns true if the unit is unknown.
 func (u Unit) IsUnknown() bool {
 	return !u.IsWeight() && !u.IsVolume() && !u.IsCount()
@@ -148,40 +260,6 @@ func (u Unit) ConvertIngredient(value float64, to Unit, ingredient Kind) (float6
 	return ConvertIngredient(value, u, to, ingredient)
 }
 
-type pair struct {
-	From Unit
-	To   Unit
-}
-
-var scaleFactor = map[pair]float64{
-	{UnitMillilitres, UnitLitres}:    0.001,
-	{UnitLitres, UnitMillilitres}:    1000,
-	{UnitGrams, UnitKilos}:           0.001,
-	{UnitKilos, UnitGrams}:           1000,
-	{UnitTeaspoons, UnitTablespoons}: 0.333333,
-	{UnitTablespoons, UnitTeaspoons}: 3,
-	{UnitCups, UnitLitres}:           0.236588,
-	{UnitLitres, UnitCups}:           4.22675,
-	{UnitPinches, UnitTeaspoons}:     0.333333,
-	{UnitTeaspoons, UnitPinches}:     3,
-	{UnitHandfuls, UnitCups}:         0.5,
-	{UnitCups, UnitHandfuls}:         2,
-}
-
-// Convert converts the given value from one unit to another.
-func Convert(value float64, from Unit, to Unit) (float64, error) {
-	if from == to {
-		return value, nil
-	}
-
-	factor, ok := scaleFactor[pair{from, to}]
-	if !ok {
-		return 0, fmt.Errorf("unsupported conversion from %s to %s", from, to)
-	}
-
-	return value * factor, nil
-}
-
 // Kind is an ingredient kind.
 type Kind string
 
@@ -221,6 +299,43 @@ func (it *Kind) Scan(src any) error {
 	return nil
 }
 
+// kindWords are the words of ingredient names identifying a kind, in order of
+// precedence, e.g. "Salted Butter" is butter and not salt.
+var kindWords = []struct {
+	kind  Kind
+	words []string
+}{
+	{KindSourdough, []string{"starter", "levain", "leaven", "sourdough"}},
+	{KindButter, []string{"butter"}},
+	{KindMilk, []string{"milk", "buttermilk"}},
+	{KindEgg, []string{"egg", "yolk"}},
+	{KindYeast, []string{"yeast"}},
+	{KindFlour, []string{"flour", "meal", "cornmeal", "semolina"}},
+	{KindWater, []string{"water"}},
+	{KindSalt, []string{"salt"}},
+	{KindSugar, []string{"sugar", "honey", "syrup", "molasses"}},
+	{KindOil, []string{"oil"}},
+}
+
+// GuessKind guesses the kind of an ingredient from its name, e.g. "Whole
+// Wheat Flour" is flour. It returns KindOther if no kind is recognized.
+func GuessKind(name string) Kind {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	for _, kw := range kindWords {
+		for _, word := range words {
+			for _, w := range kw.words {
+				if word == w || strings.TrimSuffix(word, "s") == w {
+					return kw.kind
+				}
+			}
+		}
+	}
+	return KindOther
+}
+
 var kindDensity = map[Kind]float64{
 	// Water density is 1 g/ml
 	KindWater: 1,
@@ -254,55 +369,114 @@ var kindDensity = map[Kind]float64{
 	KindSourdough: 0.95,
 }
 
-// ConvertIngredient converts the given value from one unit to another based on
-// the ingredient kind.
-func ConvertIngredient(value float64, from Unit, to Unit, kind Kind) (float64, error) {
-	// Check unit compatibility first
-	if from.IsCompatible(to) {
-		return Convert(value, from, to)
+// kindPieceWeight is the default weight in grams of a single piece.
+var kindPieceWeight = map[Kind]float64{
+	// A large egg without the shell weighs about 50g
+	KindEgg: 50,
+}
+
+// Properties are the physical properties of an ingredient used to convert
+// between weights, volumes and pieces.
+type Properties struct {
+	Kind Kind
+
+	// Density in g/ml. Zero means the default density of the kind.
+	Density float64
+
+	// PieceWeight is the weight of a single piece in grams. Zero means the
+	// default piece weight of the kind.
+	PieceWeight float64
+}
+
+func (p Properties) density() (float64, bool) {
+	if p.Density > 0 {
+		return p.Density, true
 	}
+	density, found := kindDensity[p.Kind]
+	return density, found
+}
 
-	// For incompatible units, check if ingredient density is available
-	density, found := kindDensity[kind]
-	if !found {
-		return Convert(value, from, to) // fallback to default conversion
+func (p Properties) pieceWeight() (float64, bool) {
+	if p.PieceWeight > 0 {
+		return p.PieceWeight, true
+	}
+	weight, found := kindPieceWeight[p.Kind]
+	return weight, found
+}
+
+// Convert converts the given value from one unit to another.
+//
+// Units of the same dimension are converted directly. Weights and volumes,
+// including spoon and cup measures, are bridged by the density of the
+// ingredient, and pieces by the weight of a single piece.
+func (p Properties) Convert(value float64, from Unit, to Unit) (float64, error) {
+	v, err := Convert(value, from, to)
+	if err == nil {
+		return v, nil
 	}
 
 	// Normalize value to a standard gram unit first.
-	norm, err := normalizeToGrams(value, from, density)
-	if err != nil {
+	grams, ok := p.toGrams(value, from)
+	if !ok {
 		return 0, err
 	}
 
 	// Convert the normalized value to the target unit.
-	return convertNormalized(norm, to, density)
+	v, ok = p.fromGrams(grams, to)
+	if !ok {
+		return 0, err
+	}
+	return v, nil
 }
 
-func normalizeToGrams(value float64, from Unit, density float64) (float64, error) {
-	switch {
-	case from.IsWeight():
-		return from.Convert(value, UnitGrams)
-	case from.IsVolume():
-		normalizedVolume, err := from.Convert(value, UnitMillilitres)
-		if err != nil {
-			return 0, err
-		}
-		return normalizedVolume * density, nil
-	default:
-		return 0, fmt.Errorf("unknown unit: %s", from)
+func (p Properties) toGrams(value float64, from Unit) (float64, bool) {
+	if grams, err := from.Convert(value, UnitGrams); err == nil {
+		return grams, true
+	}
+
+	if from == UnitPieces {
+		weight, found := p.pieceWeight()
+		return value * weight, found
+	}
+
+	density, found := p.density()
+	if !found {
+		return 0, false
+	}
+
+	volume, err := from.Convert(value, UnitMillilitres)
+	if err != nil {
+		return 0, false
 	}
+	return volume * density, true
 }
 
-func convertNormalized(value float64, to Unit, density float64) (float64, error) {
-	switch {
-	case to.IsWeight():
-		return UnitGrams.Convert(value, to)
-	case to.IsVolume():
-		volumeInMillilitres := value / density
-		return UnitMillilitres.Convert(volumeInMillilitres, to)
-	default:
-		return 0, fmt.Errorf("unknown unit: %s", to)
+func (p Properties) fromGrams(value float64, to Unit) (float64, bool) {
+	if v, err := UnitGrams.Convert(value, to); err == nil {
+		return v, true
 	}
+
+	if to == UnitPieces {
+		weight, found := p.pieceWeight()
+		return value / weight, found
+	}
+
+	density, found := p.density()
+	if !found {
+		return 0, false
+	}
+
+	v, err := UnitMillilitres.Convert(value/density, to)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// ConvertIngredient converts the given value from one unit to another based on
+// the ingredient kind. See Properties.Convert.
+func ConvertIngredient(value float64, from Unit, to Unit, kind Kind) (float64, error) {
+	return Properties{Kind: kind}.Convert(value, from, to)
 }
 
 // Tuple is a value and unit pair.
@@ -318,6 +492,7 @@ type Tuple struct {
 //   - if the value is 1000g, the tuple will be {1, UnitKilos}.
 //   - if the value is 1.5l, the tuple will be {1.5, UnitLitres}.
 //   - if the value is 1500ml, the tuple will be {1.5, UnitLitres}.
+//   - if the value is 20oz, the tuple will be {1.25, UnitPounds}.
 func (u Unit) Appropriate(value float64) Tuple {
 	switch u {
 	case UnitGrams:
@@ -336,6 +511,22 @@ func (u Unit) Appropriate(value float64) Tuple {
 		if value >= 1000 {
 			return Tuple{Value: value / 1000, Unit: UnitLitres}
 		}
+	case UnitOunces:
+		if value >= 16 {
+			return Tuple{Value: value / 16, Unit: UnitPounds}
+		}
+	case UnitPounds:
+		if value < 1 {
+			return Tuple{Value: value * 16, Unit: UnitOunces}
+		}
+	case UnitFluidOunces:
+		if value >= 32 {
+			return Tuple{Value: value / 32, Unit: UnitQuarts}
+		}
+	case UnitQuarts:
+		if value < 1 {
+			return Tuple{Value: value * 32, Unit: UnitFluidOunces}
+		}
 	case UnitTeaspoons:
 		if value >= 3 {
 			return Tuple{Value: value / 3, Unit: UnitTablespoons}
@@ -346,7 +537,7 @@ func (u Unit) Appropriate(value float64) Tuple {
 		}
 	case UnitCups:
 		if value >= 4 {
-			return Tuple{Value: value / 4, Unit: UnitLitres}
+			return Tuple{Value: value / 4, Unit: UnitQuarts}
 		}
 	case UnitPinches:
 		if value >= 3 {
@@ -379,7 +570,13 @@ func (t Tuple) Convert(to Unit) (Tuple, error) {
 // ConvertIngredient converts the given value to the given unit based on the
 // ingredient kind.
 func (t Tuple) ConvertIngredient(to Unit, kind Kind) (Tuple, error) {
-	value, err := ConvertIngredient(t.Value, t.Unit, to, kind)
+	return t.ConvertWith(to, Properties{Kind: kind})
+}
+
+// ConvertWith converts the given value to the given unit based on the
+// properties of an ingredient.
+func (t Tuple) ConvertWith(to Unit, props Properties) (Tuple, error) {
+	value, err := props.Convert(t.Value, t.Unit, to)
 	if err != nil {
 		return Tuple{}, err
 	}
@@ -391,6 +588,110 @@ func (t Tuple) Format() string {
 	return FormatValue(t.Value, t.Unit)
 }
 
+// ParseTuple parses an amount string such as "10g", "2pc" or "1.5 kg" into a
+// Tuple.
+//
+// The value may be a fraction or a mixed number such as "1/2 cup" or
+// "1 1/2 cups", and compound amounts such as "1 lb 4 oz" are added together
+// in the unit of the first part.
+func ParseTuple(value string) (Tuple, error) {
+	if !quantityRegexp.MatchString(value) {
+		return Tuple{}, fmt.Errorf("invalid amount string: %s", value)
+	}
+
+	var out Tuple
+	for i, matches := range quantityPartRegexp.FindAllStringSubmatch(value, -1) {
+		amount, err := ParseNumber(matches[1])
+		if err != nil {
+			return Tuple{}, fmt.Errorf("invalid amount value: %w", err)
+		}
+
+		unit, err := ParseUnit(matches[2])
+		if err != nil {
+			return Tuple{}, fmt.Errorf("invalid amount unit: %w", err)
+		}
+
+		if i == 0 {
+			out = Tuple{Value: amount, Unit: unit}
+			continue
+		}
+
+		amount, err = unit.Convert(amount, out.Unit)
+		if err != nil {
+			return Tuple{}, fmt.Errorf("invalid amount string: %s: %w", value, err)
+		}
+		out.Value += amount
+	}
+
+	return out, nil
+}
+
+// ParseNumber parses a decimal, a fraction such as "1/2" or a mixed number
+// such as "1 1/2".
+func ParseNumber(value string) (float64, error) {
+	whole, fraction, mixed := strings.Cut(value, " ")
+	if !mixed {
+		whole, fraction = "", value
+		if !strings.Contains(value, "/") {
+			return strconv.ParseFloat(value, 64)
+		}
+	}
+
+	var out float64
+	if whole != "" {
+		v, err := strconv.ParseFloat(whole, 64)
+		if err != nil {
+			return 0, err
+		}
+		out = v
+	}
+
+	numerator, denominator, _ := strings.Cut(strings.TrimSpace(fraction), "/")
+	n, err := strconv.ParseFloat(numerator, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	d, err := strconv.ParseFloat(denominator, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if d == 0 {
+		return 0, fmt.Errorf("invalid fraction: %s", value)
+	}
+
+	return out + n/d, nil
+}
+
+var (
+	numberPattern = `\d+\s+\d+/\d+|\d+/\d+|\d+\.?\d*|\.\d+`
+	unitPattern   = unitNamesPattern()
+	partPattern   = `(` + numberPattern + `)\s*(` + unitPattern + `)`
+
+	quantityPattern    = partPattern + `(?:\s+` + partPattern + `)*`
+	quantityRegexp     = regexp.MustCompile(`^\s*` + quantityPattern + `\s*$`)
+	quantityPartRegexp = regexp.MustCompile(partPattern)
+)
+
+// unitNamesPattern returns a regular expression matching any of the unit
+// names, longest first so "fl oz" isn't matched as "fl".
+func unitNamesPattern() string {
+	names := make([]string, 0, len(unitNames))
+	for name := range unitNames {
+		names = append(names, regexp.QuoteMeta(name))
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) > len(names[j])
+		}
+		return names[i] < names[j]
+	})
+
+	return strings.Join(names, "|")
+}
+
 // FormatValue formats a value with the given unit.
 //
 // The value is formatted as a string with the following rules:
@@ -430,6 +731,8 @@ func pluralizeUnit(unit Unit, value float64) string {
 		return "pinches"
 	case UnitHandfuls:
 		return "handfuls"
+	case UnitPieces:
+		return "pcs"
 	default:
 		return unit.String()
 	}
@@ -449,19 +752,55 @@ type Ingredient struct {
 	Dependency   string
 }
 
+// Mode determines how the amount of a recipe ingredient is specified.
+type Mode string
+
+const (
+	// ModePercentage is a baker's percentage relative to the ingredient's
+	// dependency. Percentages are unbounded, so 1.05 is 105%.
+	ModePercentage Mode = "percentage"
+
+	// ModeAmount is a fixed amount (e.g. 2pc or 10g) which does not follow
+	// the dependencies of the recipe.
+	ModeAmount Mode = "amount"
+)
+
 // RecipeIngredient is an ingredient of a recipe template.
 type RecipeIngredient struct {
 	Name               string
 	Kind               Kind
 	PreferUnitCategory UnitCategory
+	Mode               Mode
 	Percentage         float64
+	Amount             Tuple
 	Dependency         string
+	Stage              string
+
+	// Group is an optional label such as "Topping" or "Filling". It's only
+	// used for display and doesn't affect the calculation.
+	Group string
+
+	// Hydration of a sourdough starter or other preferment as a ratio of
+	// water to flour. Zero means unknown.
+	Hydration float64
+
+	// Density in g/ml and PieceWeight in grams override the defaults of
+	// the kind. Zero means unknown.
+	Density     float64
+	PieceWeight float64
+}
+
+// Properties returns the physical properties of the ingredient.
+func (ri RecipeIngredient) Properties() Properties {
+	return Properties{Kind: ri.Kind, Density: ri.Density, PieceWeight: ri.PieceWeight}
 }
 
 // Recipe is a recipe template.
 type Recipe struct {
 	Name        string
+	Notes       string
 	Ingredients []RecipeIngredient
+	Steps       []Step
 }
 
 // PortionIngredient is an ingredient with the amount and unit. It's used to
@@ -472,6 +811,31 @@ type PortionIngredient struct {
 	Kind               Kind
 	PreferUnitCategory UnitCategory
 	Value              Tuple
+	Group              string
+	Hydration          float64
+	Density            float64
+	PieceWeight        float64
+}
+
+// Properties returns the physical properties of the ingredient.
+func (pi PortionIngredient) Properties() Properties {
+	return Properties{Kind: pi.Kind, Density: pi.Density, PieceWeight: pi.PieceWeight}
+}
+
+// Preferred returns the amount of the ingredient in the most appropriate
+// metric unit of its preferred unit category, or of its own unit if it can't
+// be converted.
+func (pi PortionIngredient) Preferred() Tuple {
+	return pi.PreferredIn(UnitSystemMetric)
+}
+
+// PreferredIn is like Preferred for the given unit system.
+func (pi PortionIngredient) PreferredIn(system UnitSystem) Tuple {
+	value := pi.Value
+	if v, err := value.ConvertWith(system.Unit(pi.PreferUnitCategory), pi.Properties()); err == nil {
+		value = v
+	}
+	return value.Appropriate()
 }
 
 // ParseDependencies parses a list of dependency strings into a list of
@@ -494,29 +858,29 @@ func ParseDependencies(dependencies []string) ([]Dependency, error) {
 //
 // The dependency string should be in the format of:
 //
-//	<dependency label> <dependency value><dependency unit>
+//	<dependency label> <dependency amount>
 //
 // For example:
 //
 //	"water 1000g"
 //	"water 1.5l"
 //	"water .5kg"
+//	"Sourdough Starter 230g"
+//	"total_flour 1 lb 4 oz"
+//	"water 1 1/2 cups"
 //
-// The dependency label can be anything.
+// The dependency label can be anything, including an ingredient name with
+// spaces. See Calculate for how labels are resolved, and ParseTuple for the
+// accepted amounts.
 func ParseDependency(dep string) (Dependency, error) {
 	matches := depRegexp.FindStringSubmatch(dep)
-	if len(matches) != 4 {
+	if len(matches) < 3 {
 		return Dependency{}, fmt.Errorf("invalid dependency string: %s", dep)
 	}
 
-	amount, err := strconv.ParseFloat(matches[2], 64)
-	if err != nil {
-		return Dependency{}, fmt.Errorf("invalid dependency value: %w", err)
-	}
-
-	unit, err := ParseUnit(matches[3])
+	value, err := ParseTuple(matches[2])
 	if err != nil {
-		return Dependency{}, fmt.Errorf("invalid dependency unit: %w", err)
+		return Dependency{}, fmt.Errorf("invalid dependency amount: %w", err)
 	}
 
 	label := matches[1]
@@ -524,16 +888,14 @@ func ParseDependency(dep string) (Dependency, error) {
 	var kind Kind
 
 	switch label {
-	case "total_flour":
+	case DependencyTotalFlour:
 		kind = KindFlour
+	case DependencyTotalWater:
+		kind = KindWater
 	default:
 		kind = Kind(label)
 	}
 
-	value := Tuple{
-		Value: amount,
-		Unit:  unit,
-	}
 	if v, err := value.ConvertIngredient(UnitGrams, kind); err == nil {
 		value = v
 	}
@@ -544,44 +906,4 @@ func ParseDependency(dep string) (Dependency, error) {
 	}, nil
 }
 
-var depRegexp = regexp.MustCompile(`(?P<label>\w+)\s+(?P<value>\d+\.?\d*)(?P<unit>\w+)`)
-
-func findDependency(dependencies []Dependency, label string) (Dependency, error) {
-	for _, dep := range dependencies {
-		if dep.Label == label {
-			return dep, nil
-		}
-	}
-
-	return Dependency{}, fmt.Errorf("dependency not found: %s", label)
-}
-
-// Calculate calculates the portion ingredients based on the given
-// dependencies.
-func (r Recipe) Calculate(dependencies []Dependency) ([]PortionIngredient, error) {
-	return Calculate(r.Ingredients, dependencies)
-}
-
-// Calculate calculates the portion ingredients based on the given templates
-// and dependencies.
-func Calculate(templates []RecipeIngredient, dependencies []Dependency) ([]PortionIngredient, error) {
-	var ingredients []PortionIngredient
-	for _, template := range templates {
-		dep, err := findDependency(dependencies, template.Dependency)
-		if err != nil {
-			return nil, fmt.Errorf("failed to find dependency for %s: %w", template.Name, err)
-		}
-
-		ingredients = append(ingredients, PortionIngredient{
-			Name: template.Name,
-			Kind: template.Kind,
-			Value: Tuple{
-				Value: dep.Value.Value * template.Percentage,
-				Unit:  dep.Value.Unit,
-			},
-			PreferUnitCategory: template.PreferUnitCategory,
-		})
-	}
-
-	return ingredients, nil
-}
+var depRegexp = regexp.MustCompile(`^\s*(?P<label>.+?)\s+(?P<amount>` + quantityPattern + `)\s*$`)
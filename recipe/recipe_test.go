@@ -1,6 +1,7 @@
 package recipe
 
 import (
+	"math"
 	"testing"
 )
 
@@ -62,6 +63,30 @@ func TestConvertIngredient(t *testing.T) {
 			ingredient: KindWater,
 			expected:   1,
 		},
+		{
+			name:       "Convert 1lb to grams",
+			value:      1,
+			from:       UnitPounds,
+			to:         UnitGrams,
+			ingredient: KindFlour,
+			expected:   453.59237,
+		},
+		{
+			name:       "Convert 32fl oz water to 1qt",
+			value:      32,
+			from:       UnitFluidOunces,
+			to:         UnitQuarts,
+			ingredient: KindWater,
+			expected:   1,
+		},
+		{
+			name:       "Convert 1lb water to fl oz",
+			value:      1,
+			from:       UnitPounds,
+			to:         UnitFluidOunces,
+			ingredient: KindWater,
+			expected:   15.33778269656277,
+		},
 		{
 			name:       "Convert 1000g flour to litres",
 			value:      1000,
@@ -149,11 +174,69 @@ func TestParseDependency(t *testing.T) {
 				Value: Tuple{Value: 1.5, Unit: UnitGrams},
 			},
 		},
+		{
+			name: "ingredient name",
+			dep:  "Sourdough Starter 230g",
+			want: Dependency{
+				Label: "Sourdough Starter",
+				Value: Tuple{Value: 230, Unit: UnitGrams},
+			},
+		},
+		{
+			name: "space before unit",
+			dep:  "total_flour 450 g",
+			want: Dependency{
+				Label: "total_flour",
+				Value: Tuple{Value: 450, Unit: UnitGrams},
+			},
+		},
+		{
+			name: "pounds",
+			dep:  "total_flour 2 lb",
+			want: Dependency{
+				Label: "total_flour",
+				Value: Tuple{Value: 907.18474, Unit: UnitGrams},
+			},
+		},
+		{
+			name: "compound amount",
+			dep:  "total_flour 1 lb 4 oz",
+			want: Dependency{
+				Label: "total_flour",
+				Value: Tuple{Value: 566.9904625, Unit: UnitGrams},
+			},
+		},
+		{
+			name: "mixed number",
+			dep:  "Type 00 Flour 1 1/2 cups",
+			want: Dependency{
+				Label: "Type 00 Flour",
+				Value: Tuple{Value: 1.5, Unit: UnitCups},
+			},
+		},
+		{
+			name: "ingredient name with number",
+			dep:  "Type 00 Flour 500g",
+			want: Dependency{
+				Label: "Type 00 Flour",
+				Value: Tuple{Value: 500, Unit: UnitGrams},
+			},
+		},
 		{
 			name:    "invalid dependency",
 			dep:     "water 1.5",
 			wantErr: true,
 		},
+		{
+			name:    "invalid dependency",
+			dep:     "water 1/0 cups",
+			wantErr: true,
+		},
+		{
+			name:    "incompatible compound amount",
+			dep:     "water 1 lb 4 fl oz",
+			wantErr: true,
+		},
 		{
 			name:    "invalid dependency",
 			dep:     "water 1.5kgg",
@@ -183,3 +266,160 @@ func TestParseDependency(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTuple(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Tuple
+		wantErr bool
+	}{
+		{value: "10g", want: Tuple{Value: 10, Unit: UnitGrams}},
+		{value: "2pc", want: Tuple{Value: 2, Unit: UnitPieces}},
+		{value: "1.5 kg", want: Tuple{Value: 1.5, Unit: UnitKilos}},
+		{value: ".5l", want: Tuple{Value: 0.5, Unit: UnitLitres}},
+		{value: "2 lb", want: Tuple{Value: 2, Unit: UnitPounds}},
+		{value: "1 lb 4 oz", want: Tuple{Value: 1.25, Unit: UnitPounds}},
+		{value: "8 fl oz", want: Tuple{Value: 8, Unit: UnitFluidOunces}},
+		{value: "1 qt", want: Tuple{Value: 1, Unit: UnitQuarts}},
+		{value: "1/2 cup", want: Tuple{Value: 0.5, Unit: UnitCups}},
+		{value: "1 1/2 cups", want: Tuple{Value: 1.5, Unit: UnitCups}},
+		{value: "10", wantErr: true},
+		{value: "10gg", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseTuple(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTuple() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTuple() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppropriate(t *testing.T) {
+	tests := []struct {
+		value Tuple
+		want  Tuple
+	}{
+		{value: UnitGrams.Tuple(1500), want: UnitKilos.Tuple(1.5)},
+		{value: UnitOunces.Tuple(20), want: UnitPounds.Tuple(1.25)},
+		{value: UnitPounds.Tuple(0.5), want: UnitOunces.Tuple(8)},
+		{value: UnitFluidOunces.Tuple(64), want: UnitQuarts.Tuple(2)},
+		{value: UnitQuarts.Tuple(0.25), want: UnitFluidOunces.Tuple(8)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value.Format(), func(t *testing.T) {
+			if got := tt.value.Appropriate(); got != tt.want {
+				t.Errorf("Appropriate() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPropertiesConvert(t *testing.T) {
+	tests := []struct {
+		name    string
+		props   Properties
+		value   float64
+		from    Unit
+		to      Unit
+		want    float64
+		wantErr bool
+	}{
+		{name: "density overrides kind", props: Properties{Kind: KindFlour, Density: 0.8}, value: 100, from: UnitMillilitres, to: UnitGrams, want: 80},
+		{name: "kind density", props: Properties{Kind: KindFlour}, value: 100, from: UnitMillilitres, to: UnitGrams, want: 66},
+		{name: "no density", props: Properties{Kind: KindOther}, value: 100, from: UnitMillilitres, to: UnitGrams, wantErr: true},
+		{name: "default egg weight", props: Properties{Kind: KindEgg}, value: 3, from: UnitPieces, to: UnitGrams, want: 150},
+		{name: "piece weight", props: Properties{Kind: KindEgg, PieceWeight: 60}, value: 300, from: UnitGrams, to: UnitPieces, want: 5},
+		{name: "no piece weight", props: Properties{Kind: KindFlour}, value: 1, from: UnitPieces, to: UnitGrams, wantErr: true},
+		{name: "pieces to volume", props: Properties{Kind: KindOther, Density: 2, PieceWeight: 10}, value: 2, from: UnitPieces, to: UnitMillilitres, want: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.props.Convert(tt.value, tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Convert() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGuessKind(t *testing.T) {
+	tests := []struct {
+		name string
+		want Kind
+	}{
+		{name: "Whole Wheat Flour", want: KindFlour},
+		{name: "cornmeal", want: KindFlour},
+		{name: "Salted Butter", want: KindButter},
+		{name: "buttermilk", want: KindMilk},
+		{name: "Eggs", want: KindEgg},
+		{name: "egg yolks", want: KindEgg},
+		{name: "Sourdough Starter", want: KindSourdough},
+		{name: "Levain", want: KindSourdough},
+		{name: "instant yeast", want: KindYeast},
+		{name: "lukewarm water", want: KindWater},
+		{name: "sea salt", want: KindSalt},
+		{name: "Molasses", want: KindSugar},
+		{name: "extra-virgin olive oil", want: KindOil},
+		{name: "Raisins", want: KindOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GuessKind(tt.name); got != tt.want {
+				t.Errorf("GuessKind() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnitCategory(t *testing.T) {
+	tests := []struct {
+		unit Unit
+		want UnitCategory
+	}{
+		{unit: UnitOunces, want: UnitCategoryWeight},
+		{unit: UnitMillilitres, want: UnitCategoryVolume},
+		{unit: UnitCups, want: UnitCategoryVolume},
+		{unit: UnitTablespoons, want: UnitCategoryTeaspoon},
+		{unit: UnitPinches, want: UnitCategoryTeaspoon},
+		{unit: UnitPieces, want: UnitCategoryCount},
+		{unit: Unit("bunch"), want: UnitCategoryUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.unit), func(t *testing.T) {
+			if got := tt.unit.Category(); got != tt.want {
+				t.Errorf("Category() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnitSystemConvert(t *testing.T) {
+	tests := []struct {
+		system UnitSystem
+		value  Tuple
+		want   Tuple
+	}{
+		{system: UnitSystemMetric, value: UnitGrams.Tuple(1500), want: UnitKilos.Tuple(1.5)},
+		{system: UnitSystemMetric, value: UnitPounds.Tuple(1), want: UnitGrams.Tuple(453.59237)},
+		{system: UnitSystemImperial, value: UnitGrams.Tuple(453.59237), want: UnitPounds.Tuple(1)},
+		{system: UnitSystemImperial, value: UnitMillilitres.Tuple(29.5735295625), want: UnitFluidOunces.Tuple(1)},
+		{system: UnitSystemImperial, value: UnitTeaspoons.Tuple(6), want: UnitTablespoons.Tuple(2)},
+		{system: UnitSystemImperial, value: UnitPieces.Tuple(3), want: UnitPieces.Tuple(3)},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.system)+" "+tt.value.Format(), func(t *testing.T) {
+			got := tt.system.Convert(tt.value)
+			if got.Unit != tt.want.Unit || math.Abs(got.Value-tt.want.Value) > 1e-6 {
+				t.Errorf("Convert() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
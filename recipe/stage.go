@@ -0,0 +1,328 @@
+package recipe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FinalDough is the stage of ingredients which don't belong to a named stage.
+const FinalDough = ""
+
+// StagePortion is the calculated ingredients of a single stage.
+type StagePortion struct {
+	// Name of the stage, FinalDough for the final dough.
+	Name        string
+	Ingredients []PortionIngredient
+}
+
+// StageNames returns the names of the stages used by templates in the order
+// they first appear. The final dough is always last.
+func StageNames(templates []RecipeIngredient) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, template := range templates {
+		if template.Stage == FinalDough {
+			continue
+		}
+
+		key := strings.ToLower(template.Stage)
+		if !seen[key] {
+			seen[key] = true
+			names = append(names, template.Stage)
+		}
+	}
+
+	return append(names, FinalDough)
+}
+
+// StageTemplates returns the templates belonging to the given stage.
+func StageTemplates(templates []RecipeIngredient, stage string) []RecipeIngredient {
+	var out []RecipeIngredient
+	for _, template := range templates {
+		if strings.EqualFold(template.Stage, stage) {
+			out = append(out, template)
+		}
+	}
+	return out
+}
+
+// IsStaged returns true if any template belongs to a named stage.
+func IsStaged(templates []RecipeIngredient) bool {
+	for _, template := range templates {
+		if template.Stage != FinalDough {
+			return true
+		}
+	}
+	return false
+}
+
+// CalculateStages calculates a multi-stage formula, e.g. a levain build and a
+// final dough.
+//
+// Every stage has its own baker's percentages relative to the stage's total
+// flour. A stage is used by a later stage through an ingredient with the same
+// name as the stage, e.g. a "Levain" ingredient of the final dough. The
+// dependencies are applied to the final dough and each used stage is scaled so
+// its total dough equals the amount the later stages use. Stages which are not
+// used by a later stage are calculated from the dependencies directly.
+func CalculateStages(templates []RecipeIngredient, dependencies []Dependency, scale float64) ([]StagePortion, error) {
+	names := StageNames(templates)
+
+	stages := make([]StagePortion, len(names))
+	for i := len(names) - 1; i >= 0; i-- {
+		name := names[i]
+		stageTemplates := StageTemplates(templates, name)
+
+		// Sum what the later stages use of this stage.
+		var (
+			used  Tuple
+			found bool
+		)
+		for _, later := range stages[i+1:] {
+			for _, ingredient := range later.Ingredients {
+				if name == FinalDough || !strings.EqualFold(ingredient.Name, name) {
+					continue
+				}
+
+				value, err := ingredient.Value.ConvertWith(UnitGrams, ingredient.Properties())
+				if err != nil {
+					return nil, fmt.Errorf("stage %s must be used by weight: %w", name, err)
+				}
+				used.Value += value.Value
+				found = true
+			}
+		}
+
+		// Make sure the stage isn't used before it's built.
+		for _, earlier := range names[:i] {
+			for _, template := range StageTemplates(templates, earlier) {
+				if name != FinalDough && strings.EqualFold(template.Name, name) {
+					return nil, fmt.Errorf("stage %s is used by %s before it's built", name, earlier)
+				}
+			}
+		}
+
+		deps := dependencies
+		if found {
+			deps = stageDependencies(stageTemplates, dependencies, UnitGrams.Tuple(used.Value))
+		}
+
+		ingredients, err := CalculateScaled(stageTemplates, deps, scale)
+		if err != nil {
+			return nil, fmt.Errorf("stage %s: %w", stageName(name), err)
+		}
+
+		stages[i] = StagePortion{Name: name, Ingredients: ingredients}
+	}
+
+	return stages, nil
+}
+
+// stageDependencies returns the dependencies of a stage whose total dough is
+// fixed by the later stages. Dependencies which would fix its total flour
+// another way are dropped.
+func stageDependencies(templates []RecipeIngredient, dependencies []Dependency, totalDough Tuple) []Dependency {
+	s := newSolver(templates, dependencies, 1)
+
+	deps := []Dependency{{Label: DependencyTotalDough, Value: totalDough}}
+	for _, dep := range dependencies {
+		if dep.Label == DependencyTotalFlour || s.isDerived(dep.Label) {
+			continue
+		}
+		deps = append(deps, dep)
+	}
+	return deps
+}
+
+func stageName(name string) string {
+	if name == FinalDough {
+		return "final dough"
+	}
+	return name
+}
+
+// stageSolver resolves the amounts of a multi-stage formula as terms of the
+// total flour of the final dough. A recipe without stages is a single final
+// dough.
+type stageSolver struct {
+	names   []string
+	solvers []*solver
+}
+
+func newStageSolver(templates []RecipeIngredient) *stageSolver {
+	s := &stageSolver{names: StageNames(templates)}
+	for _, name := range s.names {
+		s.solvers = append(s.solvers, newSolver(StageTemplates(templates, name), nil, 1))
+	}
+	return s
+}
+
+// solve returns the total flour of the final dough in grams for which the
+// amount referred to by dep equals dep's value. Labels of the final dough
+// take precedence, other labels refer to the first stage with an ingredient
+// of that name.
+func (s *stageSolver) solve(dep Dependency) (float64, error) {
+	final := s.solvers[len(s.solvers)-1]
+	if final.isDerived(dep.Label) {
+		return final.solve(dep)
+	}
+
+	for i, stage := range s.solvers[:len(s.solvers)-1] {
+		if stage.index(dep.Label) < 0 {
+			continue
+		}
+
+		t, props, err := stage.amount(dep.Label)
+		if err != nil {
+			return 0, fmt.Errorf("failed to resolve dependency %s: %w", dep.Label, err)
+		}
+
+		flour, err := s.flour(i)
+		if err != nil {
+			return 0, fmt.Errorf("failed to resolve dependency %s: %w", dep.Label, err)
+		}
+
+		return solveTerm(dep, t.of(flour), props)
+	}
+
+	return final.solve(dep)
+}
+
+// flour returns the total flour of the i-th stage as a term of the total
+// flour of the final dough. Like CalculateStages, a stage used by later
+// stages is scaled so its total dough equals what they use, and a stage which
+// isn't used has the total flour of the final dough.
+func (s *stageSolver) flour(i int) (term, error) {
+	identity := term{Coef: 1, Const: UnitGrams.Tuple(0)}
+	if i == len(s.names)-1 {
+		return identity, nil
+	}
+
+	var (
+		used  = zeroTerm
+		found bool
+	)
+	for j := i + 1; j < len(s.names); j++ {
+		later := s.solvers[j]
+		for k, template := range later.templates {
+			if !strings.EqualFold(template.Name, s.names[i]) {
+				continue
+			}
+
+			t, ok, err := later.weigh(k)
+			if err != nil {
+				return term{}, err
+			}
+			if !ok {
+				return term{}, fmt.Errorf("stage %s must be used by weight", s.names[i])
+			}
+
+			flour, err := s.flour(j)
+			if err != nil {
+				return term{}, err
+			}
+
+			if used, err = used.add(t.of(flour)); err != nil {
+				return term{}, err
+			}
+			found = true
+		}
+	}
+	if !found {
+		return identity, nil
+	}
+
+	dough, err := s.solvers[i].resolve(DependencyTotalDough)
+	if err != nil {
+		return term{}, err
+	}
+	if dough.Coef == 0 {
+		return term{}, fmt.Errorf("stage %s does not depend on %s", s.names[i], DependencyTotalFlour)
+	}
+
+	return term{
+		Coef:  used.Coef / dough.Coef,
+		Const: UnitGrams.Tuple((used.Const.Value - dough.Const.Value) / dough.Coef),
+	}, nil
+}
+
+// isStageOutput returns true if ingredient is the output of one of the stages.
+func isStageOutput(stages []StagePortion, ingredient PortionIngredient) bool {
+	for _, stage := range stages {
+		if stage.Name != FinalDough && strings.EqualFold(stage.Name, ingredient.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Overall folds the stages of a formula into a single list of ingredients.
+// Stage outputs used by later stages are replaced by the ingredients of the
+// stage, so the prefermented flour and water count towards the totals.
+// Ingredients with the same name are added together.
+func Overall(stages []StagePortion) []PortionIngredient {
+	var (
+		out   []PortionIngredient
+		index = map[string]int{}
+	)
+	for _, stage := range stages {
+		for _, ingredient := range stage.Ingredients {
+			if isStageOutput(stages, ingredient) {
+				continue
+			}
+
+			key := strings.ToLower(ingredient.Name)
+			i, ok := index[key]
+			if !ok {
+				index[key] = len(out)
+				out = append(out, ingredient)
+				continue
+			}
+
+			if sum, ok := addPortions(out[i], ingredient); ok {
+				out[i] = sum
+			} else {
+				out = append(out, ingredient)
+			}
+		}
+	}
+
+	return out
+}
+
+// addPortions adds the amounts of two portions of the same ingredient. It
+// returns false if the amounts can't be added.
+func addPortions(a, b PortionIngredient) (PortionIngredient, bool) {
+	if a.Value.Unit == b.Value.Unit {
+		a.Value.Value += b.Value.Value
+		return a, true
+	}
+
+	av, err := a.Value.ConvertWith(UnitGrams, a.Properties())
+	if err != nil {
+		return a, false
+	}
+
+	bv, err := b.Value.ConvertWith(UnitGrams, b.Properties())
+	if err != nil {
+		return a, false
+	}
+
+	a.Value = UnitGrams.Tuple(av.Value + bv.Value)
+	return a, true
+}
+
+// TotalFlour returns the sum of all flour ingredients in grams.
+func TotalFlour(ingredients []PortionIngredient) float64 {
+	var total float64
+	for _, ingredient := range ingredients {
+		if ingredient.Kind != KindFlour {
+			continue
+		}
+
+		if v, err := ingredient.Value.ConvertWith(UnitGrams, ingredient.Properties()); err == nil {
+			total += v.Value
+		}
+	}
+	return total
+}
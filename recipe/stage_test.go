@@ -0,0 +1,118 @@
+package recipe
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCalculateStages(t *testing.T) {
+	templates := []RecipeIngredient{
+		{Name: "Whole Grain Flour", Kind: KindFlour, Percentage: 1, Dependency: "total_flour", Stage: "Levain"},
+		{Name: "Water", Kind: KindWater, Percentage: 1, Dependency: "total_flour", Stage: "Levain"},
+		{Name: "Sourdough Starter", Kind: KindSourdough, Percentage: 0.2, Dependency: "total_flour", Stage: "Levain"},
+		{Name: "Bread Flour", Kind: KindFlour, Percentage: 1, Dependency: "total_flour"},
+		{Name: "Water", Kind: KindWater, Percentage: 0.7, Dependency: "total_flour"},
+		{Name: "Levain", Kind: KindSourdough, Percentage: 0.22, Dependency: "total_flour"},
+		{Name: "Salt", Kind: KindSalt, Percentage: 0.02, Dependency: "total_flour"},
+	}
+	deps := []Dependency{{Label: "total_flour", Value: Tuple{Value: 1000, Unit: UnitGrams}}}
+
+	stages, err := CalculateStages(templates, deps, 1)
+	if err != nil {
+		t.Fatalf("CalculateStages() error = %v", err)
+	}
+
+	if len(stages) != 2 || stages[0].Name != "Levain" || stages[1].Name != FinalDough {
+		t.Fatalf("CalculateStages() got stages %v", stages)
+	}
+
+	want := map[string][]float64{
+		"Levain":   {100, 100, 20},
+		FinalDough: {1000, 700, 220, 20},
+	}
+	for _, stage := range stages {
+		for i, w := range want[stage.Name] {
+			if got := stage.Ingredients[i].Value.Value; math.Abs(got-w) > 1e-9 {
+				t.Errorf("CalculateStages() %s %s got = %v, want %v", stage.Name, stage.Ingredients[i].Name, got, w)
+			}
+		}
+	}
+
+	overall := Overall(stages)
+	wantOverall := map[string]float64{
+		"Whole Grain Flour": 100,
+		"Water":             800,
+		"Sourdough Starter": 20,
+		"Bread Flour":       1000,
+		"Salt":              20,
+	}
+	if len(overall) != len(wantOverall) {
+		t.Fatalf("Overall() got %d ingredients, want %d", len(overall), len(wantOverall))
+	}
+	for _, ingredient := range overall {
+		if w := wantOverall[ingredient.Name]; math.Abs(ingredient.Value.Value-w) > 1e-9 {
+			t.Errorf("Overall() %s got = %v, want %v", ingredient.Name, ingredient.Value.Value, w)
+		}
+	}
+
+	if got := TotalFlour(overall); got != 1100 {
+		t.Errorf("TotalFlour() got = %v, want 1100", got)
+	}
+}
+
+func TestLimitStages(t *testing.T) {
+	templates := []RecipeIngredient{
+		{Name: "Whole Grain Flour", Kind: KindFlour, Percentage: 1, Dependency: "total_flour", Stage: "Levain"},
+		{Name: "Water", Kind: KindWater, Percentage: 1, Dependency: "total_flour", Stage: "Levain"},
+		{Name: "Sourdough Starter", Kind: KindSourdough, Percentage: 0.2, Dependency: "total_flour", Stage: "Levain"},
+		{Name: "Bread Flour", Kind: KindFlour, Percentage: 1, Dependency: "total_flour"},
+		{Name: "Water", Kind: KindWater, Percentage: 0.7, Dependency: "total_flour"},
+		{Name: "Levain", Kind: KindSourdough, Percentage: 0.22, Dependency: "total_flour"},
+		{Name: "Salt", Kind: KindSalt, Percentage: 0.02, Dependency: "total_flour"},
+	}
+
+	tests := []struct {
+		limit Dependency
+		want  float64
+	}{
+		// 1000g flour uses 220g levain built from 20g starter.
+		{limit: Dependency{Label: "Sourdough Starter", Value: Tuple{Value: 23, Unit: UnitGrams}}, want: 1150},
+		{limit: Dependency{Label: "whole grain flour", Value: Tuple{Value: 50, Unit: UnitGrams}}, want: 500},
+		// Water of the final dough takes precedence over that of the levain.
+		{limit: Dependency{Label: "Water", Value: Tuple{Value: 350, Unit: UnitGrams}}, want: 500},
+		{limit: Dependency{Label: "Levain", Value: Tuple{Value: 440, Unit: UnitGrams}}, want: 2000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.limit.Label, func(t *testing.T) {
+			bounds, _, err := Limit(templates, []Dependency{tt.limit})
+			if err != nil {
+				t.Fatalf("Limit() error = %v", err)
+			}
+			got := bounds[0].TotalFlour.Value
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Fatalf("Limit() got = %v, want %v", got, tt.want)
+			}
+
+			// The limited ingredient is the limit in the calculated
+			// formula, looking at the final dough first.
+			stages, err := CalculateStages(templates, []Dependency{{Label: DependencyTotalFlour, Value: bounds[0].TotalFlour}}, 1)
+			if err != nil {
+				t.Fatalf("CalculateStages() error = %v", err)
+			}
+			for i := len(stages) - 1; i >= 0; i-- {
+				stage := stages[i]
+				for _, ingredient := range stage.Ingredients {
+					if !strings.EqualFold(ingredient.Name, tt.limit.Label) {
+						continue
+					}
+					if math.Abs(ingredient.Value.Value-tt.limit.Value.Value) > 1e-9 {
+						t.Errorf("CalculateStages() %s %s got = %v, want %v", stageName(stage.Name), ingredient.Name, ingredient.Value.Value, tt.limit.Value.Value)
+					}
+					return
+				}
+			}
+			t.Errorf("CalculateStages() has no %s", tt.limit.Label)
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package recipe
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DurationRange is how long a step takes, e.g. 12–16h for a cold retard.
+// Min equals Max for a fixed duration and both are zero for a step without a
+// duration.
+type DurationRange struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// FixedDuration returns a range of a single duration.
+func FixedDuration(d time.Duration) DurationRange {
+	return DurationRange{Min: d, Max: d}
+}
+
+// ParseDurationRange parses a duration such as "30m", "1h30m", "20 min" or
+// "2 days", or a range of two durations such as "12-16h" or "45m-1h". The
+// unit of the upper bound applies to a lower bound without a unit.
+func ParseDurationRange(value string) (DurationRange, error) {
+	value = strings.TrimSpace(value)
+	lower, upper, ok := strings.Cut(strings.ReplaceAll(value, "–", "-"), "-")
+	if !ok {
+		d, err := parseDuration(value)
+		if err != nil {
+			return DurationRange{}, err
+		}
+		return FixedDuration(d), nil
+	}
+
+	lower, upper = strings.TrimSpace(lower), strings.TrimSpace(upper)
+	if _, err := strconv.ParseFloat(lower, 64); err == nil {
+		if m := durationUnitRegexp.FindStringSubmatch(upper); m != nil {
+			lower += m[1]
+		}
+	}
+
+	min, err := parseDuration(lower)
+	if err != nil {
+		return DurationRange{}, err
+	}
+	max, err := parseDuration(upper)
+	if err != nil {
+		return DurationRange{}, err
+	}
+	if max < min {
+		return DurationRange{}, fmt.Errorf("invalid duration %s: %s is shorter than %s", value, upper, lower)
+	}
+	return DurationRange{Min: min, Max: max}, nil
+}
+
+var (
+	durationPartRegexp = regexp.MustCompile(`(\d+\.?\d*|\.\d+)\s*(days?|d|hours?|hrs?|h|minutes?|mins?|m)`)
+	durationUnitRegexp = regexp.MustCompile(`\d\s*([a-z]+)\s*$`)
+)
+
+// parseDuration parses a duration made of days, hours and minutes.
+func parseDuration(value string) (time.Duration, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	matches := durationPartRegexp.FindAllStringSubmatchIndex(value, -1)
+	if len(matches) == 0 {
+		return 0, fmt.Errorf("invalid duration: %s", value)
+	}
+
+	var (
+		d   time.Duration
+		end int
+	)
+	for _, m := range matches {
+		if strings.TrimSpace(value[end:m[0]]) != "" {
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+		end = m[1]
+
+		v, err := strconv.ParseFloat(value[m[2]:m[3]], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+
+		unit := time.Minute
+		switch value[m[4]] {
+		case 'd':
+			unit = 24 * time.Hour
+		case 'h':
+			unit = time.Hour
+		}
+		d += time.Duration(v * float64(unit))
+	}
+	if strings.TrimSpace(value[end:]) != "" {
+		return 0, fmt.Errorf("invalid duration: %s", value)
+	}
+
+	return d.Round(time.Minute), nil
+}
+
+// IsZero returns true if the step has no duration.
+func (d DurationRange) IsZero() bool {
+	return d.Min == 0 && d.Max == 0
+}
+
+// Add returns the sum of two ranges.
+func (d DurationRange) Add(other DurationRange) DurationRange {
+	return DurationRange{Min: d.Min + other.Min, Max: d.Max + other.Max}
+}
+
+// Format formats the range, e.g. "30min", "1h30min", "20–25min" or "12–16h".
+func (d DurationRange) Format() string {
+	if d.Min == d.Max {
+		return formatDuration(d.Min)
+	}
+
+	min, max := formatDuration(d.Min), formatDuration(d.Max)
+	switch {
+	case d.Min%time.Hour == 0 && d.Max%time.Hour == 0:
+		min = strings.TrimSuffix(min, "h")
+	case d.Min < time.Hour && d.Max < time.Hour:
+		min = strings.TrimSuffix(min, "min")
+	}
+	return min + "–" + max
+}
+
+func (d DurationRange) String() string {
+	return d.Format()
+}
+
+func formatDuration(d time.Duration) string {
+	hours, minutes := int(d/time.Hour), int((d%time.Hour)/time.Minute)
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dmin", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%dmin", hours, minutes)
+	}
+}
+
+// Step is a step of the method of a recipe, e.g. "Bulk ferment" for 5h at
+// 24°C. Ingredients are the ingredients of the recipe added in the step.
+type Step struct {
+	Text        string
+	Duration    DurationRange
+	Temperature *Temperature
+	Ingredients []StepIngredient
+}
+
+// StepIngredient is an ingredient of a recipe added in a step. The stage
+// tells apart ingredients of the same name, e.g. the water of a levain and
+// of the final dough.
+type StepIngredient struct {
+	Name  string
+	Stage string
+}
+
+// IngredientNames returns the names of the ingredients added in the step.
+func (s Step) IngredientNames() []string {
+	names := make([]string, len(s.Ingredients))
+	for i, ingredient := range s.Ingredients {
+		names[i] = ingredient.Name
+	}
+	return names
+}
+
+// TotalDuration returns the sum of the durations of the steps.
+func TotalDuration(steps []Step) DurationRange {
+	var total DurationRange
+	for _, step := range steps {
+		total = total.Add(step.Duration)
+	}
+	return total
+}
+
+// TimeRange is a window of clock times. Earliest equals Latest if the steps
+// before have fixed durations.
+type TimeRange struct {
+	Earliest time.Time
+	Latest   time.Time
+}
+
+// IsFixed returns true if the window is a single time.
+func (t TimeRange) IsFixed() bool {
+	return t.Earliest.Equal(t.Latest)
+}
+
+// ScheduledStep is a step with the windows of clock times it starts and ends.
+type ScheduledStep struct {
+	Step
+	Start TimeRange
+	End   TimeRange
+}
+
+// Schedule back-calculates a timeline from the time the last step should
+// end, e.g. when the bread comes out of the oven. A step starts at the
+// earliest if all steps from it onwards take their longest duration and at
+// the latest if they take their shortest.
+func Schedule(steps []Step, readyAt time.Time) []ScheduledStep {
+	out := make([]ScheduledStep, len(steps))
+	end := TimeRange{Earliest: readyAt, Latest: readyAt}
+	for i := len(steps) - 1; i >= 0; i-- {
+		start := TimeRange{
+			Earliest: end.Earliest.Add(-steps[i].Duration.Max),
+			Latest:   end.Latest.Add(-steps[i].Duration.Min),
+		}
+		out[i] = ScheduledStep{Step: steps[i], Start: start, End: end}
+		end = start
+	}
+	return out
+}
+
+// ScheduleFrom calculates a timeline forwards from the time the first step
+// starts. A step ends at the earliest if all steps up to it take their
+// shortest duration and at the latest if they take their longest.
+func ScheduleFrom(steps []Step, startAt time.Time) []ScheduledStep {
+	out := make([]ScheduledStep, len(steps))
+	start := TimeRange{Earliest: startAt, Latest: startAt}
+	for i, step := range steps {
+		end := TimeRange{
+			Earliest: start.Earliest.Add(step.Duration.Min),
+			Latest:   start.Latest.Add(step.Duration.Max),
+		}
+		out[i] = ScheduledStep{Step: step, Start: start, End: end}
+		start = end
+	}
+	return out
+}
+
+// JoinList joins items as "a, b and c", e.g. the ingredients mixed in a step.
+func JoinList(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
@@ -0,0 +1,108 @@
+package recipe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDurationRange(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    DurationRange
+		format  string
+		wantErr bool
+	}{
+		{value: "30m", want: FixedDuration(30 * time.Minute), format: "30min"},
+		{value: "30 min", want: FixedDuration(30 * time.Minute), format: "30min"},
+		{value: "1h30m", want: FixedDuration(90 * time.Minute), format: "1h30min"},
+		{value: "1.5 hours", want: FixedDuration(90 * time.Minute), format: "1h30min"},
+		{value: "5h", want: FixedDuration(5 * time.Hour), format: "5h"},
+		{value: "2 days", want: FixedDuration(48 * time.Hour), format: "48h"},
+		{value: "12-16h", want: DurationRange{Min: 12 * time.Hour, Max: 16 * time.Hour}, format: "12–16h"},
+		{value: "12h–16h", want: DurationRange{Min: 12 * time.Hour, Max: 16 * time.Hour}, format: "12–16h"},
+		{value: "20-25 min", want: DurationRange{Min: 20 * time.Minute, Max: 25 * time.Minute}, format: "20–25min"},
+		{value: "45m-1h", want: DurationRange{Min: 45 * time.Minute, Max: time.Hour}, format: "45min–1h"},
+		{value: "16-12h", wantErr: true},
+		{value: "5", wantErr: true},
+		{value: "5 weeks", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseDurationRange(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDurationRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseDurationRange() got = %v, want %v", got, tt.want)
+			}
+			if f := got.Format(); f != tt.format {
+				t.Errorf("Format() got = %s, want %s", f, tt.format)
+			}
+		})
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	steps := []Step{
+		{Text: "Autolyse", Duration: FixedDuration(30 * time.Minute)},
+		{Text: "Bulk", Duration: FixedDuration(5 * time.Hour)},
+		{Text: "Shape"},
+		{Text: "Cold retard", Duration: DurationRange{Min: 12 * time.Hour, Max: 16 * time.Hour}},
+		{Text: "Bake", Duration: FixedDuration(40 * time.Minute)},
+	}
+	readyAt := time.Date(2026, 10, 20, 8, 0, 0, 0, time.UTC)
+
+	got := Schedule(steps, readyAt)
+
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC)
+	}
+	want := []TimeRange{
+		{Earliest: at(19, 9, 50), Latest: at(19, 13, 50)},
+		{Earliest: at(19, 10, 20), Latest: at(19, 14, 20)},
+		{Earliest: at(19, 15, 20), Latest: at(19, 19, 20)},
+		{Earliest: at(19, 15, 20), Latest: at(19, 19, 20)},
+		{Earliest: at(20, 7, 20), Latest: at(20, 7, 20)},
+	}
+	for i, w := range want {
+		if !got[i].Start.Earliest.Equal(w.Earliest) || !got[i].Start.Latest.Equal(w.Latest) {
+			t.Errorf("Schedule() %s starts %v, want %v", got[i].Text, got[i].Start, w)
+		}
+	}
+	if last := got[len(got)-1]; !last.End.IsFixed() || !last.End.Earliest.Equal(readyAt) {
+		t.Errorf("Schedule() ends %v, want %v", last.End, readyAt)
+	}
+
+	from := ScheduleFrom(steps, want[0].Latest)
+	end := from[len(from)-1].End
+	if !end.Earliest.Equal(readyAt) || !end.Latest.Equal(readyAt.Add(4*time.Hour)) {
+		t.Errorf("ScheduleFrom() ends %v", end)
+	}
+
+	if total := TotalDuration(steps); total.Min != 18*time.Hour+10*time.Minute || total.Max != 22*time.Hour+10*time.Minute {
+		t.Errorf("TotalDuration() got = %v", total)
+	}
+}
+
+func TestJoinList(t *testing.T) {
+	tests := []struct {
+		items []string
+		want  string
+	}{
+		{items: nil, want: ""},
+		{items: []string{"flour"}, want: "flour"},
+		{items: []string{"flour", "water"}, want: "flour and water"},
+		{items: []string{"flour", "water", "salt"}, want: "flour, water and salt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := JoinList(tt.items); got != tt.want {
+				t.Errorf("JoinList() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
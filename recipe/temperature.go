@@ -0,0 +1,119 @@
+package recipe
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TemperatureScale is a temperature scale.
+type TemperatureScale string
+
+const (
+	Celsius    TemperatureScale = "C"
+	Fahrenheit TemperatureScale = "F"
+)
+
+// Temperature is a temperature value on a given scale.
+type Temperature struct {
+	Value float64
+	Scale TemperatureScale
+}
+
+// ParseTemperature parses a temperature string such as "25C", "77F" or
+// "25 °C" into a Temperature.
+func ParseTemperature(value string) (Temperature, error) {
+	matches := temperatureRegexp.FindStringSubmatch(value)
+	if len(matches) != 3 {
+		return Temperature{}, fmt.Errorf("invalid temperature string: %s", value)
+	}
+
+	v, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return Temperature{}, fmt.Errorf("invalid temperature value: %w", err)
+	}
+
+	return Temperature{
+		Value: v,
+		Scale: TemperatureScale(strings.ToUpper(matches[2])),
+	}, nil
+}
+
+var temperatureRegexp = regexp.MustCompile(`^\s*(?P<value>-?\d+\.?\d*|-?\.\d+)\s*°?\s*(?P<scale>[CcFf])\s*$`)
+
+// Celsius returns the temperature in degrees Celsius.
+func (t Temperature) Celsius() float64 {
+	if t.Scale == Fahrenheit {
+		return (t.Value - 32) * 5 / 9
+	}
+	return t.Value
+}
+
+// Fahrenheit returns the temperature in degrees Fahrenheit.
+func (t Temperature) Fahrenheit() float64 {
+	if t.Scale == Fahrenheit {
+		return t.Value
+	}
+	return t.Value*9/5 + 32
+}
+
+// Convert converts the temperature to the given scale.
+func (t Temperature) Convert(scale TemperatureScale) Temperature {
+	if scale == Fahrenheit {
+		return Temperature{Value: t.Fahrenheit(), Scale: Fahrenheit}
+	}
+	return Temperature{Value: t.Celsius(), Scale: Celsius}
+}
+
+// Difference returns the temperature as a difference in degrees Celsius,
+// e.g. 9F is a rise of 5C. It's used for values such as the friction factor
+// which are not absolute temperatures.
+func (t Temperature) Difference() float64 {
+	if t.Scale == Fahrenheit {
+		return t.Value * 5 / 9
+	}
+	return t.Value
+}
+
+// Format formats the temperature with one decimal, e.g. "25.0°C".
+func (t Temperature) Format() string {
+	return fmt.Sprintf("%.1f°%s", t.Value, t.Scale)
+}
+
+func (t Temperature) String() string {
+	return t.Format()
+}
+
+// DDT holds the inputs to calculate the water temperature needed to reach a
+// desired dough temperature.
+type DDT struct {
+	// Target is the desired dough temperature.
+	Target Temperature
+
+	// Room, Flour and Starter are the temperatures of the room and the
+	// ingredients. A nil Starter means the dough has no starter.
+	Room    Temperature
+	Flour   Temperature
+	Starter *Temperature
+
+	// Friction is the temperature rise caused by mixing.
+	Friction Temperature
+}
+
+// WaterTemperature returns the water temperature needed to reach the desired
+// dough temperature, on the scale of the target. Each temperature factor
+// contributes equally to the dough temperature, so the water makes up the
+// difference between the target times the number of factors and the sum of
+// the other factors.
+func WaterTemperature(ddt DDT) Temperature {
+	factors := 3.0
+	sum := ddt.Room.Celsius() + ddt.Flour.Celsius() + ddt.Friction.Difference()
+	if ddt.Starter != nil {
+		factors++
+		sum += ddt.Starter.Celsius()
+	}
+
+	water := Temperature{Value: ddt.Target.Celsius()*factors - sum, Scale: Celsius}
+	return water.Convert(ddt.Target.Scale)
+}
@@ -0,0 +1,80 @@
+package recipe
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseTemperature(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Temperature
+		wantErr bool
+	}{
+		{value: "25C", want: Temperature{Value: 25, Scale: Celsius}},
+		{value: "77f", want: Temperature{Value: 77, Scale: Fahrenheit}},
+		{value: "25.5 °C", want: Temperature{Value: 25.5, Scale: Celsius}},
+		{value: "-4F", want: Temperature{Value: -4, Scale: Fahrenheit}},
+		{value: "25", wantErr: true},
+		{value: "25K", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseTemperature(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTemperature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTemperature() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaterTemperature(t *testing.T) {
+	starter := Temperature{Value: 24, Scale: Celsius}
+	tests := []struct {
+		name string
+		ddt  DDT
+		want Temperature
+	}{
+		{
+			name: "with starter",
+			ddt: DDT{
+				Target:   Temperature{Value: 25, Scale: Celsius},
+				Room:     Temperature{Value: 22, Scale: Celsius},
+				Flour:    Temperature{Value: 21, Scale: Celsius},
+				Starter:  &starter,
+				Friction: Temperature{Value: 1, Scale: Celsius},
+			},
+			want: Temperature{Value: 32, Scale: Celsius},
+		},
+		{
+			name: "without starter",
+			ddt: DDT{
+				Target: Temperature{Value: 25, Scale: Celsius},
+				Room:   Temperature{Value: 22, Scale: Celsius},
+				Flour:  Temperature{Value: 21, Scale: Celsius},
+			},
+			want: Temperature{Value: 32, Scale: Celsius},
+		},
+		{
+			name: "fahrenheit",
+			ddt: DDT{
+				Target:   Temperature{Value: 78, Scale: Fahrenheit},
+				Room:     Temperature{Value: 72, Scale: Fahrenheit},
+				Flour:    Temperature{Value: 70, Scale: Fahrenheit},
+				Friction: Temperature{Value: 9, Scale: Fahrenheit},
+			},
+			want: Temperature{Value: 83, Scale: Fahrenheit},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WaterTemperature(tt.ddt)
+			if got.Scale != tt.want.Scale || math.Abs(got.Value-tt.want.Value) > 1e-9 {
+				t.Errorf("WaterTemperature() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package recipe
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Severity is how serious an Issue is. An error makes the recipe wrong or
+// impossible to calculate, a warning is unusual baker's math.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Checks of Validate.
+const (
+	CheckNoFlour           = "no-flour"
+	CheckFlourSum          = "flour-sum"
+	CheckDuplicate         = "duplicate"
+	CheckUnknownDependency = "unknown-dependency"
+	CheckCalculation       = "calculation"
+	CheckMissingKind       = "missing-kind"
+	CheckHydration         = "hydration"
+	CheckSalt              = "salt"
+	CheckStarter           = "starter"
+)
+
+// Usual ranges of the baker's math checked by Validate, as ratios of the
+// flour.
+var (
+	HydrationRange = [2]float64{0.5, 1}
+	SaltRange      = [2]float64{0.015, 0.03}
+	StarterRange   = [2]float64{0.05, 1}
+)
+
+// Issue is a problem of a recipe found by Validate. Stage and Ingredient are
+// empty if the issue concerns the final dough or no single ingredient.
+type Issue struct {
+	Severity   Severity
+	Check      string
+	Stage      string
+	Ingredient string
+	Message    string
+}
+
+func (i Issue) String() string {
+	var where []string
+	if i.Stage != FinalDough {
+		where = append(where, i.Stage)
+	}
+	if i.Ingredient != "" {
+		where = append(where, i.Ingredient)
+	}
+	if len(where) == 0 {
+		return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.Severity, strings.Join(where, ": "), i.Message)
+}
+
+// HasErrors returns true if any of the issues is an error.
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// nominalDependencies are used to calculate a template for validation. Only
+// the ratios of the result are meaningful.
+var nominalDependencies = []Dependency{
+	{Label: DependencyTotalFlour, Value: UnitGrams.Tuple(1000)},
+	{Label: DependencyPerPiece, Value: UnitGrams.Tuple(100)},
+}
+
+// Validate checks the baker's math of a recipe template and returns the
+// issues found, stage by stage with the final dough last.
+//
+// The structure of every stage is checked first: it must have flour, the
+// flour percentages of total_flour must sum to 100%, names must be unique
+// and dependencies must refer to a known label or an ingredient of the
+// stage. Without errors the template is then calculated to check the
+// hydration, salt and starter of the dough against their usual ranges.
+func Validate(templates []RecipeIngredient) []Issue {
+	var issues []Issue
+	add := func(severity Severity, check, stage, ingredient, format string, args ...any) {
+		issues = append(issues, Issue{
+			Severity:   severity,
+			Check:      check,
+			Stage:      stage,
+			Ingredient: ingredient,
+			Message:    fmt.Sprintf(format, args...),
+		})
+	}
+
+	var hasFlour bool
+	for _, stage := range StageNames(templates) {
+		stageTemplates := StageTemplates(templates, stage)
+		if len(stageTemplates) == 0 {
+			continue
+		}
+
+		var (
+			flour    int
+			sum      float64
+			summable = true
+			names    = map[string]bool{}
+			reported = map[string]bool{}
+		)
+		for _, template := range stageTemplates {
+			key := strings.ToLower(template.Name)
+			if names[key] && !reported[key] {
+				reported[key] = true
+				add(SeverityWarning, CheckDuplicate, stage, template.Name, "is listed more than once")
+			}
+			names[key] = true
+
+			if template.Kind == KindOther {
+				if guess := GuessKind(template.Name); guess != KindOther {
+					add(SeverityWarning, CheckMissingKind, stage, template.Name, "has no kind, it looks like %s", guess)
+				} else {
+					add(SeverityWarning, CheckMissingKind, stage, template.Name, "has no kind and is left out of the baker's math")
+				}
+			}
+
+			if template.Kind != KindFlour {
+				continue
+			}
+			flour++
+			if template.Mode == ModePercentage && template.Dependency == DependencyTotalFlour {
+				sum += template.Percentage
+			} else {
+				summable = false
+			}
+		}
+
+		for _, template := range stageTemplates {
+			if template.Mode != ModePercentage || isKnownLabel(template.Dependency) || names[strings.ToLower(template.Dependency)] {
+				continue
+			}
+			add(SeverityError, CheckUnknownDependency, stage, template.Name, "depends on %q which is neither an ingredient of the %s nor one of total_flour, total_water, total_dough and per_piece", template.Dependency, stageName(stage))
+		}
+
+		switch {
+		case flour == 0 && stage == FinalDough && !IsStaged(templates):
+			// Reported below for the whole recipe.
+		case flour == 0:
+			add(SeverityWarning, CheckNoFlour, stage, "", "the %s has no flour", stageName(stage))
+		case summable && math.Abs(sum-1) > 1e-6:
+			add(SeverityError, CheckFlourSum, stage, "", "the flour of the %s sums to %s of total_flour, want 100%%", stageName(stage), formatRatio(sum))
+		}
+		hasFlour = hasFlour || flour > 0
+	}
+
+	if !hasFlour {
+		add(SeverityError, CheckNoFlour, FinalDough, "", "the recipe has no flour")
+	}
+
+	if HasErrors(issues) {
+		return issues
+	}
+
+	stages, err := CalculateStages(templates, nominalDependencies, 1)
+	if err != nil {
+		add(SeverityError, CheckCalculation, FinalDough, "", "%v", err)
+		return issues
+	}
+
+	for _, stage := range stages {
+		flour := TotalFlour(stage.Ingredients)
+		if flour == 0 {
+			continue
+		}
+
+		for _, ingredient := range stage.Ingredients {
+			if ingredient.Kind != KindSourdough {
+				continue
+			}
+			v, err := ingredient.Value.ConvertWith(UnitGrams, ingredient.Properties())
+			if err != nil {
+				continue
+			}
+			if r := v.Value / flour; outside(r, StarterRange) {
+				add(SeverityWarning, CheckStarter, stage.Name, ingredient.Name, "is %s of the flour of the %s, outside the usual %s", formatRatio(r), stageName(stage.Name), formatRange(StarterRange))
+			}
+		}
+	}
+
+	summary := SummarizeStages(stages)
+	if h := summary.Hydration(); summary.Water > 0 && outside(h, HydrationRange) {
+		add(SeverityWarning, CheckHydration, FinalDough, "", "hydration %s is outside the usual %s", formatRatio(h), formatRange(HydrationRange))
+	}
+	switch s := summary.SaltRatio(); {
+	case summary.Salt == 0:
+		add(SeverityWarning, CheckSalt, FinalDough, "", "the recipe has no salt")
+	case outside(s, SaltRange):
+		add(SeverityWarning, CheckSalt, FinalDough, "", "salt %s is outside the usual %s", formatRatio(s), formatRange(SaltRange))
+	}
+
+	return issues
+}
+
+// isKnownLabel returns true for the dependency labels with a special
+// meaning.
+func isKnownLabel(label string) bool {
+	switch label {
+	case DependencyTotalFlour, DependencyTotalWater, DependencyTotalDough, DependencyPerPiece:
+		return true
+	}
+	return false
+}
+
+func outside(value float64, bounds [2]float64) bool {
+	return value < bounds[0]-1e-9 || value > bounds[1]+1e-9
+}
+
+func formatRatio(value float64) string {
+	return fmt.Sprintf("%.1f%%", value*100)
+}
+
+// formatRange formats bounds without trailing zeros, e.g. "1.5–3%".
+func formatRange(bounds [2]float64) string {
+	format := func(v float64) string {
+		return strconv.FormatFloat(math.Round(v*1000)/10, 'f', -1, 64)
+	}
+	return format(bounds[0]) + "–" + format(bounds[1]) + "%"
+}
@@ -0,0 +1,128 @@
+package recipe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	pct := func(name string, kind Kind, percentage float64, dependency, stage string) RecipeIngredient {
+		return RecipeIngredient{
+			Name:               name,
+			Kind:               kind,
+			PreferUnitCategory: UnitCategoryWeight,
+			Mode:               ModePercentage,
+			Percentage:         percentage,
+			Dependency:         dependency,
+			Stage:              stage,
+		}
+	}
+
+	country := []RecipeIngredient{
+		pct("Whole Wheat Flour", KindFlour, 1, DependencyTotalFlour, "Levain"),
+		pct("Water", KindWater, 1, DependencyTotalFlour, "Levain"),
+		pct("Starter", KindSourdough, 0.2, DependencyTotalFlour, "Levain"),
+		pct("Bread Flour", KindFlour, 1, DependencyTotalFlour, FinalDough),
+		pct("Water", KindWater, 0.7, DependencyTotalFlour, FinalDough),
+		pct("Levain", KindSourdough, 0.22, DependencyTotalFlour, FinalDough),
+		pct("Salt", KindSalt, 0.02, DependencyTotalFlour, FinalDough),
+	}
+
+	tests := []struct {
+		name      string
+		templates []RecipeIngredient
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "valid",
+			templates: country,
+		},
+		{
+			name:      "no flour",
+			templates: []RecipeIngredient{pct("Water", KindWater, 0.7, DependencyTotalFlour, FinalDough)},
+			want:      []string{CheckNoFlour},
+			wantErr:   true,
+		},
+		{
+			name: "flour sum",
+			templates: []RecipeIngredient{
+				pct("Bread Flour", KindFlour, 0.8, DependencyTotalFlour, FinalDough),
+				pct("Rye Flour", KindFlour, 0.1, DependencyTotalFlour, FinalDough),
+				pct("Water", KindWater, 0.7, DependencyTotalFlour, FinalDough),
+				pct("Salt", KindSalt, 0.02, DependencyTotalFlour, FinalDough),
+			},
+			want:    []string{CheckFlourSum},
+			wantErr: true,
+		},
+		{
+			name: "duplicate and unknown dependency",
+			templates: []RecipeIngredient{
+				pct("Flour", KindFlour, 1, DependencyTotalFlour, FinalDough),
+				pct("Water", KindWater, 0.4, DependencyTotalFlour, FinalDough),
+				pct("water", KindWater, 0.3, DependencyTotalFlour, FinalDough),
+				pct("Salt", KindSalt, 0.02, "Levain Flour", FinalDough),
+			},
+			want:    []string{CheckDuplicate, CheckUnknownDependency},
+			wantErr: true,
+		},
+		{
+			name: "cycle",
+			templates: []RecipeIngredient{
+				pct("Flour", KindFlour, 1, DependencyTotalFlour, FinalDough),
+				pct("Water", KindWater, 0.7, "Salt", FinalDough),
+				pct("Salt", KindSalt, 0.02, "Water", FinalDough),
+			},
+			want:    []string{CheckCalculation},
+			wantErr: true,
+		},
+		{
+			name: "unusual baker's math",
+			templates: []RecipeIngredient{
+				pct("Flour", KindFlour, 1, DependencyTotalFlour, FinalDough),
+				pct("Water", KindWater, 1.2, DependencyTotalFlour, FinalDough),
+				pct("Starter", KindSourdough, 0.02, DependencyTotalFlour, FinalDough),
+				pct("Salt", KindSalt, 0.05, DependencyTotalFlour, FinalDough),
+				pct("Seeds", KindOther, 0.1, DependencyTotalFlour, FinalDough),
+				pct("Olive Oil", KindOther, 0.05, DependencyTotalFlour, FinalDough),
+			},
+			want: []string{CheckMissingKind, CheckMissingKind, CheckStarter, CheckHydration, CheckSalt},
+		},
+		{
+			name: "no salt",
+			templates: []RecipeIngredient{
+				pct("Flour", KindFlour, 1, DependencyTotalFlour, FinalDough),
+				pct("Water", KindWater, 0.7, DependencyTotalFlour, FinalDough),
+			},
+			want: []string{CheckSalt},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := Validate(tt.templates)
+
+			var got []string
+			for _, issue := range issues {
+				got = append(got, issue.Check)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() checks = %v, want %v, issues %v", got, tt.want, issues)
+			}
+			if HasErrors(issues) != tt.wantErr {
+				t.Errorf("HasErrors() = %v, want %v", HasErrors(issues), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIssueString(t *testing.T) {
+	issue := Issue{Severity: SeverityWarning, Check: CheckStarter, Stage: "Levain", Ingredient: "Starter", Message: "is 2.0% of the flour"}
+	if got, want := issue.String(), "warning: Levain: Starter: is 2.0% of the flour"; got != want {
+		t.Errorf("String() got = %q, want %q", got, want)
+	}
+
+	issue = Issue{Severity: SeverityWarning, Check: CheckSalt, Message: "the recipe has no salt"}
+	if got, want := issue.String(), "warning: the recipe has no salt"; got != want {
+		t.Errorf("String() got = %q, want %q", got, want)
+	}
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 
 	"github.com/jedib0t/go-pretty/v6/table"
+	"gopkg.in/yaml.v3"
 )
 
 func renderTable(w io.Writer, format OutputFormat, tw table.Writer) error {
@@ -14,9 +16,31 @@ func renderTable(w io.Writer, format OutputFormat, tw table.Writer) error {
 		data = tw.RenderHTML()
 	case OutputFormatMarkdown:
 		data = tw.RenderMarkdown()
+	case OutputFormatJSON, OutputFormatYAML:
+		return fmt.Errorf("%s output is not supported by this command", format)
 	default:
 		data = tw.Render()
 	}
 	_, err := fmt.Fprintf(w, "%s\n", data)
 	return err
 }
+
+// renderData renders v in one of the structured output formats. The
+// structures are described by output.schema.json.
+func renderData(w io.Writer, format OutputFormat, v any) error {
+	switch format {
+	case OutputFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case OutputFormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("%s is not a structured output format", format)
+	}
+}
@@ -1,15 +1,30 @@
-/* DROP TABLE IF EXISTS recipe_ingredients; */
-/* DROP TABLE IF EXISTS recipes; */
-/* DROP TABLE IF EXISTS ingredients; */
+/* The current database schema, used by sqlc to generate the query package. */
+/* Databases are created and upgraded by the files in migrations/, so any */
+/* change here must come with a new migration. */
 CREATE TABLE IF NOT EXISTS ingredients (
   id INTEGER NOT NULL PRIMARY KEY,
   name TEXT NOT NULL,
-  kind TEXT NULL
+  kind TEXT NULL,
+  hydration REAL CHECK (hydration >= 0),
+  density REAL CHECK (density > 0),
+  piece_weight REAL CHECK (piece_weight > 0)
 );
 
 CREATE TABLE IF NOT EXISTS recipes (
   id INTEGER NOT NULL PRIMARY KEY,
-  name TEXT CHECK (LENGTH(name) > 0) NOT NULL
+  name TEXT CHECK (LENGTH(name) > 0) NOT NULL,
+  notes TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS stages (
+  id INTEGER NOT NULL PRIMARY KEY,
+  recipe_id INTEGER NOT NULL,
+  name TEXT CHECK (LENGTH(name) > 0) NOT NULL,
+  position INTEGER NOT NULL,
+  UNIQUE (recipe_id, name),
+  FOREIGN KEY (recipe_id) REFERENCES recipes (
+    id
+  ) ON DELETE CASCADE
 );
 
 CREATE TABLE IF NOT EXISTS recipe_ingredients (
@@ -17,20 +32,125 @@ CREATE TABLE IF NOT EXISTS recipe_ingredients (
   recipe_id INTEGER NOT NULL,
   ingredient_id INTEGER NOT NULL,
   prefer_unit_category TEXT CHECK (prefer_unit_category IN ('weight', 'volume', 'count', 'teaspoon')) NOT NULL,
-  percentage REAL NOT NULL CHECK ((
-    percentage BETWEEN 0 AND 1
-  ) AND (
-    percentage > 0
+  percentage REAL NOT NULL CHECK (percentage >= 0),
+  dependency TEXT NOT NULL,
+  mode TEXT NOT NULL DEFAULT 'percentage' CHECK (mode IN ('percentage', 'amount')),
+  amount REAL CHECK (amount > 0),
+  unit TEXT NULL,
+  stage_id INTEGER,
+  position INTEGER NOT NULL DEFAULT 0,
+  group_name TEXT CHECK (LENGTH(group_name) > 0),
+  CHECK ((
+    mode = 'percentage' AND percentage > 0 AND LENGTH(dependency) > 0
+  ) OR (
+    mode = 'amount' AND NOT amount IS NULL AND NOT unit IS NULL
   )),
-  dependency TEXT NOT NULL CHECK (dependency IN ('total_flour')),
   FOREIGN KEY (recipe_id) REFERENCES recipes (
     id
   ) ON DELETE CASCADE,
   FOREIGN KEY (ingredient_id) REFERENCES ingredients (
     id
+  ) ON DELETE CASCADE,
+  FOREIGN KEY (stage_id) REFERENCES stages (
+    id
+  ) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS steps (
+  id INTEGER NOT NULL PRIMARY KEY,
+  recipe_id INTEGER NOT NULL,
+  position INTEGER NOT NULL,
+  text TEXT CHECK (LENGTH(text) > 0) NOT NULL,
+  duration_min INTEGER CHECK (duration_min >= 0),
+  duration_max INTEGER CHECK (duration_max >= duration_min),
+  temperature REAL,
+  temperature_scale TEXT NOT NULL DEFAULT 'C' CHECK (temperature_scale IN ('C', 'F')),
+  FOREIGN KEY (recipe_id) REFERENCES recipes (
+    id
+  ) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS step_ingredients (
+  step_id INTEGER NOT NULL,
+  recipe_ingredient_id INTEGER NOT NULL,
+  PRIMARY KEY (step_id, recipe_ingredient_id),
+  FOREIGN KEY (step_id) REFERENCES steps (
+    id
+  ) ON DELETE CASCADE,
+  FOREIGN KEY (recipe_ingredient_id) REFERENCES recipe_ingredients (
+    id
+  ) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS ferment_logs (
+  id INTEGER NOT NULL PRIMARY KEY,
+  recipe_id INTEGER,
+  stage TEXT NOT NULL CHECK (stage IN ('bulk', 'proof')),
+  temperature REAL NOT NULL,
+  temperature_scale TEXT NOT NULL DEFAULT 'C' CHECK (temperature_scale IN ('C', 'F')),
+  starter REAL NOT NULL CHECK (starter > 0),
+  flour TEXT NOT NULL DEFAULT 'white' CHECK (flour IN ('white', 'whole-wheat', 'rye')),
+  minutes INTEGER NOT NULL CHECK (minutes > 0),
+  logged_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (recipe_id) REFERENCES recipes (
+    id
+  ) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS bakes (
+  id INTEGER NOT NULL PRIMARY KEY,
+  recipe_id INTEGER,
+  recipe_name TEXT NOT NULL,
+  recipe_version TEXT NOT NULL,
+  dependency TEXT,
+  scale REAL NOT NULL DEFAULT 1 CHECK (scale > 0),
+  notes TEXT NOT NULL DEFAULT '',
+  rating INTEGER CHECK (rating BETWEEN 1 AND 5),
+  started_at DATETIME NOT NULL,
+  finished_at DATETIME,
+  FOREIGN KEY (recipe_id) REFERENCES recipes (
+    id
+  ) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS bake_logs (
+  id INTEGER NOT NULL PRIMARY KEY,
+  bake_id INTEGER NOT NULL,
+  text TEXT NOT NULL DEFAULT '',
+  stage TEXT CHECK (stage IN ('bulk', 'proof')),
+  temperature REAL,
+  temperature_scale TEXT NOT NULL DEFAULT 'C' CHECK (temperature_scale IN ('C', 'F')),
+  minutes INTEGER CHECK (minutes > 0),
+  logged_at DATETIME NOT NULL,
+  FOREIGN KEY (bake_id) REFERENCES bakes (
+    id
+  ) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS bake_photos (
+  id INTEGER NOT NULL PRIMARY KEY,
+  bake_id INTEGER NOT NULL,
+  path TEXT NOT NULL UNIQUE,
+  added_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (bake_id) REFERENCES bakes (
+    id
   ) ON DELETE CASCADE
 );
 
 CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_recipe_id ON recipe_ingredients (recipe_id);
 
-CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_ingredient_id ON recipe_ingredients (ingredient_id);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_ingredient_id ON recipe_ingredients (ingredient_id);
+
+CREATE INDEX IF NOT EXISTS idx_stages_recipe_id ON stages (recipe_id);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_stage_id ON recipe_ingredients (stage_id);
+
+CREATE INDEX IF NOT EXISTS idx_steps_recipe_id ON steps (recipe_id);
+
+CREATE INDEX IF NOT EXISTS idx_step_ingredients_recipe_ingredient_id ON step_ingredients (recipe_ingredient_id);
+
+CREATE INDEX IF NOT EXISTS idx_bakes_recipe_id ON bakes (recipe_id);
+
+CREATE INDEX IF NOT EXISTS idx_bake_logs_bake_id ON bake_logs (bake_id);
+
+CREATE INDEX IF NOT EXISTS idx_bake_photos_bake_id ON bake_photos (bake_id);
\ No newline at end of file
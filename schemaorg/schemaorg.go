@@ -0,0 +1,484 @@
+// Package schemaorg reads and writes recipes as schema.org Recipe objects in
+// JSON-LD, https://schema.org/Recipe, the format embedded in web pages by
+// most recipe sites and blogs.
+//
+// Only the properties which map to a recipe template are understood: the
+// name, description, yield, ingredients and instructions.
+package schemaorg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/simonklee/sourdough/recipe"
+	nethtml "golang.org/x/net/html"
+)
+
+// Context is the JSON-LD context of the recipes written by this package.
+const Context = "https://schema.org"
+
+// Recipe is a schema.org Recipe.
+type Recipe struct {
+	Context            string      `json:"@context,omitempty"`
+	Type               Strings     `json:"@type"`
+	Name               string      `json:"name"`
+	Description        string      `json:"description,omitempty"`
+	RecipeYield        Strings     `json:"recipeYield,omitempty"`
+	RecipeIngredient   Strings     `json:"recipeIngredient"`
+	RecipeInstructions []HowToStep `json:"recipeInstructions,omitempty"`
+}
+
+// HowToStep is a step of the instructions of a recipe.
+type HowToStep struct {
+	Type string `json:"@type"`
+	Name string `json:"name,omitempty"`
+	Text string `json:"text"`
+}
+
+// Strings is a property which may be given as a single value or a list of
+// values. A single value is written without a list.
+type Strings []string
+
+// UnmarshalJSON decodes a string, a number or a list of either.
+func (s *Strings) UnmarshalJSON(data []byte) error {
+	var values []any
+	if err := json.Unmarshal(data, &values); err != nil {
+		var value any
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		values = []any{value}
+	}
+
+	*s = nil
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			*s = append(*s, v)
+		case float64:
+			*s = append(*s, strconv.FormatFloat(v, 'f', -1, 64))
+		case nil:
+		default:
+			return fmt.Errorf("expected a string, got %s", data)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON encodes a single value as a string and several as a list.
+func (s Strings) MarshalJSON() ([]byte, error) {
+	if len(s) == 1 {
+		return json.Marshal(s[0])
+	}
+	return json.Marshal([]string(s))
+}
+
+// UnmarshalJSON decodes the instructions of a recipe. They may be given as
+// text, a list of texts, a list of HowToStep or a list of HowToSection whose
+// steps are flattened into a single list.
+func (r *Recipe) UnmarshalJSON(data []byte) error {
+	type plain Recipe
+	var v struct {
+		plain
+		RecipeInstructions json.RawMessage `json:"recipeInstructions"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*r = Recipe(v.plain)
+	steps, err := parseInstructions(v.RecipeInstructions, "")
+	if err != nil {
+		return fmt.Errorf("invalid recipeInstructions: %w", err)
+	}
+	r.RecipeInstructions = steps
+	return nil
+}
+
+func parseInstructions(data json.RawMessage, section string) ([]HowToStep, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		var steps []HowToStep
+		for _, line := range strings.Split(text, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				steps = append(steps, HowToStep{Type: "HowToStep", Name: section, Text: line})
+			}
+		}
+		return steps, nil
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err == nil {
+		var steps []HowToStep
+		for _, item := range list {
+			s, err := parseInstructions(item, section)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, s...)
+		}
+		return steps, nil
+	}
+
+	var item struct {
+		Type            Strings         `json:"@type"`
+		Name            string          `json:"name"`
+		Text            string          `json:"text"`
+		ItemListElement json.RawMessage `json:"itemListElement"`
+	}
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, err
+	}
+	if isType(item.Type, "HowToSection") {
+		return parseInstructions(item.ItemListElement, item.Name)
+	}
+
+	// A step with only a name uses it as its text, otherwise the name is
+	// the title of the step.
+	step := HowToStep{Type: "HowToStep", Name: section, Text: item.Text}
+	switch {
+	case step.Text == "":
+		step.Text = item.Name
+	case item.Name != "":
+		step.Name = item.Name
+	}
+	return []HowToStep{step}, nil
+}
+
+// isType returns true if one of types is the schema.org type name, with or
+// without a prefix such as "schema:" or "http://schema.org/".
+func isType(types []string, name string) bool {
+	for _, t := range types {
+		if i := strings.LastIndexAny(t, ":/"); i >= 0 {
+			t = t[i+1:]
+		}
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract returns the recipes of a JSON-LD document, or of the
+// application/ld+json scripts of an HTML page. Recipes are found anywhere in
+// a document, e.g. in a @graph or as the mainEntity of a WebPage.
+func Extract(r io.Reader) ([]Recipe, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+	isJSON := len(data) > 0 && (data[0] == '{' || data[0] == '[')
+
+	docs := [][]byte{data}
+	if !isJSON {
+		if docs, err = scripts(data); err != nil {
+			return nil, err
+		}
+	}
+
+	var out []Recipe
+	for _, doc := range docs {
+		var v any
+		if err := json.Unmarshal(doc, &v); err != nil {
+			// Pages often carry broken JSON-LD for other purposes, so only
+			// a JSON document must be valid.
+			if isJSON {
+				return nil, fmt.Errorf("invalid JSON-LD: %w", err)
+			}
+			continue
+		}
+
+		for _, obj := range findRecipes(v) {
+			data, err := json.Marshal(obj)
+			if err != nil {
+				return nil, err
+			}
+
+			var r Recipe
+			if err := json.Unmarshal(data, &r); err != nil {
+				return nil, fmt.Errorf("invalid recipe: %w", err)
+			}
+			out = append(out, r)
+		}
+	}
+
+	if len(out) == 0 {
+		return nil, errors.New("no schema.org Recipe found")
+	}
+	return out, nil
+}
+
+// scripts returns the contents of the application/ld+json scripts of an
+// HTML page.
+func scripts(data []byte) ([][]byte, error) {
+	var (
+		out      [][]byte
+		inScript bool
+	)
+
+	z := nethtml.NewTokenizer(bytes.NewReader(data))
+	for {
+		switch z.Next() {
+		case nethtml.ErrorToken:
+			if errors.Is(z.Err(), io.EOF) {
+				return out, nil
+			}
+			return nil, z.Err()
+		case nethtml.StartTagToken:
+			name, hasAttr := z.TagName()
+			inScript = false
+			if string(name) != "script" {
+				continue
+			}
+			for hasAttr {
+				var key, value []byte
+				key, value, hasAttr = z.TagAttr()
+				if string(key) == "type" && strings.HasPrefix(strings.ToLower(strings.TrimSpace(string(value))), "application/ld+json") {
+					inScript = true
+				}
+			}
+		case nethtml.TextToken:
+			if inScript {
+				out = append(out, bytes.Clone(z.Text()))
+			}
+		case nethtml.EndTagToken:
+			inScript = false
+		}
+	}
+}
+
+// findRecipes returns the objects of type Recipe in a decoded JSON value.
+func findRecipes(v any) []map[string]any {
+	switch v := v.(type) {
+	case map[string]any:
+		var types Strings
+		if data, err := json.Marshal(v["@type"]); err == nil {
+			_ = types.UnmarshalJSON(data)
+		}
+		if isType(types, "Recipe") {
+			return []map[string]any{v}
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var out []map[string]any
+		for _, key := range keys {
+			out = append(out, findRecipes(v[key])...)
+		}
+		return out
+	case []any:
+		var out []map[string]any
+		for _, item := range v {
+			out = append(out, findRecipes(item)...)
+		}
+		return out
+	}
+	return nil
+}
+
+// Ingredient is a parsed recipeIngredient, e.g. "500 g bread flour". An
+// amount without a unit is a number of pieces, e.g. "2 large eggs".
+type Ingredient struct {
+	Name   string
+	Amount recipe.Tuple
+}
+
+var (
+	fractions = strings.NewReplacer(
+		"½", " 1/2", "⅓", " 1/3", "⅔", " 2/3", "¼", " 1/4", "¾", " 3/4",
+		"⅛", " 1/8", "⅜", " 3/8", "⅝", " 5/8", "⅞", " 7/8", "⁄", "/",
+	)
+
+	quantityRegexp = regexp.MustCompile(`^(\d+\s+\d+/\d+|\d+/\d+|\d+(?:\.\d+)?|\.\d+)\s*(.*)$`)
+	noteRegexp     = regexp.MustCompile(`\([^)]*\)`)
+)
+
+// ParseIngredient parses an ingredient such as "500 g bread flour",
+// "1 ½ cups water (lukewarm)" or "2 eggs". The unit is parsed with
+// recipe.ParseUnit. Notes in parentheses and after a comma are dropped from
+// the name.
+func ParseIngredient(value string) (Ingredient, error) {
+	text := strings.Join(strings.Fields(fractions.Replace(html.UnescapeString(value))), " ")
+
+	matches := quantityRegexp.FindStringSubmatch(text)
+	if matches == nil {
+		return Ingredient{}, fmt.Errorf("%s has no quantity", value)
+	}
+
+	amount, err := recipe.ParseNumber(matches[1])
+	if err != nil {
+		return Ingredient{}, fmt.Errorf("invalid quantity: %s: %w", value, err)
+	}
+
+	unit, rest := parseUnit(matches[2])
+
+	name := noteRegexp.ReplaceAllString(rest, "")
+	name, _, _ = strings.Cut(name, ",")
+	name = strings.TrimSpace(name)
+	name = strings.TrimSpace(strings.TrimPrefix(name, "of "))
+	if name == "" {
+		return Ingredient{}, fmt.Errorf("%s has no name", value)
+	}
+
+	return Ingredient{Name: name, Amount: unit.Tuple(amount)}, nil
+}
+
+// parseUnit parses the unit at the start of text, which may be two words
+// such as "fl oz", and returns the rest of the text. Without a unit the
+// amount is a number of pieces.
+func parseUnit(text string) (recipe.Unit, string) {
+	words := strings.Fields(text)
+	for n := min(2, len(words)); n > 0; n-- {
+		name := strings.ToLower(strings.TrimSuffix(strings.Join(words[:n], " "), "."))
+		for _, candidate := range []string{name, name + "s"} {
+			if unit, err := recipe.ParseUnit(candidate); err == nil {
+				return unit, strings.Join(words[n:], " ")
+			}
+		}
+	}
+	return recipe.UnitPieces, text
+}
+
+// Template converts the recipe into a recipe template with baker's
+// percentages of the total flour. schema.org has no notion of stages, so all
+// ingredients belong to the final dough. Kinds are guessed from the names.
+//
+// Ingredients which can't be parsed, or whose unit isn't understood, are
+// listed in the notes together with the description and the instructions.
+func (r Recipe) Template() (recipe.Recipe, error) {
+	name := strings.TrimSpace(html.UnescapeString(r.Name))
+	if name == "" {
+		return recipe.Recipe{}, errors.New("recipe without a name")
+	}
+
+	var (
+		templates []recipe.RecipeIngredient
+		other     []string
+	)
+	for _, value := range r.RecipeIngredient {
+		ingredient, err := ParseIngredient(value)
+		if err != nil {
+			other = append(other, strings.TrimSpace(html.UnescapeString(value)))
+			continue
+		}
+
+		templates = append(templates, recipe.RecipeIngredient{
+			Name:   ingredient.Name,
+			Kind:   recipe.GuessKind(ingredient.Name),
+			Mode:   recipe.ModeAmount,
+			Amount: ingredient.Amount,
+			Stage:  recipe.FinalDough,
+		})
+	}
+
+	templates, err := recipe.ToPercentages(templates)
+	if err != nil {
+		return recipe.Recipe{}, fmt.Errorf("recipe %s: %w", name, err)
+	}
+
+	var notes []string
+	if description := strings.TrimSpace(html.UnescapeString(r.Description)); description != "" {
+		notes = append(notes, description)
+	}
+	if len(other) > 0 {
+		notes = append(notes, "Also: "+strings.Join(other, ", "))
+	}
+	for _, step := range r.RecipeInstructions {
+		if text := strings.TrimSpace(html.UnescapeString(step.Text)); text != "" {
+			notes = append(notes, text)
+		}
+	}
+
+	return recipe.Recipe{
+		Name:        name,
+		Notes:       strings.Join(notes, "\n\n"),
+		Ingredients: templates,
+	}, nil
+}
+
+// FromStages returns a recipe with the overall formula of the calculated
+// stages as its ingredients and a step for each stage. The yield is the
+// total weight of the dough.
+func FromStages(name, description string, stages []recipe.StagePortion) Recipe {
+	out := Recipe{
+		Context:          Context,
+		Type:             Strings{"Recipe"},
+		Name:             name,
+		Description:      description,
+		RecipeIngredient: Strings{},
+	}
+
+	var total float64
+	for _, portion := range recipe.Overall(stages) {
+		out.RecipeIngredient = append(out.RecipeIngredient, formatPortion(portion))
+		if v, err := portion.Value.ConvertWith(recipe.UnitGrams, portion.Properties()); err == nil {
+			total += v.Value
+		}
+	}
+	if total > 0 {
+		out.RecipeYield = Strings{formatAmount(recipe.UnitGrams.Tuple(math.Round(total)).Appropriate()) + " of dough"}
+	}
+
+	for _, stage := range stages {
+		step := HowToStep{Type: "HowToStep"}
+		if len(stages) > 1 {
+			step.Name = stage.Name
+			if stage.Name == recipe.FinalDough {
+				step.Name = "Final Dough"
+			}
+		}
+
+		portions := make([]string, 0, len(stage.Ingredients))
+		for _, portion := range stage.Ingredients {
+			portions = append(portions, formatPortion(portion))
+		}
+		step.Text = "Mix " + recipe.JoinList(portions) + "."
+
+		out.RecipeInstructions = append(out.RecipeInstructions, step)
+	}
+
+	return out
+}
+
+// formatPortion formats a calculated ingredient as it is written in a
+// recipe, e.g. "500 g Bread Flour" or "3 Egg".
+func formatPortion(portion recipe.PortionIngredient) string {
+	return formatAmount(portion.Preferred()) + " " + portion.Name
+}
+
+// formatAmount formats an amount rounded to two decimals, without a unit for
+// a number of pieces.
+func formatAmount(amount recipe.Tuple) string {
+	value := strconv.FormatFloat(math.Round(amount.Value*100)/100, 'f', -1, 64)
+	if amount.Unit == recipe.UnitPieces {
+		return value
+	}
+	return value + " " + amount.Unit.String()
+}
+
+// Write writes a recipe as JSON-LD. Characters such as "<" are escaped, so
+// the output is safe to embed in a <script type="application/ld+json">.
+func Write(w io.Writer, r Recipe) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
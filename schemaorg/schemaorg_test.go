@@ -0,0 +1,216 @@
+package schemaorg
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/simonklee/sourdough/recipe"
+)
+
+func extractFile(t *testing.T, path string) []Recipe {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	recipes, err := Extract(f)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	return recipes
+}
+
+func TestExtract(t *testing.T) {
+	recipes := extractFile(t, "testdata/focaccia.html")
+	if len(recipes) != 1 {
+		t.Fatalf("Extract() got %d recipes, want 1", len(recipes))
+	}
+
+	r := recipes[0]
+	if r.Name != "Overnight Focaccia &amp; Rosemary" {
+		t.Errorf("Extract() name = %q", r.Name)
+	}
+	if want := (Strings{"1"}); !reflect.DeepEqual(r.RecipeYield, want) {
+		t.Errorf("Extract() yield = %q, want %q", r.RecipeYield, want)
+	}
+	if len(r.RecipeIngredient) != 7 {
+		t.Errorf("Extract() ingredients = %q", r.RecipeIngredient)
+	}
+
+	want := []HowToStep{
+		{Type: "HowToStep", Name: "Day 1", Text: "Mix everything until no dry flour remains."},
+		{Type: "HowToStep", Name: "Day 1", Text: "Refrigerate overnight."},
+		{Type: "HowToStep", Text: "Bake at 230°C for 25 minutes."},
+	}
+	if !reflect.DeepEqual(r.RecipeInstructions, want) {
+		t.Errorf("Extract() instructions = %+v, want %+v", r.RecipeInstructions, want)
+	}
+}
+
+func TestExtractErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:    "invalid json",
+			input:   `{"@type": "Recipe",`,
+			wantErr: "invalid JSON-LD",
+		},
+		{
+			name:    "no recipe",
+			input:   `<html><script type="application/ld+json">{"@type": "WebPage"}</script></html>`,
+			wantErr: "no schema.org Recipe found",
+		},
+		{
+			name:    "invalid instructions",
+			input:   `{"@type": "Recipe", "name": "Bread", "recipeInstructions": 1}`,
+			wantErr: "invalid recipeInstructions",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Extract(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Extract() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseIngredient(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Ingredient
+		wantErr bool
+	}{
+		{input: "500 g bread flour", want: Ingredient{Name: "bread flour", Amount: recipe.UnitGrams.Tuple(500)}},
+		{input: "500g bread flour", want: Ingredient{Name: "bread flour", Amount: recipe.UnitGrams.Tuple(500)}},
+		{input: "1 kilogram flour", want: Ingredient{Name: "flour", Amount: recipe.UnitKilos.Tuple(1)}},
+		{input: "1 ½ cups water (lukewarm)", want: Ingredient{Name: "water", Amount: recipe.UnitCups.Tuple(1.5)}},
+		{input: "1/2 Tsp. salt", want: Ingredient{Name: "salt", Amount: recipe.UnitTeaspoons.Tuple(0.5)}},
+		{input: "2 fl oz of milk", want: Ingredient{Name: "milk", Amount: recipe.UnitFluidOunces.Tuple(2)}},
+		{input: "2 large eggs, beaten", want: Ingredient{Name: "large eggs", Amount: recipe.UnitPieces.Tuple(2)}},
+		{input: "Flaky salt, to finish", wantErr: true},
+		{input: "100 g", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseIngredient(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIngredient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseIngredient() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	got, err := extractFile(t, "testdata/focaccia.html")[0].Template()
+	if err != nil {
+		t.Fatalf("Template() error = %v", err)
+	}
+
+	if got.Name != "Overnight Focaccia & Rosemary" {
+		t.Errorf("Template() name = %q", got.Name)
+	}
+
+	want := []struct {
+		name       string
+		kind       recipe.Kind
+		percentage float64
+	}{
+		{"bread flour", recipe.KindFlour, 1},
+		{"water", recipe.KindWater, 0.8},
+		{"fine sea salt", recipe.KindSalt, 0.02},
+		{"instant yeast", recipe.KindYeast, 0},
+		{"olive oil", recipe.KindOil, 0},
+		{"egg", recipe.KindEgg, 0},
+	}
+	if len(got.Ingredients) != len(want) {
+		t.Fatalf("Template() ingredients = %+v", got.Ingredients)
+	}
+	for i, w := range want {
+		ingredient := got.Ingredients[i]
+		if ingredient.Name != w.name || ingredient.Kind != w.kind || ingredient.Stage != recipe.FinalDough {
+			t.Errorf("Template() ingredient %d = %+v, want %s of kind %s", i, ingredient, w.name, w.kind)
+		}
+		if w.percentage > 0 && math.Abs(ingredient.Percentage-w.percentage) > 1e-9 {
+			t.Errorf("Template() %s percentage = %v, want %v", w.name, ingredient.Percentage, w.percentage)
+		}
+	}
+
+	for _, s := range []string{"A bubbly", "Also: Flaky salt, to finish", "Refrigerate overnight.", "Bake at 230°C"} {
+		if !strings.Contains(got.Notes, s) {
+			t.Errorf("Template() notes = %q, want %q", got.Notes, s)
+		}
+	}
+}
+
+func TestTemplateWithoutFlour(t *testing.T) {
+	r := Recipe{Name: "Syrup", RecipeIngredient: Strings{"100 g sugar", "100 g water"}}
+	if _, err := r.Template(); err == nil || !strings.Contains(err.Error(), "no flour found") {
+		t.Errorf("Template() error = %v, want no flour found", err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	stages := []recipe.StagePortion{
+		{
+			Name: "Levain",
+			Ingredients: []recipe.PortionIngredient{
+				{Name: "Flour", Kind: recipe.KindFlour, PreferUnitCategory: recipe.UnitCategoryWeight, Value: recipe.UnitGrams.Tuple(50)},
+				{Name: "Water", Kind: recipe.KindWater, PreferUnitCategory: recipe.UnitCategoryWeight, Value: recipe.UnitGrams.Tuple(50)},
+			},
+		},
+		{
+			Name: recipe.FinalDough,
+			Ingredients: []recipe.PortionIngredient{
+				{Name: "Flour", Kind: recipe.KindFlour, PreferUnitCategory: recipe.UnitCategoryWeight, Value: recipe.UnitGrams.Tuple(450)},
+				{Name: "Water", Kind: recipe.KindWater, PreferUnitCategory: recipe.UnitCategoryWeight, Value: recipe.UnitGrams.Tuple(312.345)},
+				{Name: "Levain", Kind: recipe.KindSourdough, PreferUnitCategory: recipe.UnitCategoryWeight, Value: recipe.UnitGrams.Tuple(100)},
+				{Name: "Egg", Kind: recipe.KindEgg, PreferUnitCategory: recipe.UnitCategoryCount, Value: recipe.UnitPieces.Tuple(2)},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FromStages("Country <Loaf>", "", stages)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "<") {
+		t.Errorf("Write() output is not safe to embed: %s", buf.String())
+	}
+
+	recipes, err := Extract(&buf)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	got := recipes[0]
+	if got.Name != "Country <Loaf>" {
+		t.Errorf("Extract() name = %q", got.Name)
+	}
+	if want := (Strings{"500 g Flour", "362.35 g Water", "2 Egg"}); !reflect.DeepEqual(got.RecipeIngredient, want) {
+		t.Errorf("Extract() ingredients = %q, want %q", got.RecipeIngredient, want)
+	}
+	if want := (Strings{"962 g of dough"}); !reflect.DeepEqual(got.RecipeYield, want) {
+		t.Errorf("Extract() yield = %q, want %q", got.RecipeYield, want)
+	}
+	if len(got.RecipeInstructions) != 2 || got.RecipeInstructions[1].Name != "Final Dough" {
+		t.Errorf("Extract() instructions = %+v", got.RecipeInstructions)
+	}
+	if want := "Mix 450 g Flour, 312.35 g Water, 100 g Levain and 2 Egg."; got.RecipeInstructions[1].Text != want {
+		t.Errorf("Extract() step = %q, want %q", got.RecipeInstructions[1].Text, want)
+	}
+}
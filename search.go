@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// The search index is a FTS5 table with one row per recipe, keyed by the
+// recipe ID. It is not part of the versioned migrations since FTS5 is only
+// available in builds with -tags fts5; ensureSearchIndex creates it on
+// startup instead.
+
+// searchColumns maps the filters of a search query to the columns of the
+// search index.
+var searchColumns = map[string]string{
+	"name":       "name",
+	"ingredient": "ingredients",
+	"kind":       "kinds",
+	"notes":      "notes",
+}
+
+// searchReindex replaces the index rows of the recipes whose IDs are in the
+// given set, e.g. "(NEW.id)" or "(SELECT id FROM recipes)".
+const searchReindex = `
+DELETE FROM recipe_search WHERE rowid IN %[1]s;
+INSERT INTO recipe_search (rowid, name, ingredients, kinds, notes)
+SELECT
+  r.id,
+  r.name,
+  COALESCE((
+    SELECT group_concat(i.name, ', ')
+    FROM recipe_ingredients AS ri
+    JOIN ingredients AS i
+      ON i.id = ri.ingredient_id
+    WHERE
+      ri.recipe_id = r.id
+  ), ''),
+  COALESCE((
+    SELECT group_concat(DISTINCT i.kind)
+    FROM recipe_ingredients AS ri
+    JOIN ingredients AS i
+      ON i.id = ri.ingredient_id
+    WHERE
+      ri.recipe_id = r.id
+  ), ''),
+  r.notes
+FROM recipes AS r
+WHERE
+  r.id IN %[1]s;
+`
+
+type searchTrigger struct {
+	Name string
+	On   string
+	Body string
+}
+
+// searchTriggers keep the search index in sync with the recipes, their
+// ingredients and the ingredient catalog.
+var searchTriggers = []searchTrigger{
+	{"recipe_search_recipes_ai", "AFTER INSERT ON recipes", fmt.Sprintf(searchReindex, "(NEW.id)")},
+	{"recipe_search_recipes_au", "AFTER UPDATE ON recipes", fmt.Sprintf(searchReindex, "(OLD.id, NEW.id)")},
+	{"recipe_search_recipes_ad", "AFTER DELETE ON recipes", "DELETE FROM recipe_search WHERE rowid = OLD.id;"},
+	{"recipe_search_recipe_ingredients_ai", "AFTER INSERT ON recipe_ingredients", fmt.Sprintf(searchReindex, "(NEW.recipe_id)")},
+	{"recipe_search_recipe_ingredients_au", "AFTER UPDATE ON recipe_ingredients", fmt.Sprintf(searchReindex, "(OLD.recipe_id, NEW.recipe_id)")},
+	{"recipe_search_recipe_ingredients_ad", "AFTER DELETE ON recipe_ingredients", fmt.Sprintf(searchReindex, "(OLD.recipe_id)")},
+	{"recipe_search_ingredients_au", "AFTER UPDATE ON ingredients", fmt.Sprintf(searchReindex, "(SELECT recipe_id FROM recipe_ingredients WHERE ingredient_id = NEW.id)")},
+}
+
+// dropSearchTriggers drops the triggers of the search index. It works
+// without FTS5 support.
+func dropSearchTriggers(ctx context.Context, db *sql.DB) error {
+	for _, t := range searchTriggers {
+		if _, err := db.ExecContext(ctx, "DROP TRIGGER IF EXISTS "+t.Name); err != nil {
+			return fmt.Errorf("failed to drop trigger %s: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
+// SearchResult is a recipe matching a search query, with a snippet of the
+// best matching column. Matches in the snippet are wrapped in
+// searchMatchStart and searchMatchEnd.
+type SearchResult struct {
+	ID      int64
+	Name    string
+	Snippet string
+}
+
+// Markers wrapping the matches in a snippet, replaced when rendering.
+const (
+	searchMatchStart = "\x02"
+	searchMatchEnd   = "\x03"
+)
+
+// parseSearchQuery translates a search query into a FTS5 match expression.
+// Terms are matched as prefixes and all terms must match. Quoted terms are
+// matched as phrases, and terms can be restricted to a column with a filter,
+// e.g.
+//
+//	country kind:sourdough ingredient:"whole grain"
+func parseSearchQuery(q string) (string, error) {
+	var terms []string
+	for _, token := range splitSearchQuery(q) {
+		column := ""
+		if name, value, ok := strings.Cut(token, ":"); ok && !strings.HasPrefix(name, `"`) {
+			c, ok := searchColumns[strings.ToLower(name)]
+			if !ok {
+				return "", fmt.Errorf("unknown search filter %q, expected name, ingredient, kind or notes", name)
+			}
+			column, token = c, value
+		}
+
+		phrase := strings.HasPrefix(token, `"`)
+		token = strings.Trim(token, `"`)
+		if strings.TrimSpace(token) == "" {
+			continue
+		}
+
+		term := `"` + strings.ReplaceAll(token, `"`, `""`) + `"`
+		if !phrase {
+			term += "*"
+		}
+		if column != "" {
+			term = column + " : " + term
+		}
+		terms = append(terms, term)
+	}
+
+	if len(terms) == 0 {
+		return "", errors.New("empty search query")
+	}
+	return strings.Join(terms, " AND "), nil
+}
+
+// splitSearchQuery splits a search query on whitespace outside of double
+// quotes.
+func splitSearchQuery(q string) []string {
+	var (
+		tokens []string
+		b      strings.Builder
+		quoted bool
+	)
+	for _, r := range q {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			b.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if b.Len() > 0 {
+				tokens = append(tokens, b.String())
+				b.Reset()
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() > 0 {
+		tokens = append(tokens, b.String())
+	}
+	return tokens
+}
@@ -0,0 +1,91 @@
+//go:build fts5
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// ensureSearchIndex creates the search index and its triggers if they don't
+// exist. The index is rebuilt when any trigger was missing, e.g. after a
+// migration or after running a build without FTS5 support.
+func ensureSearchIndex(ctx context.Context, db *sql.DB) (err error) {
+	var n int
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name LIKE 'recipe\\_search\\_%' ESCAPE '\\'").Scan(&n)
+	if err != nil {
+		return err
+	}
+	if n == len(searchTriggers) {
+		return nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `CREATE VIRTUAL TABLE IF NOT EXISTS recipe_search USING fts5 (
+  name,
+  ingredients,
+  kinds,
+  notes,
+  tokenize = 'unicode61 remove_diacritics 2'
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create search index: %w", err)
+	}
+
+	for _, t := range searchTriggers {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s %s BEGIN %s END", t.Name, t.On, t.Body))
+		if err != nil {
+			return fmt.Errorf("failed to create trigger %s: %w", t.Name, err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM recipe_search; "+fmt.Sprintf(searchReindex, "(SELECT id FROM recipes)"))
+	if err != nil {
+		return fmt.Errorf("failed to build search index: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// searchRecipes returns the recipes matching the search query q, best match
+// first. Matches in names weigh the most, then ingredients, kinds and notes.
+func searchRecipes(ctx context.Context, db *sql.DB, q string) ([]SearchResult, error) {
+	match, err := parseSearchQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT
+  rowid,
+  name,
+  snippet(recipe_search, -1, ?, ?, '…', 10)
+FROM recipe_search
+WHERE
+  recipe_search MATCH ?
+ORDER BY
+  bm25(recipe_search, 10.0, 5.0, 2.0, 1.0)`, searchMatchStart, searchMatchEnd, match)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for %q: %w", q, err)
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var r SearchResult
+		if err := rows.Scan(&r.ID, &r.Name, &r.Snippet); err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,20 @@
+//go:build !fts5
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ensureSearchIndex drops the triggers of a search index created by a build
+// with FTS5 support, since they fail on every write without it. A build with
+// FTS5 support rebuilds the index when it finds them missing.
+func ensureSearchIndex(ctx context.Context, db *sql.DB) error {
+	return dropSearchTriggers(ctx, db)
+}
+
+func searchRecipes(ctx context.Context, db *sql.DB, q string) ([]SearchResult, error) {
+	return nil, errors.New("search requires a build with -tags fts5")
+}